@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AchievementComment: satu komentar di thread diskusi prestasi.
+// Thread tidak ikut dihapus saat revisi / submit ulang.
+type AchievementComment struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	AchievementID string              `bson:"achievementId" json:"achievement_id"`
+	ParentID      *primitive.ObjectID `bson:"parentId,omitempty" json:"parent_id,omitempty"` // balasan
+	AuthorID      string              `bson:"authorId" json:"author_id"`                     // users.id
+	AuthorRole    string              `bson:"authorRole" json:"author_role"`
+	Body          string              `bson:"body" json:"body"`
+	ChangeRequest *ChangeRequest      `bson:"changeRequest,omitempty" json:"change_request,omitempty"`
+	Round         int                 `bson:"round" json:"round"` // resubmission_count saat komentar dibuat
+	CreatedAt     time.Time           `bson:"createdAt" json:"created_at"`
+}
+
+// ChangeRequest: permintaan perubahan untuk satu field ATAU satu attachment
+type ChangeRequest struct {
+	Field          string `bson:"field,omitempty" json:"field,omitempty"`                // title, description, details.<key>, ...
+	AttachmentID   string `bson:"attachmentId,omitempty" json:"attachment_id,omitempty"` // attachments[].id
+	AttachmentName string `bson:"attachment,omitempty" json:"attachment_name,omitempty"` // diisi server: nama file saat diminta
+}
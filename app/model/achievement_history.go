@@ -12,4 +12,5 @@ type AchievementStatusHistory struct {
 type AchievementHistoryResponse struct {
 	Achievement Achievement                `json:"achievement"`
 	History     []AchievementStatusHistory `json:"history"`
+	Approvals   []AchievementApproval      `json:"approvals"`
 }
@@ -1,30 +1,146 @@
 package model
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Achievement struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	StudentID       string             `bson:"studentId" json:"student_id"`
-	AchievementType string             `bson:"achievementType" json:"achievementType"`
-	Title           string             `bson:"title" json:"title"`
-	Description     string             `bson:"description" json:"description"`
-	Details         map[string]any     `bson:"details" json:"details"`
-	Attachments     []Attachment       `bson:"attachments" json:"attachments"`
-	Tags            []string           `bson:"tags" json:"tags"`
-	Points          int                `bson:"points" json:"points"`
-	Status          string             `bson:"status" json:"status"`
-	IsDeleted       bool               `bson:"is_deleted" json:"is_deleted"`
-	CreatedAt       time.Time          `bson:"createdAt" json:"created_at"`
-	UpdatedAt       time.Time          `bson:"updatedAt" json:"updated_at"`
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	StudentID       string              `bson:"studentId" json:"student_id"` // pemilik / pembuat
+	Members         []AchievementMember `bson:"members,omitempty" json:"members,omitempty"`
+	AchievementType string              `bson:"achievementType" json:"achievementType"`
+	Title           string              `bson:"title" json:"title"`
+	Description     string              `bson:"description" json:"description"`
+	Details         map[string]any      `bson:"details" json:"details"`
+	Attachments     []Attachment        `bson:"attachments" json:"attachments"`
+	Tags            []string            `bson:"tags" json:"tags"`
+	Points          int                 `bson:"points" json:"points"` // dihitung server, lihat PointRule
+	PointsVersion   string              `bson:"pointsRuleVersion,omitempty" json:"points_rule_version,omitempty"`
+	PointsOverride  *PointsOverride     `bson:"pointsOverride,omitempty" json:"points_override,omitempty"`
+	TemplateID      string              `bson:"templateId,omitempty" json:"template_id,omitempty"` // dibuat dari AchievementTemplate
+	ValidFrom       *time.Time          `bson:"validFrom" json:"valid_from,omitempty"`             // sertifikasi: details.issuedDate
+	ValidUntil      *time.Time          `bson:"validUntil" json:"valid_until,omitempty"`           // sertifikasi: details.validUntil
+	RenewsID        string              `bson:"renewsId,omitempty" json:"renews_id,omitempty"`     // sertifikasi lama yang diperpanjang
+	RenewedByID     string              `bson:"renewedById,omitempty" json:"renewed_by_id,omitempty"`
+	RemindedFor     *time.Time          `bson:"expiryRemindedFor,omitempty" json:"-"` // ValidUntil yang sudah diingatkan
+	Status          string              `bson:"status" json:"status"`
+	IsDeleted       bool                `bson:"is_deleted" json:"is_deleted"`
+	DeletedAt       *time.Time          `bson:"deletedAt,omitempty" json:"deleted_at,omitempty"`
+	CreatedAt       time.Time           `bson:"createdAt" json:"created_at"`
+	UpdatedAt       time.Time           `bson:"updatedAt" json:"updated_at"`
+}
+
+// IsExpired: masa berlaku sudah lewat (berlaku sampai akhir hari ValidUntil)
+func (a *Achievement) IsExpired(now time.Time) bool {
+	return a.ValidUntil != nil && !now.Before(a.ValidUntil.AddDate(0, 0, 1))
+}
+
+// MarshalJSON: tambahkan status turunan "expired" dan URL pratinjau lampiran
+// di setiap response
+func (a Achievement) MarshalJSON() ([]byte, error) {
+	type plain Achievement
+	a.Attachments = a.attachmentsWithPreviewURLs()
+	return json.Marshal(struct {
+		plain
+		Expired bool `json:"expired"`
+	}{plain(a), a.IsExpired(time.Now())})
+}
+
+// path download lampiran (butuh JWT, akses sama dengan detail prestasi)
+const attachmentPath = "/api/v1/achievements/%s/attachments/%s"
+
+// attachmentsWithPreviewURLs: salinan lampiran dengan URL pratinjau yang siap
+func (a Achievement) attachmentsWithPreviewURLs() []Attachment {
+	if a.Attachments == nil {
+		return nil
+	}
+
+	atts := make([]Attachment, len(a.Attachments))
+	for i, att := range a.Attachments {
+		if att.Preview != nil && att.Preview.Status == PreviewReady && att.ID != "" {
+			p := *att.Preview
+			base := fmt.Sprintf(attachmentPath, a.ID.Hex(), att.ID)
+			p.ThumbnailURL = base + "?variant=" + VariantThumbnail
+			p.PreviewURL = base + "?variant=" + VariantPreview
+			att.Preview = &p
+		}
+		atts[i] = att
+	}
+	return atts
+}
+
+// prefix FileURL lampiran lama (sebelum storage); file-nya ada di root storage lokal
+const LegacyUploadPrefix = "/uploads/"
+
+// StorageKey: key lampiran di storage; lampiran lama diturunkan dari FileURL
+func (a Attachment) StorageKey() string {
+	if a.Key != "" {
+		return a.Key
+	}
+	if key, ok := strings.CutPrefix(a.FileURL, LegacyUploadPrefix); ok {
+		return key
+	}
+	return ""
+}
+
+// Matches: attachmentID = ID, atau hash untuk lampiran lama tanpa ID
+func (a Attachment) Matches(attachmentID string) bool {
+	return attachmentID != "" && (a.ID == attachmentID || (a.ID == "" && a.Hash == attachmentID))
+}
+
+// peran anggota tim
+const (
+	MemberRoleLeader = "leader"
+	MemberRoleMember = "member"
+)
+
+// AchievementMember: anggota prestasi tim (pemilik ikut tercatat)
+type AchievementMember struct {
+	StudentID string `bson:"studentId" json:"student_id"`
+	Role      string `bson:"role" json:"role"`
 }
 
 type Attachment struct {
+	ID         string    `bson:"id,omitempty" json:"id,omitempty"` // dipakai URL download
 	FileName   string    `bson:"fileName" json:"file_name"`
-	FileURL    string    `bson:"fileUrl" json:"file_url"`
-	FileType   string    `bson:"fileType" json:"file_type"`
+	Key        string    `bson:"key,omitempty" json:"key,omitempty"`          // object key di storage
+	FileURL    string    `bson:"fileUrl,omitempty" json:"file_url,omitempty"` // lama: path di ./uploads
+	FileType   string    `bson:"fileType" json:"file_type"`                   // MIME hasil sniffing isi file
+	Size       int64     `bson:"size,omitempty" json:"size,omitempty"`        // byte
+	Kind       string    `bson:"kind,omitempty" json:"kind,omitempty"`        // jenis bukti, lihat EvidenceKinds
+	Hash       string    `bson:"hash,omitempty" json:"hash,omitempty"`        // sha256 isi file
 	UploadedAt time.Time `bson:"uploadedAt" json:"uploaded_at"`
+
+	Preview *AttachmentPreview `bson:"preview,omitempty" json:"preview,omitempty"` // thumbnail & pratinjau
+}
+
+// status pembuatan pratinjau lampiran
+const (
+	PreviewPending     = "pending"
+	PreviewReady       = "ready"
+	PreviewUnavailable = "unavailable" // bukan gambar / PDF tanpa gambar di halaman pertama (teks / vektor)
+	PreviewFailed      = "failed"      // file asli hilang / decoder gagal; gangguan storage tetap pending
+)
+
+// varian file turunan lampiran (?variant= di URL download)
+const (
+	VariantThumbnail = "thumbnail"
+	VariantPreview   = "preview"
+)
+
+// AttachmentPreview: JPEG turunan lampiran, dibuat async setelah upload.
+// URL diisi saat serialisasi (Achievement.MarshalJSON), tidak disimpan.
+type AttachmentPreview struct {
+	Status       string `bson:"status" json:"status"`
+	ThumbnailKey string `bson:"thumbnailKey,omitempty" json:"-"`
+	PreviewKey   string `bson:"previewKey,omitempty" json:"-"`
+	Width        int    `bson:"width,omitempty" json:"width,omitempty"` // ukuran pratinjau (px)
+	Height       int    `bson:"height,omitempty" json:"height,omitempty"`
+	ThumbnailURL string `bson:"-" json:"thumbnail_url,omitempty"`
+	PreviewURL   string `bson:"-" json:"preview_url,omitempty"`
 }
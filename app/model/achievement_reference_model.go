@@ -11,6 +11,21 @@ type AchievementReference struct {
 	VerifiedAt         *time.Time `db:"verified_at"`
 	VerifiedBy         *string    `db:"verified_by"`
 	RejectionNote      *string    `db:"rejection_note"`
+	ResubmissionCount  int        `db:"resubmission_count"`
+	ApprovalLevel      int        `db:"approval_level"`   // jumlah tahap yang sudah disetujui
+	PendingLevel       *string    `db:"pending_level"`    // nama tahap yang sedang ditunggu
+	LevelEnteredAt     *time.Time `db:"level_entered_at"` // tahap yang ditunggu mulai (dasar SLA)
+	OverdueAt          *time.Time `db:"overdue_at"`       // pengingat SLA sudah dikirim
+	EscalatedAt        *time.Time `db:"escalated_at"`     // sudah dieskalasi ke admin
 	CreatedAt          time.Time  `db:"created_at"`
 	UpdatedAt          time.Time  `db:"updated_at"`
 }
+
+// LevelStart: awal hitungan SLA tahap yang sedang ditunggu
+// (baris lama tanpa level_entered_at → submitted_at)
+func (r *AchievementReference) LevelStart() *time.Time {
+	if r.LevelEnteredAt != nil {
+		return r.LevelEnteredAt
+	}
+	return r.SubmittedAt
+}
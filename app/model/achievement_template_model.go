@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AchievementTemplate: isian bawaan untuk kegiatan yang berulang
+// (mis. olimpiade nasional tiap tahun). Mahasiswa cukup mengisi
+// field pribadi; Details dari template tidak bisa diubah.
+type AchievementTemplate struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name                string             `bson:"name" json:"name"`
+	AchievementType     string             `bson:"achievementType" json:"achievementType"`
+	TitlePattern        string             `bson:"titlePattern" json:"title_pattern"` // placeholder {key} diambil dari details
+	Description         string             `bson:"description" json:"description"`
+	Details             map[string]any     `bson:"details" json:"details"`
+	RequiredAttachments []string           `bson:"requiredAttachments" json:"required_attachments"`
+	Tags                []string           `bson:"tags" json:"tags"`
+	IsActive            bool               `bson:"isActive" json:"is_active"`
+	CreatedBy           string             `bson:"createdBy" json:"created_by"` // users.id
+	CreatedAt           time.Time          `bson:"createdAt" json:"created_at"`
+	UpdatedAt           time.Time          `bson:"updatedAt" json:"updated_at"`
+}
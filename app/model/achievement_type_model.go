@@ -0,0 +1,91 @@
+package model
+
+// ======================= TIPE DATA FIELD =======================
+
+const (
+	FieldString  = "string"
+	FieldInteger = "integer"
+	FieldNumber  = "number"
+	FieldBoolean = "boolean"
+	FieldDate    = "date" // YYYY-MM-DD
+	FieldURL     = "url"
+	FieldArray   = "array" // list string
+)
+
+// DetailField: aturan satu key di Achievement.Details
+type DetailField struct {
+	Name     string   `json:"name"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Required bool     `json:"required"`
+	Enum     []string `json:"enum,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Aliases  []string `json:"aliases,omitempty"` // ejaan lama yang dinormalisasi ke Name
+}
+
+// AchievementTypeSchema: skema details untuk satu AchievementType
+type AchievementTypeSchema struct {
+	Type     string                `json:"type"`
+	Label    string                `json:"label"`
+	Fields   []DetailField         `json:"fields"`
+	Evidence []EvidenceRequirement `json:"evidence,omitempty"` // bukti wajib saat submit
+	Uploads  *UploadPolicy         `json:"uploads,omitempty"`  // kosong = tipe file default & batas global
+}
+
+// UploadPolicy: file lampiran yang diterima untuk satu tipe prestasi.
+// Tipe file dicek dari isi file (sniffing), bukan Content-Type dari client.
+type UploadPolicy struct {
+	FileTypes []string `json:"file_types,omitempty"`  // MIME, boleh wildcard "image/*"
+	MaxSizeMB int64    `json:"max_size_mb,omitempty"` // hanya bisa lebih kecil dari batas global
+}
+
+// ======================= BUKTI (EVIDENCE) =======================
+
+// jenis bukti, diisi saat upload attachment
+const (
+	EvidenceCertificate       = "certificate"        // sertifikat / piagam
+	EvidenceAppointmentLetter = "appointment_letter" // surat tugas / SK kepengurusan
+	EvidencePublication       = "publication"        // naskah publikasi
+	EvidencePhoto             = "photo"              // dokumentasi kegiatan
+	EvidenceOther             = "other"
+)
+
+var EvidenceKinds = []string{
+	EvidenceCertificate,
+	EvidenceAppointmentLetter,
+	EvidencePublication,
+	EvidencePhoto,
+	EvidenceOther,
+}
+
+// jenis bukti yang boleh berupa video (dokumentasi kegiatan)
+var VideoEvidenceKinds = []string{EvidencePhoto, EvidenceOther}
+
+// EvidenceOption: satu cara memenuhi requirement — attachment dengan
+// Kind (dan FileTypes, kalau diisi) ATAU field details yang terisi
+type EvidenceOption struct {
+	Kind        string   `json:"kind,omitempty"`
+	FileTypes   []string `json:"file_types,omitempty"`   // MIME, boleh wildcard "image/*"
+	DetailField string   `json:"detail_field,omitempty"` // mis. url (DOI)
+}
+
+// EvidenceRequirement: terpenuhi kalau salah satu AnyOf terpenuhi
+type EvidenceRequirement struct {
+	Name  string           `json:"name"`
+	Label string           `json:"label"`
+	AnyOf []EvidenceOption `json:"any_of"`
+}
+
+// UnmetEvidence: requirement yang belum terpenuhi, dikirim ke frontend
+type UnmetEvidence struct {
+	Requirement string           `json:"requirement"`
+	Label       string           `json:"label"`
+	AnyOf       []EvidenceOption `json:"any_of"`
+}
+
+// FieldError: error validasi per field, dikirim apa adanya ke frontend
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// kapan snapshot versi diambil
+const (
+	VersionCreate = "create"
+	VersionUpdate = "update"
+	VersionSubmit = "submit"
+
+	// lampiran dihapus / diganti
+	VersionAttachmentDelete  = "attachment_delete"
+	VersionAttachmentReplace = "attachment_replace"
+)
+
+// AchievementVersion: salinan utuh dokumen prestasi setelah create / update / submit
+// (dan hapus / ganti lampiran).
+// Tidak pernah diubah, jadi apa yang dilihat reviewer saat itu tetap bisa dibuka.
+type AchievementVersion struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AchievementID string             `bson:"achievementId" json:"achievement_id"`
+	Version       int                `bson:"version" json:"version"` // mulai dari 1
+	Reason        string             `bson:"reason" json:"reason"`
+	Round         int                `bson:"round" json:"round"` // resubmission_count saat snapshot
+	Snapshot      Achievement        `bson:"snapshot" json:"snapshot"`
+	CreatedBy     string             `bson:"createdBy" json:"created_by"` // users.id
+	CreatedAt     time.Time          `bson:"createdAt" json:"created_at"`
+}
+
+// FieldChange: satu perubahan field antar dua versi.
+// Field memakai nama JSON dokumen, details.<key> untuk isi details.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// AchievementDiff: perubahan dari versi From ke versi To
+type AchievementDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// ApprovalLevel: satu tahap verifikasi (mis. dosen wali, lalu fakultas)
+type ApprovalLevel struct {
+	Name   string          `json:"name"`
+	Actors []WorkflowActor `json:"actors"`
+}
+
+// ApprovalChain: urutan tahap verifikasi untuk tipe prestasi tertentu.
+// AchievementType kosong = berlaku untuk semua tipe.
+// When mencocokkan Details, mis. {"competitionLevel": "international"}.
+type ApprovalChain struct {
+	AchievementType string            `json:"achievementType,omitempty"`
+	When            map[string]string `json:"when,omitempty"`
+	Levels          []ApprovalLevel   `json:"levels"`
+}
+
+// AchievementApproval: persetujuan satu tahap.
+// Round = resubmission_count saat disetujui, supaya submit ulang mulai dari awal.
+type AchievementApproval struct {
+	MongoAchievementID string    `json:"achievement_id"`
+	Round              int       `json:"round"`
+	Level              int       `json:"level"`
+	LevelName          string    `json:"level_name"`
+	ApprovedBy         string    `json:"approved_by"`
+	Note               *string   `json:"note,omitempty"`
+	ApprovedAt         time.Time `json:"approved_at"`
+}
@@ -0,0 +1,27 @@
+package model
+
+// jenis ketidakcocokan antara MongoDB (achievements) dan PostgreSQL (achievement_references)
+const (
+	IssueOrphanDocument  = "orphan_document"  // dokumen MongoDB tanpa reference
+	IssueOrphanReference = "orphan_reference" // reference tanpa dokumen MongoDB
+	IssueStatusMismatch  = "status_mismatch"  // status deleted tidak sama di kedua sisi
+	IssueMemberMismatch  = "member_mismatch"  // anggota tim dokumen ≠ achievement_members
+)
+
+// ConsistencyIssue: satu temuan rekonsiliasi
+type ConsistencyIssue struct {
+	Kind            string `json:"kind"`
+	AchievementID   string `json:"achievement_id"`
+	ReferenceStatus string `json:"reference_status,omitempty"`
+	DocumentDeleted *bool  `json:"document_deleted,omitempty"`
+	Repaired        bool   `json:"repaired"`
+	Error           string `json:"error,omitempty"`
+}
+
+// ConsistencyReport: hasil satu kali rekonsiliasi
+type ConsistencyReport struct {
+	Documents  int                `json:"documents"`
+	References int                `json:"references"`
+	Skipped    int                `json:"skipped"` // masih dalam masa tenggang (mungkin sedang ditulis)
+	Issues     []ConsistencyIssue `json:"issues"`
+}
@@ -0,0 +1,23 @@
+package model
+
+// alasan kecocokan kandidat duplikat
+const (
+	DuplicateAttachment  = "same_attachment"  // isi file lampiran identik (hash)
+	DuplicateCertNumber  = "same_certificate" // nomor sertifikat sama
+	DuplicateTitle       = "same_title"       // judul sama setelah dinormalisasi
+	DuplicateSimilarName = "similar_title"    // judul mirip (kata-kata sebagian besar sama)
+	DuplicateEvent       = "same_event"       // nama kegiatan / kompetisi di details sama
+	DuplicateEventDate   = "same_event_date"  // tanggal kegiatan di details sama
+)
+
+// DuplicateCandidate: prestasi lain yang kemungkinan sama dengan yang dicek.
+// Hanya peringatan, tidak memblokir create / submit. Untuk mahasiswa, kandidat
+// milik mahasiswa lain hanya berisi skor & alasan (tanpa ID / judul).
+type DuplicateCandidate struct {
+	AchievementID string   `json:"achievement_id,omitempty"`
+	StudentID     string   `json:"student_id,omitempty"`
+	Title         string   `json:"title,omitempty"`
+	Score         float64  `json:"score"` // 0..1
+	Reasons       []string `json:"reasons"`
+	StudentIDs    []string `json:"-"` // pemilik + anggota tim kandidat
+}
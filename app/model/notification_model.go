@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// tipe notifikasi
+const (
+	NotificationAchievementRevoked = "achievement_revoked"
+	NotificationAchievementComment = "achievement_comment"
+	NotificationReviewReminder     = "review_reminder"
+	NotificationReviewEscalated    = "review_escalated"
+	NotificationCertExpiring       = "certification_expiring"
+)
+
+type Notification struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID        string             `bson:"userId" json:"user_id"` // users.id penerima
+	Type          string             `bson:"type" json:"type"`
+	Title         string             `bson:"title" json:"title"`
+	Message       string             `bson:"message" json:"message"`
+	AchievementID string             `bson:"achievementId,omitempty" json:"achievement_id,omitempty"`
+	IsRead        bool               `bson:"isRead" json:"is_read"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"created_at"`
+}
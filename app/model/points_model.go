@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// PointRule: satu baris tabel poin. Rule pertama yang cocok dipakai,
+// jadi rule yang lebih spesifik ditulis lebih dulu.
+type PointRule struct {
+	AchievementType string            `json:"achievementType"`
+	When            map[string]string `json:"when,omitempty"` // key details → nilai (case-insensitive)
+	Points          int               `json:"points"`
+	Description     string            `json:"description,omitempty"`
+}
+
+// PointsOverride: poin yang diubah verifikator, wajib dengan alasan
+type PointsOverride struct {
+	Points           int       `bson:"points" json:"points"`
+	CalculatedPoints int       `bson:"calculatedPoints" json:"calculated_points"`
+	Justification    string    `bson:"justification" json:"justification"`
+	By               string    `bson:"by" json:"by"` // users.id verifikator
+	At               time.Time `bson:"at" json:"at"`
+}
@@ -25,4 +25,5 @@ type AchievementStatisticsResponse struct {
 	TotalPerPeriod       []PeriodStat             `json:"total_per_period"`
 	CompetitionLevels    []CompetitionLevelStat   `json:"competition_level_distribution"`
 	TotalPerStudent      []StudentAchievementStat `json:"total_per_student"`
+	ExpiredExcluded      int                      `json:"expired_excluded"` // sertifikasi kedaluwarsa yang tidak dihitung
 }
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// ReviewSLA: batas waktu review per tipe prestasi, dihitung per tahap verifikasi
+// dari level_entered_at (tahap berikutnya mulai dari nol).
+// AchievementType kosong = default untuk tipe yang tidak disebut.
+type ReviewSLA struct {
+	AchievementType string `json:"achievementType"`
+	DueHours        int    `json:"dueHours"`      // lewat → overdue + pengingat ke reviewer
+	EscalateHours   int    `json:"escalateHours"` // lewat → eskalasi ke admin
+}
+
+// ReviewQueueItem: satu baris antrian review milik reviewer
+type ReviewQueueItem struct {
+	Achievement Achievement           `json:"achievement"`
+	Reference   *AchievementReference `json:"reference"`
+	AgeHours    int                   `json:"age_hours"`
+	DueAt       time.Time             `json:"due_at"`
+	EscalateAt  time.Time             `json:"escalate_at"`
+	Overdue     bool                  `json:"overdue"`
+	Escalated   bool                  `json:"escalated"`
+}
+
+// ReviewSLAReport: hasil satu kali jalan job SLA
+type ReviewSLAReport struct {
+	Checked   int `json:"checked"`
+	Overdue   int `json:"overdue"`
+	Escalated int `json:"escalated"`
+}
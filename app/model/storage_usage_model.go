@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// batas kuota penyimpanan lampiran
+const (
+	QuotaScopeStudent     = "student"
+	QuotaScopeAchievement = "achievement"
+)
+
+// StorageUsage: byte lampiran milik satu mahasiswa (ukuran asli per lampiran,
+// walaupun file identik hanya disimpan sekali). Termasuk prestasi di tempat sampah.
+type StorageUsage struct {
+	StudentID    string `bson:"_id" json:"student_id,omitempty"` // students.id
+	UsedBytes    int64  `bson:"usedBytes" json:"used_bytes"`
+	Attachments  int    `bson:"attachments" json:"attachments"`
+	Achievements int    `bson:"achievements" json:"achievements"`
+	QuotaBytes   int64  `bson:"-" json:"quota_bytes"`
+}
+
+// AchievementStorageUsage: byte lampiran satu prestasi
+type AchievementStorageUsage struct {
+	AchievementID primitive.ObjectID `bson:"_id" json:"achievement_id"`
+	StudentID     string             `bson:"studentId" json:"student_id"`
+	Title         string             `bson:"title" json:"title"`
+	UsedBytes     int64              `bson:"usedBytes" json:"used_bytes"`
+	Attachments   int                `bson:"attachments" json:"attachments"`
+	QuotaBytes    int64              `bson:"-" json:"quota_bytes"`
+}
+
+// StorageReport: laporan admin, pemakai penyimpanan terbesar di atas
+type StorageReport struct {
+	TotalBytes       int64                     `json:"total_bytes"`
+	TotalAttachments int                       `json:"total_attachments"`
+	TopStudents      []StorageUsage            `json:"top_students"`
+	TopAchievements  []AchievementStorageUsage `json:"top_achievements"`
+}
+
+// QuotaExceeded: detail response 413 saat upload melebihi kuota
+type QuotaExceeded struct {
+	Scope          string `json:"scope"` // student / achievement
+	UsedBytes      int64  `json:"used_bytes"`
+	QuotaBytes     int64  `json:"quota_bytes"`
+	RequestedBytes int64  `json:"requested_bytes"`
+}
+
+// QuotaReservation: byte yang sudah lolos cek kuota tapi lampirannya belum
+// tercatat di MongoDB (file masih dikirim ke storage). Dihitung ke kuota
+// sampai dilepas; pesanan yang kedaluwarsa (proses mati) diabaikan.
+type QuotaReservation struct {
+	ID            int64     `json:"id"`
+	StudentID     string    `json:"student_id"`
+	AchievementID string    `json:"achievement_id"`
+	Bytes         int64     `json:"bytes"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
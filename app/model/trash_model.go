@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// TrashItem: prestasi di tempat sampah + kapan dihapus permanen
+type TrashItem struct {
+	Achievement Achievement `json:"achievement"`
+	DeletedAt   time.Time   `json:"deleted_at"`
+	PurgeAt     time.Time   `json:"purge_at"`
+}
+
+// TrashPurgeReport: hasil satu kali jalan job purge
+type TrashPurgeReport struct {
+	Checked      int `json:"checked"`
+	Purged       int `json:"purged"`
+	FilesRemoved int `json:"files_removed"`
+}
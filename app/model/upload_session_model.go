@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UploadSession: upload lampiran bertahap (resumable) yang belum selesai.
+// Isi file dikirim per potongan (chunk) dan disimpan sementara di storage;
+// setelah offset = size, potongan digabung jadi satu lampiran prestasi.
+type UploadSession struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AchievementID string             `bson:"achievementId" json:"achievement_id"`
+	StudentID     string             `bson:"studentId" json:"student_id"` // pemilik prestasi (kuota)
+	FileName      string             `bson:"fileName" json:"file_name"`
+	Kind          string             `bson:"kind" json:"kind"`
+	Size          int64              `bson:"size" json:"size"`                             // total byte yang dijanjikan client
+	Offset        int64              `bson:"offset" json:"offset"`                         // byte yang sudah diterima
+	Checksum      string             `bson:"checksum,omitempty" json:"checksum,omitempty"` // sha256 (hex) seluruh file, opsional
+
+	Chunks []UploadChunk `bson:"chunks" json:"-"`
+	// 512 byte pertama (sniffing MIME) & state sha256 berjalan,
+	// supaya potongan tidak perlu dibaca ulang saat selesai
+	Head      []byte `bson:"head,omitempty" json:"-"`
+	HashState []byte `bson:"hashState,omitempty" json:"-"`
+
+	CreatedBy string    `bson:"createdBy" json:"created_by"` // users.id
+	CreatedAt time.Time `bson:"createdAt" json:"created_at"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updated_at"`
+	ExpiresAt time.Time `bson:"expiresAt" json:"expires_at"` // diperpanjang tiap potongan masuk
+	// diisi saat potongan sedang digabung jadi lampiran (satu request saja)
+	FinishingAt *time.Time `bson:"finishingAt,omitempty" json:"finishing_at,omitempty"`
+}
+
+// UploadChunk: satu potongan yang sudah tersimpan di storage
+type UploadChunk struct {
+	Key    string `bson:"key"`
+	Offset int64  `bson:"offset"`
+	Size   int64  `bson:"size"`
+}
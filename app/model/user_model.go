@@ -60,4 +60,7 @@ type UserWithProfileResponse struct {
 
 	Student  *Student  `json:"student_profile,omitempty"`
 	Lecturer *Lecturer `json:"lecturer_profile,omitempty"`
+
+	// pemakaian penyimpanan lampiran (hanya mahasiswa, di detail user)
+	StorageUsage *StorageUsage `json:"storage_usage,omitempty"`
 }
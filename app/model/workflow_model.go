@@ -0,0 +1,65 @@
+package model
+
+// ======================= STATUS PRESTASI =======================
+// status bawaan, workflow dari konfigurasi boleh menambah status lain
+
+const (
+	StatusDraft     = "draft"
+	StatusSubmitted = "submitted"
+	StatusVerified  = "verified"
+	StatusRejected  = "rejected"
+	StatusDeleted   = "deleted"
+	StatusRevoked   = "revoked"
+)
+
+// ======================= ACTION WORKFLOW =======================
+
+const (
+	ActionUpdate           = "update"
+	ActionUploadAttachment = "upload_attachment"
+	ActionDelete           = "delete"
+	ActionSubmit           = "submit"
+	ActionVerify           = "verify"
+	ActionReject           = "reject"
+	ActionRevise           = "revise"
+	ActionWithdraw         = "withdraw"
+	ActionRevoke           = "revoke"
+	ActionRestore          = "restore"
+)
+
+// ======================= RELASI ACTOR =======================
+
+const (
+	RelationOwner   = "owner"   // mahasiswa pemilik prestasi
+	RelationMember  = "member"  // pemilik atau anggota tim prestasi
+	RelationAdvisor = "advisor" // dosen wali dari pemilik / salah satu anggota tim
+)
+
+// WorkflowActor: siapa yang boleh menjalankan transisi.
+// Relation kosong = semua user dengan role tsb.
+type WorkflowActor struct {
+	Role     string `json:"role"`
+	Relation string `json:"relation,omitempty"`
+}
+
+type WorkflowTransition struct {
+	Action     string          `json:"action"`
+	From       []string        `json:"from"`
+	To         string          `json:"to"`
+	Permission string          `json:"permission,omitempty"`
+	Actors     []WorkflowActor `json:"actors"`
+}
+
+type WorkflowDefinition struct {
+	States      []string             `json:"states"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// StatusChange: satu perpindahan status, dipakai repository untuk
+// UPDATE bersyarat + mencatat riwayat dalam satu query
+type StatusChange struct {
+	From []string
+	To   string
+	By   string  // users.id yang menjalankan action
+	Note *string // alasan (reject / revise / revoke / ...)
+}
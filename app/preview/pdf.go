@@ -0,0 +1,531 @@
+package preview
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/jpeg"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// batas kedalaman rujukan / pohon halaman (PDF rusak bisa berputar)
+const maxPDFDepth = 32
+
+// firstPageImage: gambar terbesar (luas piksel) yang tertanam di halaman pertama.
+// Halaman tidak di-render; teks & grafik vektor diabaikan → ErrUnsupported kalau tidak ada gambar.
+func firstPageImage(data []byte) (image.Image, error) {
+	doc := parsePDF(data)
+
+	page, resources, ok := doc.firstPage()
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	if r, ok := doc.resolve(page["Resources"]).(pdfDict); ok {
+		resources = r
+	}
+
+	best := doc.largestImage(resources, 0)
+	if best == nil {
+		return nil, ErrUnsupported
+	}
+	return doc.decodeImage(best)
+}
+
+/* =======================
+   OBJEK PDF
+======================= */
+
+type (
+	pdfName  string
+	pdfDict  map[string]any
+	pdfArray []any
+	pdfRef   struct{ num, gen int }
+	pdfNull  struct{}
+)
+
+type pdfStream struct {
+	dict pdfDict
+	data []byte // masih terenkode (/Filter)
+}
+
+type pdfDoc struct {
+	objects map[int]any
+}
+
+var objHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// parsePDF: kumpulkan semua "N G obj" (objek yang muncul belakangan menang,
+// sesuai incremental update) lalu isi object stream (PDF 1.5+)
+func parsePDF(data []byte) *pdfDoc {
+	doc := &pdfDoc{objects: map[int]any{}}
+
+	for _, m := range objHeader.FindAllSubmatchIndex(data, -1) {
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		p := &pdfParser{data: data, pos: m[1]}
+		v := p.value(0)
+		if v == nil {
+			continue
+		}
+		if dict, ok := v.(pdfDict); ok {
+			if s, ok := p.stream(dict); ok {
+				v = s
+			}
+		}
+		doc.objects[num] = v
+	}
+
+	var objStreams []*pdfStream
+	for _, v := range doc.objects {
+		if s, ok := v.(*pdfStream); ok && s.dict["Type"] == pdfName("ObjStm") {
+			objStreams = append(objStreams, s)
+		}
+	}
+	for _, s := range objStreams {
+		doc.loadObjectStream(s)
+	}
+	return doc
+}
+
+// loadObjectStream: objek terkompresi, hanya mengisi nomor yang belum ada
+func (d *pdfDoc) loadObjectStream(s *pdfStream) {
+	data, err := d.decodeStream(s, false)
+	if err != nil {
+		return
+	}
+	n, _ := d.resolve(s.dict["N"]).(int)
+	first, _ := d.resolve(s.dict["First"]).(int)
+	if first <= 0 || first > len(data) {
+		return
+	}
+
+	header := &pdfParser{data: data[:first]}
+	for range n {
+		num, ok1 := header.value(0).(int)
+		off, ok2 := header.value(0).(int)
+		// offset negatif / di luar data → header rusak, sisa isinya tidak dipercaya
+		if !ok1 || !ok2 || off < 0 || off >= len(data)-first {
+			return
+		}
+		if _, exists := d.objects[num]; exists {
+			continue
+		}
+		p := &pdfParser{data: data, pos: first + off}
+		if v := p.value(0); v != nil {
+			d.objects[num] = v
+		}
+	}
+}
+
+func (d *pdfDoc) resolve(v any) any {
+	for range maxPDFDepth {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.objects[ref.num]
+	}
+	return nil
+}
+
+// firstPage: Catalog → Pages → Kids[0] ... sampai /Type /Page,
+// Resources diwariskan dari node Pages di atasnya
+func (d *pdfDoc) firstPage() (pdfDict, pdfDict, bool) {
+	// lebih dari satu Catalog (PDF rusak / gabungan) → nomor objek terbesar
+	var catalog pdfDict
+	catalogNum := -1
+	for num, v := range d.objects {
+		if dict, ok := v.(pdfDict); ok && dict["Type"] == pdfName("Catalog") && num > catalogNum {
+			catalog, catalogNum = dict, num
+		}
+	}
+	if catalog == nil {
+		return nil, nil, false
+	}
+
+	node, _ := d.resolve(catalog["Pages"]).(pdfDict)
+	var resources pdfDict
+	for range maxPDFDepth {
+		if node == nil {
+			return nil, nil, false
+		}
+		if r, ok := d.resolve(node["Resources"]).(pdfDict); ok {
+			resources = r
+		}
+		if node["Type"] == pdfName("Page") {
+			return node, resources, true
+		}
+		kids, _ := d.resolve(node["Kids"]).(pdfArray)
+		if len(kids) == 0 {
+			return nil, nil, false
+		}
+		node, _ = d.resolve(kids[0]).(pdfDict)
+	}
+	return nil, nil, false
+}
+
+// largestImage: XObject gambar terbesar, termasuk di dalam Form XObject
+func (d *pdfDoc) largestImage(resources pdfDict, depth int) *pdfStream {
+	if resources == nil || depth > 3 {
+		return nil
+	}
+	xobjects, _ := d.resolve(resources["XObject"]).(pdfDict)
+
+	var best *pdfStream
+	bestArea := 0
+	for _, ref := range xobjects {
+		s, ok := d.resolve(ref).(*pdfStream)
+		if !ok {
+			continue
+		}
+
+		candidate := s
+		if s.dict["Subtype"] == pdfName("Form") {
+			inner, _ := d.resolve(s.dict["Resources"]).(pdfDict)
+			candidate = d.largestImage(inner, depth+1)
+		} else if s.dict["Subtype"] != pdfName("Image") || s.dict["ImageMask"] == true {
+			continue
+		}
+		if candidate == nil {
+			continue
+		}
+
+		w, _ := d.resolve(candidate.dict["Width"]).(int)
+		h, _ := d.resolve(candidate.dict["Height"]).(int)
+		if w > 0 && h > 0 && w*h > bestArea {
+			best, bestArea = candidate, w*h
+		}
+	}
+	return best
+}
+
+/* =======================
+   DECODE GAMBAR
+======================= */
+
+func (d *pdfDoc) filters(s *pdfStream) []pdfName {
+	switch f := d.resolve(s.dict["Filter"]).(type) {
+	case pdfName:
+		return []pdfName{f}
+	case pdfArray:
+		names := []pdfName{}
+		for _, v := range f {
+			if n, ok := d.resolve(v).(pdfName); ok {
+				names = append(names, n)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// decodeStream: jalankan filter stream; kalau keepDCT, DCTDecode terakhir
+// dibiarkan (data JPEG langsung di-decode image/jpeg)
+func (d *pdfDoc) decodeStream(s *pdfStream, keepDCT bool) ([]byte, error) {
+	data := s.data
+	filters := d.filters(s)
+	for i, f := range filters {
+		switch {
+		case f == "FlateDecode":
+			if params, ok := d.resolve(s.dict["DecodeParms"]).(pdfDict); ok {
+				if p, _ := d.resolve(params["Predictor"]).(int); p > 1 {
+					return nil, ErrUnsupported
+				}
+			}
+			zr, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			// batas hasil dekompresi = batas piksel x 4 kanal
+			data, err = io.ReadAll(io.LimitReader(zr, maxPixels*4))
+			zr.Close()
+			if err != nil && err != io.ErrUnexpectedEOF {
+				return nil, err
+			}
+		case f == "DCTDecode" && keepDCT && i == len(filters)-1:
+		default:
+			return nil, ErrUnsupported
+		}
+	}
+	return data, nil
+}
+
+func (d *pdfDoc) decodeImage(s *pdfStream) (image.Image, error) {
+	w, _ := d.resolve(s.dict["Width"]).(int)
+	h, _ := d.resolve(s.dict["Height"]).(int)
+	if err := checkSize(w, h); err != nil {
+		return nil, err
+	}
+
+	data, err := d.decodeStream(s, true)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := d.filters(s)
+	if len(filters) > 0 && filters[len(filters)-1] == "DCTDecode" {
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if err := checkSize(cfg.Width, cfg.Height); err != nil {
+			return nil, err
+		}
+		return jpeg.Decode(bytes.NewReader(data))
+	}
+
+	// piksel mentah: hanya 8 bit per komponen
+	if bpc, _ := d.resolve(s.dict["BitsPerComponent"]).(int); bpc != 8 {
+		return nil, ErrUnsupported
+	}
+	n := d.components(s.dict["ColorSpace"])
+	if n == 0 || len(data) < w*h*n {
+		return nil, ErrUnsupported
+	}
+
+	rect := image.Rect(0, 0, w, h)
+	switch n {
+	case 1:
+		return &image.Gray{Pix: data[:w*h], Stride: w, Rect: rect}, nil
+	case 3:
+		img := image.NewRGBA(rect)
+		for i := range w * h {
+			img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2], img.Pix[i*4+3] = data[i*3], data[i*3+1], data[i*3+2], 0xff
+		}
+		return img, nil
+	default:
+		return &image.CMYK{Pix: data[:w*h*4], Stride: w * 4, Rect: rect}, nil
+	}
+}
+
+// components: jumlah komponen warna ColorSpace (0 = tidak didukung)
+func (d *pdfDoc) components(cs any) int {
+	switch v := d.resolve(cs).(type) {
+	case pdfName:
+		switch v {
+		case "DeviceGray", "CalGray":
+			return 1
+		case "DeviceRGB", "CalRGB":
+			return 3
+		case "DeviceCMYK":
+			return 4
+		}
+	case pdfArray:
+		// [/ICCBased stream] → /N di dict profil
+		if len(v) == 2 && d.resolve(v[0]) == pdfName("ICCBased") {
+			if s, ok := d.resolve(v[1]).(*pdfStream); ok {
+				if n, _ := d.resolve(s.dict["N"]).(int); n == 1 || n == 3 || n == 4 {
+					return n
+				}
+			}
+		}
+	}
+	return 0
+}
+
+/* =======================
+   PARSER
+======================= */
+
+type pdfParser struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isPDFDelim(c byte) bool {
+	return isPDFSpace(c) || bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+// eof: posisi di luar data (termasuk negatif dari offset rusak);
+// setiap akses p.data[p.pos] harus dijaga dengan ini
+func (p *pdfParser) eof() bool {
+	return p.pos < 0 || p.pos >= len(p.data)
+}
+
+func (p *pdfParser) skipSpace() {
+	for !p.eof() {
+		c := p.data[p.pos]
+		if c == '%' {
+			for !p.eof() && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *pdfParser) hasPrefix(s string) bool {
+	return !p.eof() && bytes.HasPrefix(p.data[p.pos:], []byte(s))
+}
+
+func (p *pdfParser) token() string {
+	if p.eof() {
+		return ""
+	}
+	start := p.pos
+	for !p.eof() && !isPDFDelim(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+// value: satu objek langsung; nil kalau tidak dikenali
+func (p *pdfParser) value(depth int) any {
+	p.skipSpace()
+	if p.eof() || depth > maxPDFDepth {
+		return nil
+	}
+
+	switch c := p.data[p.pos]; {
+	case p.hasPrefix("<<"):
+		p.pos += 2
+		dict := pdfDict{}
+		for {
+			p.skipSpace()
+			if p.eof() {
+				return dict
+			}
+			if p.hasPrefix(">>") {
+				p.pos += 2
+				return dict
+			}
+			key, ok := p.value(depth + 1).(pdfName)
+			if !ok {
+				return dict
+			}
+			dict[string(key)] = p.value(depth + 1)
+		}
+
+	case c == '[':
+		p.pos++
+		arr := pdfArray{}
+		for {
+			p.skipSpace()
+			if p.eof() {
+				return arr
+			}
+			if p.data[p.pos] == ']' {
+				p.pos++
+				return arr
+			}
+			v := p.value(depth + 1)
+			if v == nil {
+				return arr
+			}
+			arr = append(arr, v)
+		}
+
+	case c == '/':
+		p.pos++
+		return pdfName(p.token())
+
+	case c == '(':
+		return p.literalString()
+
+	case c == '<':
+		end := bytes.IndexByte(p.data[p.pos:], '>')
+		if end < 0 {
+			p.pos = len(p.data)
+			return nil
+		}
+		p.pos += end + 1
+		return ""
+
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		tok := p.token()
+		n, err := strconv.Atoi(tok)
+		if err != nil {
+			f, _ := strconv.ParseFloat(tok, 64)
+			return f
+		}
+		// "N G R" → rujukan objek
+		save := p.pos
+		p.skipSpace()
+		if gen, err := strconv.Atoi(p.token()); err == nil {
+			p.skipSpace()
+			if p.hasPrefix("R") && (p.pos+1 == len(p.data) || isPDFDelim(p.data[p.pos+1])) {
+				p.pos++
+				return pdfRef{num: n, gen: gen}
+			}
+		}
+		p.pos = save
+		return n
+
+	default:
+		switch tok := p.token(); tok {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return pdfNull{}
+		case "":
+			p.pos++ // delimiter tak terduga
+			return nil
+		default:
+			return pdfName(tok) // kata kunci lain (mis. endobj) → diabaikan pemanggil
+		}
+	}
+}
+
+// literalString: (...) dengan kurung bersarang & escape; isinya tidak dipakai
+func (p *pdfParser) literalString() any {
+	level := 0
+	for !p.eof() {
+		switch p.data[p.pos] {
+		case '\\':
+			p.pos++
+		case '(':
+			level++
+		case ')':
+			level--
+			if level == 0 {
+				p.pos++
+				return ""
+			}
+		}
+		p.pos++
+	}
+	return ""
+}
+
+// stream: data setelah "stream" sampai "endstream" (pakai /Length kalau cocok)
+func (p *pdfParser) stream(dict pdfDict) (*pdfStream, bool) {
+	p.skipSpace()
+	if !p.hasPrefix("stream") {
+		return nil, false
+	}
+	p.pos += len("stream")
+	if p.hasPrefix("\r\n") {
+		p.pos += 2
+	} else if p.hasPrefix("\n") || p.hasPrefix("\r") {
+		p.pos++
+	}
+	start := p.pos
+	if p.eof() {
+		return nil, false
+	}
+
+	if n, ok := dict["Length"].(int); ok && n >= 0 && n <= len(p.data)-start {
+		rest := bytes.TrimLeft(p.data[start+n:], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return &pdfStream{dict: dict, data: p.data[start : start+n]}, true
+		}
+	}
+
+	end := bytes.Index(p.data[start:], []byte("endstream"))
+	if end < 0 {
+		return nil, false
+	}
+	data := bytes.TrimRight(p.data[start:start+end], "\r\n")
+	return &pdfStream{dict: dict, data: data}, true
+}
@@ -0,0 +1,102 @@
+// Package preview membuat thumbnail & pratinjau lampiran (pure Go).
+// Gambar diperkecil langsung; PDF memakai gambar terbesar di halaman pertama
+// (sertifikat hasil scan), PDF tanpa gambar tidak punya pratinjau.
+package preview
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// sisi terpanjang hasil (px)
+const (
+	ThumbnailSize = 256
+	PreviewSize   = 1024
+)
+
+// batas piksel gambar sumber (cegah decompression bomb)
+const maxPixels = 40_000_000
+
+var (
+	ErrUnsupported = errors.New("preview: unsupported content")
+	ErrTooLarge    = errors.New("preview: image too large")
+)
+
+// Supported: tipe file yang bisa dibuatkan pratinjau
+func Supported(mediaType string) bool {
+	switch mediaType {
+	case "image/jpeg", "image/png", "image/webp", "application/pdf":
+		return true
+	}
+	return false
+}
+
+// Decode: gambar sumber pratinjau untuk isi file bertipe mediaType
+func Decode(data []byte, mediaType string) (image.Image, error) {
+	switch mediaType {
+	case "image/jpeg", "image/png", "image/webp":
+		return decodeImage(data)
+	case "application/pdf":
+		return firstPageImage(data)
+	}
+	return nil, ErrUnsupported
+}
+
+// decodeImage: cek ukuran dari header dulu sebelum decode penuh
+func decodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSize(cfg.Width, cfg.Height); err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+func checkSize(w, h int) error {
+	if w <= 0 || h <= 0 {
+		return ErrUnsupported
+	}
+	if w > maxPixels/h {
+		return ErrTooLarge
+	}
+	return nil
+}
+
+// Fit: perkecil supaya sisi terpanjang <= size (tidak pernah diperbesar).
+// Transparansi diratakan ke latar putih karena hasilnya JPEG.
+func Fit(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(h*size/w, 1)
+		} else {
+			w, h = max(w*size/h, 1), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+	return dst
+}
+
+// EncodeJPEG: format semua thumbnail & pratinjau
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
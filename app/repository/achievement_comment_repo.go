@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"uas-backend/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AchievementCommentRepository interface {
+	Create(ctx context.Context, comment *model.AchievementComment) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*model.AchievementComment, error)
+	FindByAchievementID(ctx context.Context, achievementID string) ([]model.AchievementComment, error)
+	DeleteByAchievementID(ctx context.Context, achievementID string) error
+}
+
+type achievementCommentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAchievementCommentRepository(db *mongo.Database) AchievementCommentRepository {
+	return &achievementCommentRepository{
+		collection: db.Collection("achievement_comments"),
+	}
+}
+
+func (r *achievementCommentRepository) Create(ctx context.Context, comment *model.AchievementComment) error {
+	res, err := r.collection.InsertOne(ctx, comment)
+	if err != nil {
+		return err
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return errors.New("failed to cast inserted ID to ObjectID")
+	}
+
+	comment.ID = oid
+	return nil
+}
+
+func (r *achievementCommentRepository) GetByID(
+	ctx context.Context,
+	id primitive.ObjectID,
+) (*model.AchievementComment, error) {
+
+	var comment model.AchievementComment
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// FindByAchievementID: urut dari komentar paling lama (urutan thread)
+func (r *achievementCommentRepository) FindByAchievementID(
+	ctx context.Context,
+	achievementID string,
+) ([]model.AchievementComment, error) {
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"achievementId": achievementID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.AchievementComment{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteByAchievementID: dipakai saat purge prestasi dari tempat sampah
+func (r *achievementCommentRepository) DeleteByAchievementID(ctx context.Context, achievementID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"achievementId": achievementID})
+	return err
+}
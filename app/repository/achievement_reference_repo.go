@@ -2,28 +2,79 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"uas-backend/app/model"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrStatusConflict: status sudah berubah duluan (race dengan request lain)
+var ErrStatusConflict = errors.New("achievement status has changed")
+
 type AchievementReferenceRepository interface {
-	CreateDraft(ctx context.Context, studentID, mongoID string) error
+	CreateDraft(ctx context.Context, studentID, mongoID, createdBy string) error
 	GetByAchievementID(ctx context.Context, achievementID string) (*model.AchievementReference, error)
-	MarkDeleted(ctx context.Context, achievementID string) error
-	Submit(ctx context.Context, achievementID string) (*model.AchievementReference, error)
-	Verify(
+	UpdateStatus(
+		ctx context.Context,
+		achievementID string,
+		change model.StatusChange,
+	) (*model.AchievementReference, error)
+	Submit(
 		ctx context.Context,
 		achievementID string,
-		verifiedBy string,
+		change model.StatusChange,
+		firstLevel string,
+	) (*model.AchievementReference, error)
+	Approve(
+		ctx context.Context,
+		achievementID string,
+		change model.StatusChange,
+		approval model.AchievementApproval,
+		nextLevel string,
 	) (*model.AchievementReference, error)
 	Reject(
 		ctx context.Context,
 		achievementID string,
-		rejectionNote string,
+		change model.StatusChange,
+	) (*model.AchievementReference, error)
+	Revise(
+		ctx context.Context,
+		achievementID string,
+		change model.StatusChange,
+		maxResubmissions int,
+	) (*model.AchievementReference, error)
+	Withdraw(
+		ctx context.Context,
+		achievementID string,
+		change model.StatusChange,
 	) (*model.AchievementReference, error)
 
 	GetByStudentID(ctx context.Context, studentID string) ([]*model.AchievementReference, error)
+	GetHistory(ctx context.Context, achievementID string) ([]model.AchievementStatusHistory, error)
+	GetApprovals(ctx context.Context, achievementID string) ([]model.AchievementApproval, error)
+	GetPending(ctx context.Context, statuses []string) ([]*model.AchievementReference, error)
+	MarkOverdue(ctx context.Context, achievementID string) (bool, error)
+	MarkEscalated(ctx context.Context, achievementID string) (bool, error)
+
+	SetMembers(ctx context.Context, achievementID string, members []model.AchievementMember) error
+	GetMemberIDs(ctx context.Context, achievementID string) ([]string, error)
+
+	Purge(ctx context.Context, achievementID string) (bool, error)
+
+	// pesanan kuota penyimpanan lampiran satu mahasiswa (dicek & dicatat di bawah kunci lintas instance)
+	ReserveStudentQuota(
+		ctx context.Context,
+		res *model.QuotaReservation,
+		check func(reserved []model.QuotaReservation) (bool, error),
+	) (bool, error)
+	ReleaseQuotaReservation(ctx context.Context, id int64) error
+
+	// konsistensi MongoDB ↔ PostgreSQL
+	GetAll(ctx context.Context) ([]*model.AchievementReference, error)
+	GetAllMembers(ctx context.Context) (map[string][]model.AchievementMember, error)
+	Discard(ctx context.Context, achievementID string) error
+	RecreateDeleted(ctx context.Context, studentID, mongoID, note string) error
 }
 
 type achievementReferenceRepository struct {
@@ -34,35 +85,8 @@ func NewAchievementReferenceRepository(db *pgxpool.Pool) AchievementReferenceRep
 	return &achievementReferenceRepository{db: db}
 }
 
-func (r *achievementReferenceRepository) CreateDraft(
-	ctx context.Context,
-	studentID string,
-	mongoID string,
-) error {
-
-	query := `
-		INSERT INTO achievement_references (
-			id, student_id, mongo_achievement_id, status
-		) VALUES (
-			gen_random_uuid(), $1, $2, 'draft'
-		)
-	`
-
-	_, err := r.db.Exec(ctx, query, studentID, mongoID)
-	return err
-}
-
-func (r *achievementReferenceRepository) GetByAchievementID(
-	ctx context.Context,
-	achievementID string,
-) (*model.AchievementReference, error) {
-
-	var ref model.AchievementReference
-
-	err := r.db.QueryRow(
-		ctx,
-		`
-		SELECT
+// kolom standar achievement_references (urutan harus sama dengan scanReference)
+const referenceColumns = `
 			id,
 			student_id,
 			mongo_achievement_id,
@@ -71,13 +95,19 @@ func (r *achievementReferenceRepository) GetByAchievementID(
 			verified_at,
 			verified_by,
 			rejection_note,
+			resubmission_count,
+			approval_level,
+			pending_level,
+			level_entered_at,
+			overdue_at,
+			escalated_at,
 			created_at,
 			updated_at
-		FROM achievement_references
-		WHERE mongo_achievement_id = $1
-		`,
-		achievementID,
-	).Scan(
+`
+
+func scanReference(row pgx.Row) (*model.AchievementReference, error) {
+	var ref model.AchievementReference
+	err := row.Scan(
 		&ref.ID,
 		&ref.StudentID,
 		&ref.MongoAchievementID,
@@ -86,204 +116,659 @@ func (r *achievementReferenceRepository) GetByAchievementID(
 		&ref.VerifiedAt,
 		&ref.VerifiedBy,
 		&ref.RejectionNote,
+		&ref.ResubmissionCount,
+		&ref.ApprovalLevel,
+		&ref.PendingLevel,
+		&ref.LevelEnteredAt,
+		&ref.OverdueAt,
+		&ref.EscalatedAt,
 		&ref.CreatedAt,
 		&ref.UpdatedAt,
 	)
-
 	if err != nil {
 		return nil, err
 	}
-
 	return &ref, nil
 }
 
-func (r *achievementReferenceRepository) MarkDeleted(ctx context.Context, achievementID string) error {
+// scanTransition: UPDATE ... WHERE status = ANY(from) yang tidak kena baris
+// berarti status sudah berubah → ErrStatusConflict
+func scanTransition(row pgx.Row) (*model.AchievementReference, error) {
+	ref, err := scanReference(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrStatusConflict
+	}
+	return ref, err
+}
+
+// transitionQuery: UPDATE bersyarat status + INSERT riwayat dalam satu query.
+// $1 = mongo_achievement_id, $2 = status tujuan, $3 = status asal,
+// $4 = note, $5 = changed_by; parameter tambahan mulai dari $6.
+func transitionQuery(extraSet, extraWhere string) string {
+	return `
+		WITH updated AS (
+			UPDATE achievement_references
+			SET
+				status = $2,` + extraSet + `
+				updated_at = NOW()
+			WHERE mongo_achievement_id = $1
+			  AND status::text = ANY($3::text[])` + extraWhere + `
+			RETURNING` + referenceColumns + `
+		), logged AS (
+			INSERT INTO achievement_status_history (
+				mongo_achievement_id, status, note, changed_by
+			)
+			SELECT mongo_achievement_id, status, $4, $5::uuid
+			FROM updated
+		)
+		SELECT` + referenceColumns + `
+		FROM updated
+	`
+}
+
+func transitionArgs(achievementID string, change model.StatusChange, extra ...any) []any {
+	args := []any{
+		achievementID,
+		change.To,
+		change.From,
+		change.Note,
+		nullableString(change.By),
+	}
+	return append(args, extra...)
+}
+
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func (r *achievementReferenceRepository) CreateDraft(
+	ctx context.Context,
+	studentID string,
+	mongoID string,
+	createdBy string,
+) error {
+
 	query := `
-		UPDATE achievement_references
-		SET status = 'deleted', updated_at = NOW()
-		WHERE mongo_achievement_id = $1
+		WITH created AS (
+			INSERT INTO achievement_references (
+				id, student_id, mongo_achievement_id, status
+			) VALUES (
+				gen_random_uuid(), $1, $2, $3
+			)
+			RETURNING mongo_achievement_id, status
+		)
+		INSERT INTO achievement_status_history (
+			mongo_achievement_id, status, changed_by
+		)
+		SELECT mongo_achievement_id, status, $4::uuid
+		FROM created
 	`
-	_, err := r.db.Exec(ctx, query, achievementID)
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		studentID,
+		mongoID,
+		model.StatusDraft,
+		nullableString(createdBy),
+	)
 	return err
 }
 
+func (r *achievementReferenceRepository) GetByAchievementID(
+	ctx context.Context,
+	achievementID string,
+) (*model.AchievementReference, error) {
+
+	return scanReference(r.db.QueryRow(
+		ctx,
+		`
+		SELECT`+referenceColumns+`
+		FROM achievement_references
+		WHERE mongo_achievement_id = $1
+		`,
+		achievementID,
+	))
+}
+
+func (r *achievementReferenceRepository) UpdateStatus(
+	ctx context.Context,
+	achievementID string,
+	change model.StatusChange,
+) (*model.AchievementReference, error) {
+
+	return scanTransition(r.db.QueryRow(
+		ctx,
+		transitionQuery("", ""),
+		transitionArgs(achievementID, change)...,
+	))
+}
+
+// Submit: mulai dari tahap verifikasi pertama
 func (r *achievementReferenceRepository) Submit(
 	ctx context.Context,
 	achievementID string,
+	change model.StatusChange,
+	firstLevel string,
 ) (*model.AchievementReference, error) {
 
-	query := `
+	return scanTransition(r.db.QueryRow(
+		ctx,
+		transitionQuery(`
+				submitted_at = NOW(),
+				approval_level = 0,
+				pending_level = $6,
+				level_entered_at = NOW(),
+				overdue_at = NULL,
+				escalated_at = NULL,`, ""),
+		transitionArgs(achievementID, change, firstLevel)...,
+	))
+}
+
+// Approve: catat persetujuan satu tahap dalam satu transaksi.
+// nextLevel kosong = tahap terakhir → status berubah ke change.To (verified).
+// Guard approval_level + status mencegah dua reviewer menyetujui tahap yang sama.
+func (r *achievementReferenceRepository) Approve(
+	ctx context.Context,
+	achievementID string,
+	change model.StatusChange,
+	approval model.AchievementApproval,
+	nextLevel string,
+) (*model.AchievementReference, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1️⃣ naikkan tahap; SLA tahap berikutnya mulai dari nol
+	tag, err := tx.Exec(
+		ctx,
+		`
 		UPDATE achievement_references
 		SET
-			status = 'submitted',
-			submitted_at = NOW(),
+			approval_level = approval_level + 1,
+			pending_level = $3,
+			level_entered_at = NOW(),
+			overdue_at = NULL,
+			escalated_at = NULL,
 			updated_at = NOW()
 		WHERE mongo_achievement_id = $1
-		  AND status = 'draft'
-		RETURNING
-			id,
-			student_id,
-			mongo_achievement_id,
-			status,
-			submitted_at,
-			verified_at,
-			verified_by,
-			rejection_note,
-			created_at,
-			updated_at
-	`
-
-	var ref model.AchievementReference
-	err := r.db.QueryRow(ctx, query, achievementID).Scan(
-		&ref.ID,
-		&ref.StudentID,
-		&ref.MongoAchievementID,
-		&ref.Status,
-		&ref.SubmittedAt,
-		&ref.VerifiedAt,
-		&ref.VerifiedBy,
-		&ref.RejectionNote,
-		&ref.CreatedAt,
-		&ref.UpdatedAt,
+		  AND approval_level = $2
+		  AND status::text = ANY($4::text[])
+		`,
+		achievementID,
+		approval.Level,
+		nullableString(nextLevel),
+		change.From,
 	)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrStatusConflict
+	}
 
+	// 2️⃣ simpan persetujuan tahap ini
+	if _, err := tx.Exec(
+		ctx,
+		`
+		INSERT INTO achievement_approvals (
+			mongo_achievement_id, round, level, level_name, approved_by, note
+		) VALUES ($1, $2, $3, $4, $5::uuid, $6)
+		`,
+		achievementID,
+		approval.Round,
+		approval.Level,
+		approval.LevelName,
+		approval.ApprovedBy,
+		approval.Note,
+	); err != nil {
+		return nil, err
+	}
+
+	// 3️⃣ tahap terakhir → verified, selain itu cukup baca ulang reference
+	var ref *model.AchievementReference
+	if nextLevel == "" {
+		ref, err = scanTransition(tx.QueryRow(
+			ctx,
+			transitionQuery(`
+				verified_at = NOW(),
+				verified_by = $6,`, ""),
+			transitionArgs(achievementID, change, change.By)...,
+		))
+	} else {
+		ref, err = scanReference(tx.QueryRow(
+			ctx,
+			`
+			SELECT`+referenceColumns+`
+			FROM achievement_references
+			WHERE mongo_achievement_id = $1
+			`,
+			achievementID,
+		))
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &ref, nil
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
+
+func (r *achievementReferenceRepository) Reject(
+	ctx context.Context,
+	achievementID string,
+	change model.StatusChange,
+) (*model.AchievementReference, error) {
+
+	return scanTransition(r.db.QueryRow(
+		ctx,
+		transitionQuery(`
+				rejection_note = $4,`, ""),
+		transitionArgs(achievementID, change)...,
+	))
 }
 
-func (r *achievementReferenceRepository) Verify(
+// Revise: rejected → editable lagi. rejection_note TIDAK dihapus supaya
+// mahasiswa tetap bisa lihat alasan penolakan sebelumnya.
+func (r *achievementReferenceRepository) Revise(
 	ctx context.Context,
 	achievementID string,
-	verifiedBy string,
+	change model.StatusChange,
+	maxResubmissions int,
 ) (*model.AchievementReference, error) {
 
-	query := `
-		UPDATE achievement_references
-		SET
-			status = 'verified',
-			verified_at = NOW(),
-			verified_by = $2,
-			updated_at = NOW()
+	return scanTransition(r.db.QueryRow(
+		ctx,
+		transitionQuery(`
+				resubmission_count = resubmission_count + 1,`, `
+			  AND resubmission_count < $6`),
+		transitionArgs(achievementID, change, maxResubmissions)...,
+	))
+}
+
+// Withdraw: submitted → draft selama belum ada reviewer yang bertindak.
+// Kondisi dicek di WHERE yang sama dengan UPDATE, jadi kalau Verify/Reject
+// menang duluan, query ini tidak kena baris → ErrStatusConflict.
+func (r *achievementReferenceRepository) Withdraw(
+	ctx context.Context,
+	achievementID string,
+	change model.StatusChange,
+) (*model.AchievementReference, error) {
+
+	return scanTransition(r.db.QueryRow(
+		ctx,
+		transitionQuery(`
+				submitted_at = NULL,
+				pending_level = NULL,
+				level_entered_at = NULL,
+				overdue_at = NULL,
+				escalated_at = NULL,`, `
+			  AND verified_by IS NULL
+			  AND approval_level = 0`),
+		transitionArgs(achievementID, change)...,
+	))
+}
+
+func (r *achievementReferenceRepository) GetByStudentID(
+	ctx context.Context,
+	studentID string,
+) ([]*model.AchievementReference, error) {
+
+	rows, err := r.db.Query(
+		ctx,
+		`
+		SELECT`+referenceColumns+`
+		FROM achievement_references
+		WHERE student_id = $1
+		   OR EXISTS (
+			SELECT 1 FROM achievement_members m
+			WHERE m.mongo_achievement_id = achievement_references.mongo_achievement_id
+			  AND m.student_id = $1
+		   )
+		`,
+		studentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []*model.AchievementReference
+	for rows.Next() {
+		ref, err := scanReference(rows)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
+
+func (r *achievementReferenceRepository) GetHistory(
+	ctx context.Context,
+	achievementID string,
+) ([]model.AchievementStatusHistory, error) {
+
+	rows, err := r.db.Query(
+		ctx,
+		`
+		SELECT status, note, changed_at, changed_by::text
+		FROM achievement_status_history
 		WHERE mongo_achievement_id = $1
-		  AND status = 'submitted'
-		RETURNING
-			id,
-			student_id,
-			mongo_achievement_id,
-			status,
-			submitted_at,
-			verified_at,
-			verified_by,
-			rejection_note,
-			created_at,
-			updated_at
-	`
+		ORDER BY changed_at, id
+		`,
+		achievementID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	var ref model.AchievementReference
-	err := r.db.QueryRow(
+	history := []model.AchievementStatusHistory{}
+	for rows.Next() {
+		var h model.AchievementStatusHistory
+		if err := rows.Scan(&h.Status, &h.Note, &h.UpdatedAt, &h.UpdatedBy); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+
+	return history, rows.Err()
+}
+
+func (r *achievementReferenceRepository) GetApprovals(
+	ctx context.Context,
+	achievementID string,
+) ([]model.AchievementApproval, error) {
+
+	rows, err := r.db.Query(
 		ctx,
-		query,
+		`
+		SELECT mongo_achievement_id, round, level, level_name,
+		       approved_by::text, note, approved_at
+		FROM achievement_approvals
+		WHERE mongo_achievement_id = $1
+		ORDER BY round, level
+		`,
 		achievementID,
-		verifiedBy,
-	).Scan(
-		&ref.ID,
-		&ref.StudentID,
-		&ref.MongoAchievementID,
-		&ref.Status,
-		&ref.SubmittedAt,
-		&ref.VerifiedAt,
-		&ref.VerifiedBy,
-		&ref.RejectionNote,
-		&ref.CreatedAt,
-		&ref.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	approvals := []model.AchievementApproval{}
+	for rows.Next() {
+		var a model.AchievementApproval
+		if err := rows.Scan(
+			&a.MongoAchievementID,
+			&a.Round,
+			&a.Level,
+			&a.LevelName,
+			&a.ApprovedBy,
+			&a.Note,
+			&a.ApprovedAt,
+		); err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, a)
+	}
+
+	return approvals, rows.Err()
+}
 
+// GetPending: semua prestasi yang sedang menunggu tahap verifikasi mana pun
+func (r *achievementReferenceRepository) GetPending(
+	ctx context.Context,
+	statuses []string,
+) ([]*model.AchievementReference, error) {
+
+	rows, err := r.db.Query(
+		ctx,
+		`
+		SELECT`+referenceColumns+`
+		FROM achievement_references
+		WHERE pending_level IS NOT NULL
+		  AND status::text = ANY($1::text[])
+		ORDER BY level_entered_at ASC
+		`,
+		statuses,
+	)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return &ref, nil
+	var refs []*model.AchievementReference
+	for rows.Next() {
+		ref, err := scanReference(rows)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
 }
 
-func (r *achievementReferenceRepository) Reject(
+// MarkOverdue: tandai lewat SLA; false kalau sudah ditandai sebelumnya
+// (job lain / run sebelumnya) supaya pengingat tidak terkirim dua kali
+func (r *achievementReferenceRepository) MarkOverdue(
 	ctx context.Context,
 	achievementID string,
-	rejectionNote string,
-) (*model.AchievementReference, error) {
+) (bool, error) {
 
-	query := `
+	tag, err := r.db.Exec(
+		ctx,
+		`
 		UPDATE achievement_references
-		SET
-			status = 'rejected',
-			rejection_note = $2,
-			updated_at = NOW()
+		SET overdue_at = NOW()
 		WHERE mongo_achievement_id = $1
-		  AND status = 'submitted'
-		RETURNING
-			id,
-			student_id,
-			mongo_achievement_id,
-			status,
-			submitted_at,
-			verified_at,
-			verified_by,
-			rejection_note,
-			created_at,
-			updated_at
-	`
+		  AND overdue_at IS NULL
+		  AND pending_level IS NOT NULL
+		`,
+		achievementID,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
 
-	var ref model.AchievementReference
-	err := r.db.QueryRow(
+// MarkEscalated: sama seperti MarkOverdue untuk batas eskalasi
+func (r *achievementReferenceRepository) MarkEscalated(
+	ctx context.Context,
+	achievementID string,
+) (bool, error) {
+
+	tag, err := r.db.Exec(
 		ctx,
-		query,
+		`
+		UPDATE achievement_references
+		SET escalated_at = NOW()
+		WHERE mongo_achievement_id = $1
+		  AND escalated_at IS NULL
+		  AND pending_level IS NOT NULL
+		`,
 		achievementID,
-		rejectionNote,
-	).Scan(
-		&ref.ID,
-		&ref.StudentID,
-		&ref.MongoAchievementID,
-		&ref.Status,
-		&ref.SubmittedAt,
-		&ref.VerifiedAt,
-		&ref.VerifiedBy,
-		&ref.RejectionNote,
-		&ref.CreatedAt,
-		&ref.UpdatedAt,
 	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetMembers: ganti seluruh daftar anggota tim dalam satu transaksi.
+// members kosong → prestasi individu (tidak ada baris anggota).
+func (r *achievementReferenceRepository) SetMembers(
+	ctx context.Context,
+	achievementID string,
+	members []model.AchievementMember,
+) error {
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(
+		ctx,
+		`DELETE FROM achievement_members WHERE mongo_achievement_id = $1`,
+		achievementID,
+	); err != nil {
+		return err
+	}
+
+	for _, m := range members {
+		if _, err := tx.Exec(
+			ctx,
+			`
+			INSERT INTO achievement_members (mongo_achievement_id, student_id, role)
+			VALUES ($1, $2, $3)
+			`,
+			achievementID,
+			m.StudentID,
+			m.Role,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetMemberIDs: student_id seluruh anggota tim (kosong untuk prestasi individu)
+func (r *achievementReferenceRepository) GetMemberIDs(
+	ctx context.Context,
+	achievementID string,
+) ([]string, error) {
+
+	rows, err := r.db.Query(
+		ctx,
+		`
+		SELECT student_id
+		FROM achievement_members
+		WHERE mongo_achievement_id = $1
+		ORDER BY student_id
+		`,
+		achievementID,
+	)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return &ref, nil
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
 }
 
-func (r *achievementReferenceRepository) GetByStudentID(
+// Purge: hapus permanen reference berstatus deleted beserta riwayat,
+// approval, dan anggota tim. true = tidak ada lagi reference aktif
+// (terhapus sekarang atau memang sudah tidak ada); false = prestasi
+// sudah dipulihkan sehingga tidak boleh di-purge.
+// Baris reference dikunci (FOR UPDATE) sampai commit: Restore yang datang
+// bersamaan menunggu lalu gagal, bukan dipulihkan lalu ikut terhapus.
+func (r *achievementReferenceRepository) Purge(
+	ctx context.Context,
+	achievementID string,
+) (bool, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(
+		ctx,
+		`
+		SELECT status::text
+		FROM achievement_references
+		WHERE mongo_achievement_id = $1
+		FOR UPDATE
+		`,
+		achievementID,
+	).Scan(&status)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return false, err
+	}
+	if err == nil && status != model.StatusDeleted {
+		return false, nil
+	}
+
+	if err := deleteReferenceRows(ctx, tx, achievementID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// Discard: hapus reference apa pun statusnya (kompensasi create yang
+// gagal di tengah jalan / reference tanpa dokumen MongoDB)
+func (r *achievementReferenceRepository) Discard(
+	ctx context.Context,
+	achievementID string,
+) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := deleteReferenceRows(ctx, tx, achievementID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// deleteReferenceRows: reference + semua tabel turunannya
+func deleteReferenceRows(ctx context.Context, tx pgx.Tx, achievementID string) error {
+	for _, q := range []string{
+		`DELETE FROM achievement_status_history WHERE mongo_achievement_id = $1`,
+		`DELETE FROM achievement_approvals WHERE mongo_achievement_id = $1`,
+		`DELETE FROM achievement_members WHERE mongo_achievement_id = $1`,
+		`DELETE FROM achievement_references WHERE mongo_achievement_id = $1`,
+	} {
+		if _, err := tx.Exec(ctx, q, achievementID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAll: semua reference (termasuk deleted), untuk rekonsiliasi
+func (r *achievementReferenceRepository) GetAll(
 	ctx context.Context,
-	studentID string,
 ) ([]*model.AchievementReference, error) {
 
 	rows, err := r.db.Query(
 		ctx,
 		`
-		SELECT
-			id,
-			student_id,
-			mongo_achievement_id,
-			status,
-			submitted_at,
-			verified_at,
-			verified_by,
-			rejection_note,
-			created_at,
-			updated_at
+		SELECT`+referenceColumns+`
 		FROM achievement_references
-		WHERE student_id = $1
+		ORDER BY created_at ASC
 		`,
-		studentID,
 	)
 	if err != nil {
 		return nil, err
@@ -292,23 +777,168 @@ func (r *achievementReferenceRepository) GetByStudentID(
 
 	var refs []*model.AchievementReference
 	for rows.Next() {
-		ref := new(model.AchievementReference)
-		if err := rows.Scan(
-			&ref.ID,
-			&ref.StudentID,
-			&ref.MongoAchievementID,
-			&ref.Status,
-			&ref.SubmittedAt,
-			&ref.VerifiedAt,
-			&ref.VerifiedBy,
-			&ref.RejectionNote,
-			&ref.CreatedAt,
-			&ref.UpdatedAt,
-		); err != nil {
+		ref, err := scanReference(rows)
+		if err != nil {
 			return nil, err
 		}
 		refs = append(refs, ref)
 	}
 
-	return refs, nil
+	return refs, rows.Err()
+}
+
+// GetAllMembers: anggota tim semua prestasi (key = mongo_achievement_id), untuk rekonsiliasi
+func (r *achievementReferenceRepository) GetAllMembers(
+	ctx context.Context,
+) (map[string][]model.AchievementMember, error) {
+
+	rows, err := r.db.Query(
+		ctx,
+		`
+		SELECT mongo_achievement_id, student_id, role
+		FROM achievement_members
+		ORDER BY mongo_achievement_id, student_id
+		`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[string][]model.AchievementMember{}
+	for rows.Next() {
+		var id string
+		var m model.AchievementMember
+		if err := rows.Scan(&id, &m.StudentID, &m.Role); err != nil {
+			return nil, err
+		}
+		result[id] = append(result[id], m)
+	}
+
+	return result, rows.Err()
+}
+
+// RecreateDeleted: buat ulang reference yang hilang untuk dokumen MongoDB
+// (rekonsiliasi) langsung berstatus deleted, supaya dokumennya masuk tempat
+// sampah: bisa dipulihkan pemiliknya, atau dihapus permanen oleh purge.
+func (r *achievementReferenceRepository) RecreateDeleted(
+	ctx context.Context,
+	studentID string,
+	mongoID string,
+	note string,
+) error {
+
+	query := `
+		WITH created AS (
+			INSERT INTO achievement_references (
+				id, student_id, mongo_achievement_id, status
+			) VALUES (
+				gen_random_uuid(), $1, $2, $3
+			)
+			RETURNING mongo_achievement_id, status
+		)
+		INSERT INTO achievement_status_history (
+			mongo_achievement_id, status, note
+		)
+		SELECT mongo_achievement_id, status, $4
+		FROM created
+	`
+
+	_, err := r.db.Exec(ctx, query, studentID, mongoID, model.StatusDeleted, note)
+	return err
+}
+
+// quotaLockTimeout: batas tunggu advisory lock kuota; cek + pencatatan pesanan
+// hanya beberapa query, jadi menunggu lebih lama berarti ada yang macet
+const quotaLockTimeout = "5s"
+
+// ReserveStudentQuota: satu transaksi pendek di bawah advisory lock per mahasiswa
+// (dilepas saat commit / rollback): baca pesanan yang masih berlaku, jalankan check,
+// lalu catat res kalau check mengizinkan (res.ID terisi). Koneksi sudah kembali ke
+// pool saat pemanggil mengirim file ke storage.
+func (r *achievementReferenceRepository) ReserveStudentQuota(
+	ctx context.Context,
+	res *model.QuotaReservation,
+	check func(reserved []model.QuotaReservation) (bool, error),
+) (bool, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SET LOCAL lock_timeout = '`+quotaLockTimeout+`'`); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(
+		ctx,
+		`SELECT pg_advisory_xact_lock(hashtext('attachment_quota:' || $1))`,
+		res.StudentID,
+	); err != nil {
+		return false, err
+	}
+
+	// pesanan kedaluwarsa (proses mati sebelum melepas) dibersihkan sekalian
+	if _, err := tx.Exec(
+		ctx,
+		`DELETE FROM attachment_quota_reservations WHERE student_id = $1 AND expires_at <= NOW()`,
+		res.StudentID,
+	); err != nil {
+		return false, err
+	}
+
+	rows, err := tx.Query(
+		ctx,
+		`
+		SELECT id, student_id, mongo_achievement_id, bytes, expires_at
+		FROM attachment_quota_reservations
+		WHERE student_id = $1
+		`,
+		res.StudentID,
+	)
+	if err != nil {
+		return false, err
+	}
+	reserved := []model.QuotaReservation{}
+	for rows.Next() {
+		var q model.QuotaReservation
+		if err := rows.Scan(&q.ID, &q.StudentID, &q.AchievementID, &q.Bytes, &q.ExpiresAt); err != nil {
+			rows.Close()
+			return false, err
+		}
+		reserved = append(reserved, q)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	ok, err := check(reserved)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if err := tx.QueryRow(
+		ctx,
+		`
+		INSERT INTO attachment_quota_reservations
+			(student_id, mongo_achievement_id, bytes, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+		`,
+		res.StudentID,
+		res.AchievementID,
+		res.Bytes,
+		res.ExpiresAt,
+	).Scan(&res.ID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+func (r *achievementReferenceRepository) ReleaseQuotaReservation(ctx context.Context, id int64) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM attachment_quota_reservations WHERE id = $1`, id)
+	return err
 }
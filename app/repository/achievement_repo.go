@@ -16,12 +16,51 @@ import (
 type AchievementRepository interface {
 	Create(ctx context.Context, a *model.Achievement) (primitive.ObjectID, error)
 	GetByID(ctx context.Context, id primitive.ObjectID) (*model.Achievement, error)
-	AddAttachment(ctx context.Context, id primitive.ObjectID, att model.Attachment) error
+	AddAttachment(ctx context.Context, id primitive.ObjectID, att model.Attachment) (bool, error)
+	RemoveAttachment(ctx context.Context, id primitive.ObjectID, attachmentID string) (bool, error)
+	ReplaceAttachment(ctx context.Context, id primitive.ObjectID, attachmentID string, att model.Attachment) (bool, error)
 	Update(ctx context.Context, a *model.Achievement) error
+	SetSubmission(ctx context.Context, a *model.Achievement) error
+	SetPoints(ctx context.Context, a *model.Achievement) error
+	SetRecalculatedPoints(ctx context.Context, a *model.Achievement, fromVersion string) (bool, error)
 	SoftDelete(ctx context.Context, id primitive.ObjectID) error
 	FindByStudentIDs(ctx context.Context, studentIDs []string) ([]model.Achievement, error)
+	FindByAttachmentHashes(ctx context.Context, hashes []string) ([]model.Achievement, error)
 	FindAll(ctx context.Context) ([]model.Achievement, error)
 	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Achievement, error)
+
+	// tempat sampah
+	FindDeleted(ctx context.Context, studentIDs []string) ([]model.Achievement, error)
+	FindDeletedBefore(ctx context.Context, cutoff time.Time) ([]model.Achievement, error)
+	Restore(ctx context.Context, id primitive.ObjectID) error
+	HardDelete(ctx context.Context, id primitive.ObjectID) error
+	AttachmentInUse(ctx context.Context, key string, excludeID primitive.ObjectID) (bool, error)
+
+	// konsistensi MongoDB ↔ PostgreSQL
+	Remove(ctx context.Context, id primitive.ObjectID) error
+	FindSyncStates(ctx context.Context) ([]model.Achievement, error)
+	SyncStatus(ctx context.Context, id primitive.ObjectID, status string) error
+	SyncMembers(ctx context.Context, id primitive.ObjectID, members []model.AchievementMember) error
+
+	// masa berlaku sertifikasi
+	FindExpiring(ctx context.Context, from, to time.Time) ([]model.Achievement, error)
+	MarkExpiryReminded(ctx context.Context, id primitive.ObjectID, validUntil time.Time) (bool, error)
+	ClaimRenewal(ctx context.Context, id primitive.ObjectID, renewalID string) (bool, error)
+	ReleaseRenewal(ctx context.Context, id primitive.ObjectID, renewalID string) error
+	ReleaseRenewalBy(ctx context.Context, renewalID string) error
+
+	// pratinjau lampiran
+	SetAttachmentPreview(ctx context.Context, id primitive.ObjectID, attachmentID string, p model.AttachmentPreview) error
+	FindPendingPreviews(ctx context.Context) ([]model.Achievement, error)
+
+	// lampiran lama (sebelum ada ID / hash)
+	FindLegacyAttachments(ctx context.Context) ([]model.Achievement, error)
+	BackfillAttachment(ctx context.Context, id primitive.ObjectID, legacy, att model.Attachment) (bool, error)
+
+	// pemakaian penyimpanan lampiran (termasuk tempat sampah: file baru hilang saat purge)
+	StorageByStudent(ctx context.Context, studentIDs []string, limit int64) ([]model.StorageUsage, error)
+	StorageByAchievement(ctx context.Context, limit int64) ([]model.AchievementStorageUsage, error)
+	StorageTotal(ctx context.Context) (model.StorageUsage, error)
 }
 
 type achievementRepository struct {
@@ -62,35 +101,188 @@ func (r *achievementRepository) GetByID(
 	return &achievement, nil
 }
 
+// AddAttachment: false kalau isi yang sama (hash) sudah jadi lampiran prestasi ini
+// (upload paralel / retry), supaya satu file tidak tercatat dua kali
 func (r *achievementRepository) AddAttachment(
 	ctx context.Context,
 	id primitive.ObjectID,
 	att model.Attachment,
-) error {
+) (bool, error) {
 
-	_, err := r.collection.UpdateOne(
+	res, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": id},
+		bson.M{"_id": id, "attachments.hash": bson.M{"$ne": att.Hash}},
 		bson.M{
 			"$push": bson.M{"attachments": att},
 			"$set":  bson.M{"updatedAt": att.UploadedAt},
 		},
 	)
-	return err
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// attachmentSelector: pasangan model.Attachment.Matches untuk query MongoDB
+func attachmentSelector(attachmentID string) bson.M {
+	return bson.M{"$or": []bson.M{
+		{"id": attachmentID},
+		{"id": bson.M{"$exists": false}, "hash": attachmentID},
+	}}
+}
+
+// RemoveAttachment: false kalau lampiran sudah tidak ada
+func (r *achievementRepository) RemoveAttachment(
+	ctx context.Context,
+	id primitive.ObjectID,
+	attachmentID string,
+) (bool, error) {
+
+	res, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":         id,
+			"attachments": bson.M{"$elemMatch": attachmentSelector(attachmentID)},
+		},
+		bson.M{
+			"$pull": bson.M{"attachments": attachmentSelector(attachmentID)},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
 }
 
+// ReplaceAttachment: ganti lampiran di posisi yang sama; false kalau sudah tidak ada
+func (r *achievementRepository) ReplaceAttachment(
+	ctx context.Context,
+	id primitive.ObjectID,
+	attachmentID string,
+	att model.Attachment,
+) (bool, error) {
+
+	res, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":         id,
+			"attachments": bson.M{"$elemMatch": attachmentSelector(attachmentID)},
+		},
+		bson.M{
+			"$set": bson.M{
+				"attachments.$": att,
+				"updatedAt":     att.UploadedAt,
+			},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// Update: hanya field yang bisa diedit lewat PUT /achievements/:id. Lampiran,
+// status pratinjau, poin & klaim perpanjangan diubah request / worker lain
+// secara bersamaan, jadi tidak ikut ditimpa dari dokumen yang dibaca di awal.
+// Tim yang tinggal pemiliknya saja (members nil) di-$unset, bukan dibiarkan.
 func (r *achievementRepository) Update(ctx context.Context, a *model.Achievement) error {
+	update := bson.M{
+		"$set": bson.M{
+			"title":       a.Title,
+			"description": a.Description,
+			"details":     a.Details,
+			"validFrom":   a.ValidFrom,
+			"validUntil":  a.ValidUntil,
+			"tags":        a.Tags,
+			"updatedAt":   a.UpdatedAt,
+		},
+	}
+	if len(a.Members) > 0 {
+		update["$set"].(bson.M)["members"] = a.Members
+	} else {
+		update["$unset"] = bson.M{"members": ""}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": a.ID}, update)
+	return err
+}
+
+// SetSubmission: hanya field yang dihitung ulang saat submit (details yang
+// dinormalisasi, masa berlaku, poin); lampiran & status pratinjau tidak disentuh.
+// Override poin ronde sebelumnya dihapus.
+func (r *achievementRepository) SetSubmission(ctx context.Context, a *model.Achievement) error {
 	_, err := r.collection.UpdateOne(
 		ctx,
 		bson.M{"_id": a.ID},
 		bson.M{
-			"$set": a,
+			"$set": bson.M{
+				"details":           a.Details,
+				"validFrom":         a.ValidFrom,
+				"validUntil":        a.ValidUntil,
+				"points":            a.Points,
+				"pointsRuleVersion": a.PointsVersion,
+				"updatedAt":         a.UpdatedAt,
+			},
+			"$unset": bson.M{"pointsOverride": ""},
 		},
 	)
 	return err
 }
 
+// SetPoints: hanya poin, versi tabel & override verifikator (override nil → dihapus)
+func (r *achievementRepository) SetPoints(ctx context.Context, a *model.Achievement) error {
+	update := bson.M{
+		"$set": bson.M{
+			"points":            a.Points,
+			"pointsRuleVersion": a.PointsVersion,
+		},
+	}
+	if a.PointsOverride != nil {
+		update["$set"].(bson.M)["pointsOverride"] = a.PointsOverride
+	} else {
+		update["$unset"] = bson.M{"pointsOverride": ""}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": a.ID}, update)
+	return err
+}
+
+// SetRecalculatedPoints: poin hasil hitung ulang tabel baru, hanya kalau dokumen
+// masih memakai versi fromVersion (tidak diubah request lain sejak dibaca).
+// Override verifikator tetap; hanya calculatedPoints-nya yang diperbarui.
+func (r *achievementRepository) SetRecalculatedPoints(
+	ctx context.Context,
+	a *model.Achievement,
+	fromVersion string,
+) (bool, error) {
+
+	filter := bson.M{"_id": a.ID, "pointsRuleVersion": fromVersion}
+	if fromVersion == "" {
+		// dokumen lama belum punya field versi
+		filter["pointsRuleVersion"] = bson.M{"$in": bson.A{nil, ""}}
+	}
+
+	set := bson.M{
+		"points":            a.Points,
+		"pointsRuleVersion": a.PointsVersion,
+	}
+	if a.PointsOverride != nil {
+		filter["pointsOverride"] = bson.M{"$ne": nil}
+		set["pointsOverride.calculatedPoints"] = a.PointsOverride.CalculatedPoints
+	} else {
+		filter["pointsOverride"] = nil
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
 func (r *achievementRepository) SoftDelete(ctx context.Context, id primitive.ObjectID) error {
+	now := time.Now()
 	_, err := r.collection.UpdateOne(
 		ctx,
 		bson.M{"_id": id},
@@ -98,7 +290,8 @@ func (r *achievementRepository) SoftDelete(ctx context.Context, id primitive.Obj
 			"$set": bson.M{
 				"is_deleted": true,
 				"status":     "deleted",
-				"updatedAt":  time.Now(),
+				"deletedAt":  now,
+				"updatedAt":  now,
 			},
 		},
 	)
@@ -125,13 +318,17 @@ func (r *achievementRepository) FindByStudentIDs(
 		return []model.Achievement{}, nil
 	}
 
+	// pemilik ATAU anggota tim; $and karena notDeletedFilter juga pakai $or
 	filter := bson.M{
-		"studentId": bson.M{"$in": studentIDs},
-	}
-
-	// gabung dengan not-deleted
-	for k, v := range notDeletedFilter() {
-		filter[k] = v
+		"$and": []bson.M{
+			{
+				"$or": []bson.M{
+					{"studentId": bson.M{"$in": studentIDs}},
+					{"members.studentId": bson.M{"$in": studentIDs}},
+				},
+			},
+			notDeletedFilter(),
+		},
 	}
 
 	cursor, err := r.collection.Find(ctx, filter)
@@ -202,3 +399,492 @@ func (r *achievementRepository) FindByIDs(
 
 	return result, nil
 }
+
+// FindByAttachmentHashes: prestasi yang punya lampiran dengan isi file sama
+func (r *achievementRepository) FindByAttachmentHashes(
+	ctx context.Context,
+	hashes []string,
+) ([]model.Achievement, error) {
+
+	// ⛔ jangan query Mongo dengan $in: []
+	if len(hashes) == 0 {
+		return []model.Achievement{}, nil
+	}
+
+	filter := bson.M{
+		"attachments.hash": bson.M{"$in": hashes},
+	}
+
+	// gabung dengan not-deleted
+	for k, v := range notDeletedFilter() {
+		filter[k] = v
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.Achievement
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindDeleted: isi tempat sampah; studentIDs kosong = semua mahasiswa (admin)
+func (r *achievementRepository) FindDeleted(
+	ctx context.Context,
+	studentIDs []string,
+) ([]model.Achievement, error) {
+
+	filter := bson.M{"is_deleted": true}
+	if len(studentIDs) > 0 {
+		filter["studentId"] = bson.M{"$in": studentIDs}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deletedAt", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.Achievement{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindDeletedBefore: kandidat purge. Data lama tanpa deletedAt
+// memakai updatedAt (diisi saat soft delete).
+func (r *achievementRepository) FindDeletedBefore(
+	ctx context.Context,
+	cutoff time.Time,
+) ([]model.Achievement, error) {
+
+	filter := bson.M{
+		"is_deleted": true,
+		"$or": []bson.M{
+			{"deletedAt": bson.M{"$lt": cutoff}},
+			{"deletedAt": bson.M{"$exists": false}, "updatedAt": bson.M{"$lt": cutoff}},
+		},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.Achievement
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Restore: keluarkan dari tempat sampah, kembali ke draft
+func (r *achievementRepository) Restore(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "is_deleted": true},
+		bson.M{
+			"$set": bson.M{
+				"is_deleted": false,
+				"status":     "draft",
+				"updatedAt":  time.Now(),
+			},
+			"$unset": bson.M{"deletedAt": ""},
+		},
+	)
+	return err
+}
+
+// HardDelete: hapus permanen, hanya untuk dokumen yang sudah di tempat sampah
+func (r *achievementRepository) HardDelete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "is_deleted": true})
+	return err
+}
+
+// AttachmentInUse: object storage masih dirujuk prestasi lain (termasuk yang
+// di tempat sampah); lampiran lama dicocokkan lewat fileUrl
+func (r *achievementRepository) AttachmentInUse(
+	ctx context.Context,
+	key string,
+	excludeID primitive.ObjectID,
+) (bool, error) {
+
+	n, err := r.collection.CountDocuments(ctx, bson.M{
+		"_id": bson.M{"$ne": excludeID},
+		"$or": []bson.M{
+			{"attachments.key": key},
+			{"attachments.fileUrl": model.LegacyUploadPrefix + key},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// Remove: hapus dokumen apa pun statusnya (kompensasi create / perbaikan orphan)
+func (r *achievementRepository) Remove(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// FindSyncStates: semua dokumen (termasuk tempat sampah), hanya field
+// yang dibutuhkan rekonsiliasi
+func (r *achievementRepository) FindSyncStates(ctx context.Context) ([]model.Achievement, error) {
+	opts := options.Find().SetProjection(bson.M{
+		"_id":        1,
+		"studentId":  1,
+		"members":    1,
+		"status":     1,
+		"is_deleted": 1,
+		"createdAt":  1,
+		"updatedAt":  1,
+	})
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.Achievement
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SyncStatus: samakan status dokumen dengan reference PostgreSQL
+func (r *achievementRepository) SyncStatus(ctx context.Context, id primitive.ObjectID, status string) error {
+	if status == model.StatusDeleted {
+		return r.SoftDelete(ctx, id)
+	}
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{
+				"is_deleted": false,
+				"status":     status,
+				"updatedAt":  time.Now(),
+			},
+			"$unset": bson.M{"deletedAt": ""},
+		},
+	)
+	return err
+}
+
+// SyncMembers: samakan anggota tim dokumen dengan PostgreSQL
+// (tanpa anggota → field dihapus, sama seperti Update)
+func (r *achievementRepository) SyncMembers(
+	ctx context.Context,
+	id primitive.ObjectID,
+	members []model.AchievementMember,
+) error {
+
+	update := bson.M{"$set": bson.M{"members": members}}
+	if len(members) == 0 {
+		update = bson.M{"$unset": bson.M{"members": ""}}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// FindExpiring: prestasi aktif dengan validUntil di rentang [from, to]
+func (r *achievementRepository) FindExpiring(
+	ctx context.Context,
+	from, to time.Time,
+) ([]model.Achievement, error) {
+
+	filter := notDeletedFilter()
+	filter["validUntil"] = bson.M{"$gte": from, "$lte": to}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.Achievement
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// MarkExpiryReminded: tandai pengingat untuk validUntil ini sudah dikirim.
+// false = sudah ditandai proses lain, atau validUntil berubah sejak dibaca.
+func (r *achievementRepository) MarkExpiryReminded(
+	ctx context.Context,
+	id primitive.ObjectID,
+	validUntil time.Time,
+) (bool, error) {
+
+	res, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":               id,
+			"validUntil":        validUntil,
+			"expiryRemindedFor": bson.M{"$ne": validUntil},
+		},
+		bson.M{"$set": bson.M{"expiryRemindedFor": validUntil}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// ClaimRenewal: tandai sertifikasi diperpanjang oleh renewalID.
+// false = sudah diperpanjang (diklaim request lain lebih dulu).
+func (r *achievementRepository) ClaimRenewal(
+	ctx context.Context,
+	id primitive.ObjectID,
+	renewalID string,
+) (bool, error) {
+
+	res, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "renewedById": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"renewedById": renewalID}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// ReleaseRenewal: batalkan klaim ClaimRenewal (hanya kalau masih milik renewalID)
+func (r *achievementRepository) ReleaseRenewal(
+	ctx context.Context,
+	id primitive.ObjectID,
+	renewalID string,
+) error {
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "renewedById": renewalID},
+		bson.M{"$unset": bson.M{"renewedById": ""}},
+	)
+	return err
+}
+
+// ReleaseRenewalBy: lepas klaim milik prestasi perpanjangan renewalID
+// (draft perpanjangan dihapus), tanpa perlu tahu sertifikasi lamanya
+func (r *achievementRepository) ReleaseRenewalBy(ctx context.Context, renewalID string) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"renewedById": renewalID},
+		bson.M{"$unset": bson.M{"renewedById": ""}},
+	)
+	return err
+}
+
+// SetAttachmentPreview: hasil pembuatan pratinjau; lampiran yang sudah
+// dihapus / diganti tidak ikut berubah
+func (r *achievementRepository) SetAttachmentPreview(
+	ctx context.Context,
+	id primitive.ObjectID,
+	attachmentID string,
+	p model.AttachmentPreview,
+) error {
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":         id,
+			"attachments": bson.M{"$elemMatch": attachmentSelector(attachmentID)},
+		},
+		bson.M{"$set": bson.M{"attachments.$.preview": p}},
+	)
+	return err
+}
+
+// FindPendingPreviews: prestasi aktif yang masih punya lampiran menunggu pratinjau
+func (r *achievementRepository) FindPendingPreviews(ctx context.Context) ([]model.Achievement, error) {
+	filter := notDeletedFilter()
+	filter["attachments.preview.status"] = model.PreviewPending
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.Achievement
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindLegacyAttachments: prestasi (termasuk tempat sampah) yang masih punya lampiran tanpa ID
+func (r *achievementRepository) FindLegacyAttachments(ctx context.Context) ([]model.Achievement, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"attachments": bson.M{"$elemMatch": bson.M{"id": bson.M{"$exists": false}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.Achievement
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BackfillAttachment: isi ID / hash / ukuran lampiran lama. Hanya mengenai lampiran
+// yang masih tanpa ID; false = sudah diisi proses lain (atau lampiran sudah dihapus).
+func (r *achievementRepository) BackfillAttachment(
+	ctx context.Context,
+	id primitive.ObjectID,
+	legacy model.Attachment,
+	att model.Attachment,
+) (bool, error) {
+
+	selector := bson.M{
+		"id":       bson.M{"$exists": false},
+		"fileName": legacy.FileName,
+	}
+	if legacy.FileURL != "" {
+		selector["fileUrl"] = legacy.FileURL
+	}
+	if legacy.Key != "" {
+		selector["key"] = legacy.Key
+	}
+
+	set := bson.M{"attachments.$.id": att.ID}
+	if att.Hash != "" {
+		set["attachments.$.hash"] = att.Hash
+	}
+	if att.Size > 0 {
+		set["attachments.$.size"] = att.Size
+	}
+
+	res, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":         id,
+			"attachments": bson.M{"$elemMatch": selector},
+		},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// StorageByStudent: pemakaian per mahasiswa, terbesar dulu.
+// studentIDs kosong = semua mahasiswa; mahasiswa tanpa lampiran tidak muncul.
+func (r *achievementRepository) StorageByStudent(
+	ctx context.Context,
+	studentIDs []string,
+	limit int64,
+) ([]model.StorageUsage, error) {
+
+	match := bson.M{"attachments.0": bson.M{"$exists": true}}
+	if len(studentIDs) > 0 {
+		match["studentId"] = bson.M{"$in": studentIDs}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$attachments"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$studentId",
+			"usedBytes":      bson.M{"$sum": "$attachments.size"},
+			"attachments":    bson.M{"$sum": 1},
+			"achievementIds": bson.M{"$addToSet": "$_id"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"usedBytes":    1,
+			"attachments":  1,
+			"achievements": bson.M{"$size": "$achievementIds"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "usedBytes", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.StorageUsage{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StorageByAchievement: prestasi dengan lampiran terbesar dulu
+func (r *achievementRepository) StorageByAchievement(
+	ctx context.Context,
+	limit int64,
+) ([]model.AchievementStorageUsage, error) {
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"attachments.0": bson.M{"$exists": true}}}},
+		{{Key: "$project", Value: bson.M{
+			"studentId":   1,
+			"title":       1,
+			"usedBytes":   bson.M{"$sum": "$attachments.size"},
+			"attachments": bson.M{"$size": "$attachments"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "usedBytes", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.AchievementStorageUsage{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StorageTotal: total seluruh lampiran (StudentID kosong)
+func (r *achievementRepository) StorageTotal(ctx context.Context) (model.StorageUsage, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"attachments.0": bson.M{"$exists": true}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "",
+			"usedBytes":    bson.M{"$sum": bson.M{"$sum": "$attachments.size"}},
+			"attachments":  bson.M{"$sum": bson.M{"$size": "$attachments"}},
+			"achievements": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return model.StorageUsage{}, err
+	}
+
+	result := []model.StorageUsage{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return model.StorageUsage{}, err
+	}
+	if len(result) == 0 {
+		return model.StorageUsage{}, nil
+	}
+	return result[0], nil
+}
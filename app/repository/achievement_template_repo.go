@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"uas-backend/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AchievementTemplateRepository interface {
+	Create(ctx context.Context, t *model.AchievementTemplate) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*model.AchievementTemplate, error)
+	FindAll(ctx context.Context, achievementType string, activeOnly bool) ([]model.AchievementTemplate, error)
+	Update(ctx context.Context, t *model.AchievementTemplate) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type achievementTemplateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAchievementTemplateRepository(db *mongo.Database) AchievementTemplateRepository {
+	return &achievementTemplateRepository{
+		collection: db.Collection("achievement_templates"),
+	}
+}
+
+func (r *achievementTemplateRepository) Create(ctx context.Context, t *model.AchievementTemplate) error {
+	res, err := r.collection.InsertOne(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return errors.New("failed to cast inserted ID to ObjectID")
+	}
+
+	t.ID = oid
+	return nil
+}
+
+func (r *achievementTemplateRepository) GetByID(
+	ctx context.Context,
+	id primitive.ObjectID,
+) (*model.AchievementTemplate, error) {
+
+	var t model.AchievementTemplate
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FindAll: achievementType kosong = semua tipe, urut nama
+func (r *achievementTemplateRepository) FindAll(
+	ctx context.Context,
+	achievementType string,
+	activeOnly bool,
+) ([]model.AchievementTemplate, error) {
+
+	filter := bson.M{}
+	if achievementType != "" {
+		filter["achievementType"] = achievementType
+	}
+	if activeOnly {
+		filter["isActive"] = true
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.AchievementTemplate{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *achievementTemplateRepository) Update(ctx context.Context, t *model.AchievementTemplate) error {
+	t.UpdatedAt = time.Now()
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": t.ID}, t)
+	return err
+}
+
+// Delete: prestasi yang sudah dibuat tetap menyimpan templateId-nya
+func (r *achievementTemplateRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
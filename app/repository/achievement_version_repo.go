@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"uas-backend/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AchievementVersionRepository interface {
+	// EnsureIndexes: unique (achievementId, version), dipanggil saat start
+	EnsureIndexes(ctx context.Context) error
+	// Create: nomor versi diisi otomatis (versi terakhir + 1)
+	Create(ctx context.Context, v *model.AchievementVersion) error
+	FindByAchievementID(ctx context.Context, achievementID string) ([]model.AchievementVersion, error)
+	DeleteByAchievementID(ctx context.Context, achievementID string) error
+}
+
+type achievementVersionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAchievementVersionRepository(db *mongo.Database) AchievementVersionRepository {
+	return &achievementVersionRepository{
+		collection: db.Collection("achievement_versions"),
+	}
+}
+
+// percobaan ulang kalau nomor versi sudah diambil request lain
+const versionInsertAttempts = 5
+
+func (r *achievementVersionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "achievementId", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// Create: versi terakhir + 1; dua request bersamaan bentrok di unique index,
+// yang kalah membaca ulang versi terakhir lalu mencoba lagi
+func (r *achievementVersionRepository) Create(ctx context.Context, v *model.AchievementVersion) error {
+	for attempt := 1; ; attempt++ {
+		// versi terakhir
+		var last model.AchievementVersion
+		err := r.collection.FindOne(
+			ctx,
+			bson.M{"achievementId": v.AchievementID},
+			options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}),
+		).Decode(&last)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		v.Version = last.Version + 1
+
+		res, err := r.collection.InsertOne(ctx, v)
+		if mongo.IsDuplicateKeyError(err) && attempt < versionInsertAttempts {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		oid, ok := res.InsertedID.(primitive.ObjectID)
+		if !ok {
+			return errors.New("failed to cast inserted ID to ObjectID")
+		}
+
+		v.ID = oid
+		return nil
+	}
+}
+
+// FindByAchievementID: urut dari versi paling lama
+func (r *achievementVersionRepository) FindByAchievementID(
+	ctx context.Context,
+	achievementID string,
+) ([]model.AchievementVersion, error) {
+
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"achievementId": achievementID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.AchievementVersion{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteByAchievementID: dipakai saat purge prestasi dari tempat sampah
+func (r *achievementVersionRepository) DeleteByAchievementID(ctx context.Context, achievementID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"achievementId": achievementID})
+	return err
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"uas-backend/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, n *model.Notification) error
+	FindByUserID(ctx context.Context, userID string) ([]model.Notification, error)
+	MarkRead(ctx context.Context, id primitive.ObjectID, userID string) error
+}
+
+type notificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationRepository(db *mongo.Database) NotificationRepository {
+	return &notificationRepository{
+		collection: db.Collection("notifications"),
+	}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, n *model.Notification) error {
+	res, err := r.collection.InsertOne(ctx, n)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		n.ID = oid
+	}
+	return nil
+}
+
+func (r *notificationRepository) FindByUserID(
+	ctx context.Context,
+	userID string,
+) ([]model.Notification, error) {
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.Notification{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// MarkRead: hanya pemilik notifikasi yang bisa menandai
+func (r *notificationRepository) MarkRead(
+	ctx context.Context,
+	id primitive.ObjectID,
+	userID string,
+) error {
+
+	res, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "userId": userID},
+		bson.M{"$set": bson.M{"isRead": true}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return errors.New("notification not found")
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package repository
 
-import "context"
+import (
+	"context"
+
+	"uas-backend/app/model"
+)
 
 type ReportRepository interface {
 	GetVerifiedAchievementIDs(ctx context.Context) ([]string, error)
@@ -26,8 +30,9 @@ func (r *reportRepository) GetVerifiedAchievementIDs(
 		`
 		SELECT mongo_achievement_id
 		FROM achievement_references
-		WHERE status = 'verified'
+		WHERE status = $1
 		`,
+		model.StatusVerified,
 	)
 	if err != nil {
 		return nil, err
@@ -55,9 +60,17 @@ func (r *reportRepository) GetVerifiedAchievementIDsByStudent(
 		`
 		SELECT mongo_achievement_id
 		FROM achievement_references
-		WHERE status = 'verified'
-		  AND student_id = $1
+		WHERE status = $1
+		  AND (
+			student_id = $2
+			OR EXISTS (
+				SELECT 1 FROM achievement_members m
+				WHERE m.mongo_achievement_id = achievement_references.mongo_achievement_id
+				  AND m.student_id = $2
+			)
+		  )
 		`,
+		model.StatusVerified,
 		studentID,
 	)
 	if err != nil {
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"uas-backend/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type UploadSessionRepository interface {
+	Create(ctx context.Context, s *model.UploadSession) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*model.UploadSession, error)
+	// Advance: simpan progres hanya kalau offset di database masih from
+	// (potongan paralel / retry ganda → false)
+	Advance(ctx context.Context, s *model.UploadSession, from int64) (bool, error)
+	// ClaimFinish: tandai sesi lengkap sedang digabung. false = sudah diklaim request
+	// lain (klaim sebelum staleBefore dianggap ditinggalkan) atau sesi sudah tidak ada
+	ClaimFinish(ctx context.Context, id primitive.ObjectID, now, staleBefore time.Time) (bool, error)
+	// ReleaseFinish: penggabungan gagal → sesi boleh digabung ulang
+	ReleaseFinish(ctx context.Context, id primitive.ObjectID) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// FindExpired: sesi yang ditinggalkan (expiresAt sebelum waktu tertentu)
+	FindExpired(ctx context.Context, before time.Time) ([]model.UploadSession, error)
+	// FindOpenByStudent: sesi yang masih berlaku untuk prestasi milik mahasiswa (dihitung ke kuota)
+	FindOpenByStudent(ctx context.Context, studentID string, now time.Time) ([]model.UploadSession, error)
+}
+
+type uploadSessionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewUploadSessionRepository(db *mongo.Database) UploadSessionRepository {
+	return &uploadSessionRepository{
+		collection: db.Collection("upload_sessions"),
+	}
+}
+
+func (r *uploadSessionRepository) Create(ctx context.Context, s *model.UploadSession) error {
+	res, err := r.collection.InsertOne(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return errors.New("failed to cast inserted ID to ObjectID")
+	}
+
+	s.ID = oid
+	return nil
+}
+
+func (r *uploadSessionRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*model.UploadSession, error) {
+	var s model.UploadSession
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *uploadSessionRepository) Advance(ctx context.Context, s *model.UploadSession, from int64) (bool, error) {
+	res, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": s.ID, "offset": from},
+		bson.M{"$set": bson.M{
+			"offset":    s.Offset,
+			"chunks":    s.Chunks,
+			"head":      s.Head,
+			"hashState": s.HashState,
+			"updatedAt": s.UpdatedAt,
+			"expiresAt": s.ExpiresAt,
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (r *uploadSessionRepository) ClaimFinish(
+	ctx context.Context,
+	id primitive.ObjectID,
+	now, staleBefore time.Time,
+) (bool, error) {
+	res, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":   id,
+			"$expr": bson.M{"$eq": bson.A{"$offset", "$size"}},
+			"$or": []bson.M{
+				{"finishingAt": bson.M{"$exists": false}},
+				{"finishingAt": nil},
+				{"finishingAt": bson.M{"$lt": staleBefore}},
+			},
+		},
+		bson.M{"$set": bson.M{"finishingAt": now}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (r *uploadSessionRepository) ReleaseFinish(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$unset": bson.M{"finishingAt": ""}},
+	)
+	return err
+}
+
+func (r *uploadSessionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *uploadSessionRepository) FindExpired(ctx context.Context, before time.Time) ([]model.UploadSession, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"expiresAt": bson.M{"$lt": before}})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.UploadSession{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *uploadSessionRepository) FindOpenByStudent(
+	ctx context.Context,
+	studentID string,
+	now time.Time,
+) ([]model.UploadSession, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"studentId": studentID,
+		"expiresAt": bson.M{"$gte": now},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.UploadSession{}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"reflect"
+	"slices"
+
+	"uas-backend/app/model"
+)
+
+// DiffAchievements: perubahan per field dari versi from ke versi to.
+// Urutan: field utama, members, attachments, lalu details.<key> (urut abjad).
+func DiffAchievements(from, to *model.Achievement) []model.FieldChange {
+	changes := []model.FieldChange{}
+
+	add := func(field string, before, after any) {
+		if !reflect.DeepEqual(before, after) {
+			changes = append(changes, model.FieldChange{Field: field, Before: before, After: after})
+		}
+	}
+
+	add("title", from.Title, to.Title)
+	add("description", from.Description, to.Description)
+	add("achievementType", from.AchievementType, to.AchievementType)
+	add("tags", emptyIfNil(from.Tags), emptyIfNil(to.Tags))
+	add("members", emptyIfNil(from.Members), emptyIfNil(to.Members))
+	add("attachments", attachmentNames(from.Attachments), attachmentNames(to.Attachments))
+
+	var keys []string
+	for k := range from.Details {
+		keys = append(keys, k)
+	}
+	for k := range to.Details {
+		if _, ok := from.Details[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		add("details."+k, from.Details[k], to.Details[k])
+	}
+
+	return changes
+}
+
+// attachmentNames: lampiran dibandingkan per file (nama + hash isi)
+func attachmentNames(atts []model.Attachment) []string {
+	names := []string{}
+	for _, a := range atts {
+		name := a.FileName
+		if a.Hash != "" {
+			name += "#" + a.Hash[:min(len(a.Hash), 12)]
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// emptyIfNil: nil dan slice kosong dianggap sama
+func emptyIfNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
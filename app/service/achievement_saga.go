@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"uas-backend/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Penulisan prestasi menyentuh dua store (MongoDB + PostgreSQL) tanpa
+// transaksi bersama. Tiap langkah yang gagal di tengah jalan dikompensasi
+// di sini; sisa yang tetap tidak sinkron (mis. kompensasi ikut gagal atau
+// proses mati) diperbaiki oleh ConsistencyService.Reconcile.
+
+// compensateCreate: create gagal setelah dokumen MongoDB tersimpan →
+// buang reference (kalau sempat dibuat) lalu dokumennya. Kalau kompensasi
+// ikut gagal, data tersisa akan ditemukan oleh rekonsiliasi.
+func (s *AchievementService) compensateCreate(ctx context.Context, oid primitive.ObjectID) {
+	if err := s.referenceRepo.Discard(ctx, oid.Hex()); err != nil {
+		logCompensation("discard reference", oid.Hex(), err)
+		return
+	}
+	if err := s.achievementRepo.Remove(ctx, oid); err != nil {
+		logCompensation("remove document", oid.Hex(), err)
+	}
+}
+
+// releaseRenewal: prestasi perpanjangan gagal dibuat → sertifikasi lama
+// bisa diperpanjang lagi
+func (s *AchievementService) releaseRenewal(ctx context.Context, renews *model.Achievement, renewalID string) {
+	if renews == nil {
+		return
+	}
+	if err := s.achievementRepo.ReleaseRenewal(ctx, renews.ID, renewalID); err != nil {
+		logCompensation("release renewal", renews.ID.Hex(), err)
+	}
+}
+
+// releaseRenewalBy: draft perpanjangan dihapus → sertifikasi lama yang
+// diklaimnya bisa diperpanjang & diingatkan lagi. Gagal hanya dicatat.
+func (s *AchievementService) releaseRenewalBy(ctx context.Context, renewalID string) {
+	if err := s.achievementRepo.ReleaseRenewalBy(ctx, renewalID); err != nil {
+		logCompensation("release renewal", renewalID, err)
+	}
+}
+
+// reclaimRenewal: draft perpanjangan dipulihkan → klaim sertifikasi lama
+// dipasang lagi. Kalau sudah diperpanjang draft lain, klaim dibiarkan milik draft itu.
+func (s *AchievementService) reclaimRenewal(ctx context.Context, id primitive.ObjectID) {
+	a, err := s.achievementRepo.GetByID(ctx, id)
+	if err != nil || a.RenewsID == "" {
+		return
+	}
+	renewsID, err := primitive.ObjectIDFromHex(a.RenewsID)
+	if err != nil {
+		return
+	}
+	claimed, err := s.achievementRepo.ClaimRenewal(ctx, renewsID, id.Hex())
+	if err != nil {
+		logCompensation("reclaim renewal", a.RenewsID, err)
+		return
+	}
+	if !claimed {
+		log.Printf("⚠️ Renewal %s restored, but %s was already renewed by another achievement", id.Hex(), a.RenewsID)
+	}
+}
+
+// restorePoints: override poin sudah tersimpan tetapi approve di PostgreSQL
+// gagal → kembalikan poin & override sebelumnya
+func (s *AchievementService) restorePoints(ctx context.Context, previous *model.Achievement) {
+	if err := s.achievementRepo.SetPoints(ctx, previous); err != nil {
+		logCompensation("restore points", previous.ID.Hex(), err)
+	}
+}
+
+// restoreMembers: anggota tim di PostgreSQL sudah diganti tetapi dokumen
+// MongoDB gagal disimpan → kembalikan anggota sesuai dokumen
+func (s *AchievementService) restoreMembers(ctx context.Context, achievementID string, previous []model.AchievementMember) {
+	if err := s.referenceRepo.SetMembers(ctx, achievementID, previous); err != nil {
+		logCompensation("restore members", achievementID, err)
+	}
+}
+
+// revertStatus: perubahan status di PostgreSQL sudah tersimpan tetapi
+// MongoDB gagal → kembalikan status reference ke semula
+func (s *AchievementService) revertStatus(
+	c *fiber.Ctx,
+	achievementID string,
+	t *model.WorkflowTransition,
+	previous string,
+) {
+	claims := c.Locals("user").(*model.JWTClaims)
+	note := "rollback: " + t.Action + " failed in MongoDB"
+	if _, err := s.referenceRepo.UpdateStatus(c.Context(), achievementID, model.StatusChange{
+		From: []string{t.To},
+		To:   previous,
+		By:   claims.UserID,
+		Note: &note,
+	}); err != nil {
+		logCompensation("revert status", achievementID, err)
+	}
+}
+
+func logCompensation(step, achievementID string, err error) {
+	log.Printf("⚠️ Compensation %s for %s failed (will be repaired by reconcile): %v", step, achievementID, err)
+}
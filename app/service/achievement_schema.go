@@ -0,0 +1,474 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"uas-backend/app/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AchievementSchemas: registry skema details per AchievementType
+type AchievementSchemas struct {
+	schemas []model.AchievementTypeSchema
+	byType  map[string]model.AchievementTypeSchema
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+// DefaultAchievementSchemas dipakai kalau tidak ada file konfigurasi
+func DefaultAchievementSchemas() []model.AchievementTypeSchema {
+	return []model.AchievementTypeSchema{
+		{
+			Type:  "competition",
+			Label: "Kompetisi",
+			Fields: []model.DetailField{
+				{Name: "competitionName", Label: "Nama Kompetisi", Type: model.FieldString, Required: true, Aliases: []string{"competition_name", "eventName"}},
+				{Name: "competitionLevel", Label: "Tingkat", Type: model.FieldString, Required: true,
+					Enum: []string{"international", "national", "regional", "local"}, Aliases: []string{"competition_level", "level"}},
+				{Name: "rank", Label: "Peringkat", Type: model.FieldInteger, Required: true, Min: floatPtr(1), Aliases: []string{"position", "juara"}},
+				{Name: "medalType", Label: "Medali", Type: model.FieldString, Enum: []string{"gold", "silver", "bronze"}, Aliases: []string{"medal_type", "medal"}},
+				{Name: "organizer", Label: "Penyelenggara", Type: model.FieldString},
+				{Name: "eventDate", Label: "Tanggal", Type: model.FieldDate, Required: true, Aliases: []string{"event_date", "date"}},
+			},
+			Evidence: []model.EvidenceRequirement{
+				{Name: "certificate", Label: "Sertifikat (PDF)", AnyOf: []model.EvidenceOption{
+					{Kind: model.EvidenceCertificate, FileTypes: []string{"application/pdf"}},
+				}},
+			},
+		},
+		{
+			Type:  "publication",
+			Label: "Publikasi",
+			Fields: []model.DetailField{
+				{Name: "publicationType", Label: "Jenis Publikasi", Type: model.FieldString, Required: true,
+					Enum: []string{"journal", "conference", "book"}, Aliases: []string{"publication_type"}},
+				{Name: "publicationTitle", Label: "Judul Publikasi", Type: model.FieldString, Required: true, Aliases: []string{"publication_title"}},
+				{Name: "authors", Label: "Penulis", Type: model.FieldArray, Required: true},
+				{Name: "publisher", Label: "Penerbit", Type: model.FieldString, Required: true},
+				{Name: "indexing", Label: "Indeksasi", Type: model.FieldString, Enum: []string{"scopus", "wos", "sinta", "none"}},
+				{Name: "issn", Label: "ISSN / ISBN", Type: model.FieldString, Aliases: []string{"isbn"}},
+				{Name: "publishedDate", Label: "Tanggal Terbit", Type: model.FieldDate, Required: true, Aliases: []string{"published_date", "date"}},
+				{Name: "url", Label: "Tautan", Type: model.FieldURL, Aliases: []string{"link", "doi"}},
+			},
+			Evidence: []model.EvidenceRequirement{
+				{Name: "publication", Label: "DOI / tautan atau naskah PDF", AnyOf: []model.EvidenceOption{
+					{DetailField: "url"},
+					{Kind: model.EvidencePublication, FileTypes: []string{"application/pdf"}},
+				}},
+			},
+			Uploads: &model.UploadPolicy{FileTypes: []string{"application/pdf"}},
+		},
+		{
+			Type:  "organization",
+			Label: "Organisasi",
+			Fields: []model.DetailField{
+				{Name: "organizationName", Label: "Nama Organisasi", Type: model.FieldString, Required: true, Aliases: []string{"organization_name", "organization"}},
+				{Name: "position", Label: "Jabatan", Type: model.FieldString, Required: true, Aliases: []string{"role"}},
+				{Name: "periodStart", Label: "Mulai", Type: model.FieldDate, Required: true, Aliases: []string{"period_start", "startDate"}},
+				{Name: "periodEnd", Label: "Selesai", Type: model.FieldDate, Aliases: []string{"period_end", "endDate"}},
+			},
+			Evidence: []model.EvidenceRequirement{
+				{Name: "appointment_letter", Label: "Surat tugas / SK", AnyOf: []model.EvidenceOption{
+					{Kind: model.EvidenceAppointmentLetter},
+				}},
+			},
+		},
+		{
+			Type:  "certification",
+			Label: "Sertifikasi",
+			Fields: []model.DetailField{
+				{Name: "certificationName", Label: "Nama Sertifikasi", Type: model.FieldString, Required: true, Aliases: []string{"certification_name"}},
+				{Name: "issuedBy", Label: "Penerbit", Type: model.FieldString, Required: true, Aliases: []string{"issued_by", "issuer"}},
+				{Name: "certificationNumber", Label: "Nomor Sertifikat", Type: model.FieldString, Aliases: []string{"certification_number"}},
+				{Name: "issuedDate", Label: "Tanggal Terbit", Type: model.FieldDate, Required: true, Aliases: []string{"issued_date", "date"}},
+				{Name: "validUntil", Label: "Berlaku Sampai", Type: model.FieldDate, Aliases: []string{"valid_until", "expiryDate"}},
+			},
+			Evidence: []model.EvidenceRequirement{
+				{Name: "certificate", Label: "Sertifikat (PDF / gambar)", AnyOf: []model.EvidenceOption{
+					{Kind: model.EvidenceCertificate, FileTypes: []string{"application/pdf", "image/*"}},
+				}},
+			},
+		},
+		{
+			Type:  "academic",
+			Label: "Akademik",
+			Fields: []model.DetailField{
+				{Name: "activityName", Label: "Nama Kegiatan", Type: model.FieldString, Required: true, Aliases: []string{"activity_name"}},
+				{Name: "institution", Label: "Institusi", Type: model.FieldString},
+				{Name: "score", Label: "Nilai", Type: model.FieldNumber, Min: floatPtr(0)},
+				{Name: "date", Label: "Tanggal", Type: model.FieldDate, Required: true, Aliases: []string{"eventDate"}},
+			},
+		},
+	}
+}
+
+func NewAchievementSchemas(schemas []model.AchievementTypeSchema) (*AchievementSchemas, error) {
+	if len(schemas) == 0 {
+		return nil, errors.New("achievement schemas: empty registry")
+	}
+
+	r := &AchievementSchemas{
+		schemas: schemas,
+		byType:  map[string]model.AchievementTypeSchema{},
+	}
+
+	for _, sc := range schemas {
+		if sc.Type == "" {
+			return nil, errors.New("achievement schemas: schema without type")
+		}
+		if _, dup := r.byType[sc.Type]; dup {
+			return nil, fmt.Errorf("achievement schemas: duplicate type %q", sc.Type)
+		}
+
+		names := map[string]bool{}
+		for _, f := range sc.Fields {
+			switch f.Type {
+			case model.FieldString, model.FieldInteger, model.FieldNumber,
+				model.FieldBoolean, model.FieldDate, model.FieldURL, model.FieldArray:
+			default:
+				return nil, fmt.Errorf("achievement schemas: %s.%s has unknown type %q", sc.Type, f.Name, f.Type)
+			}
+
+			for _, n := range append([]string{f.Name}, f.Aliases...) {
+				if n == "" || names[n] {
+					return nil, fmt.Errorf("achievement schemas: %s has empty or duplicate field name %q", sc.Type, n)
+				}
+				names[n] = true
+			}
+		}
+
+		if err := validateEvidence(sc); err != nil {
+			return nil, err
+		}
+		if err := validateUploads(sc); err != nil {
+			return nil, err
+		}
+
+		r.byType[sc.Type] = sc
+	}
+
+	return r, nil
+}
+
+// validateEvidence: nama unik, tiap opsi punya kind dikenal atau field skema
+func validateEvidence(sc model.AchievementTypeSchema) error {
+	seen := map[string]bool{}
+	for _, req := range sc.Evidence {
+		if req.Name == "" || seen[req.Name] {
+			return fmt.Errorf("achievement schemas: %s has empty or duplicate evidence name %q", sc.Type, req.Name)
+		}
+		seen[req.Name] = true
+
+		if len(req.AnyOf) == 0 {
+			return fmt.Errorf("achievement schemas: %s evidence %s has no options", sc.Type, req.Name)
+		}
+		for _, opt := range req.AnyOf {
+			switch {
+			case opt.Kind != "" && opt.DetailField != "":
+				return fmt.Errorf("achievement schemas: %s evidence %s: option needs kind OR detail_field", sc.Type, req.Name)
+			case opt.Kind != "":
+				if !slices.Contains(model.EvidenceKinds, opt.Kind) {
+					return fmt.Errorf("achievement schemas: %s evidence %s has unknown kind %q", sc.Type, req.Name, opt.Kind)
+				}
+			case opt.DetailField != "":
+				if !slices.ContainsFunc(sc.Fields, func(f model.DetailField) bool { return f.Name == opt.DetailField }) {
+					return fmt.Errorf("achievement schemas: %s evidence %s has unknown detail field %q", sc.Type, req.Name, opt.DetailField)
+				}
+			default:
+				return fmt.Errorf("achievement schemas: %s evidence %s: option needs kind or detail_field", sc.Type, req.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateUploads: MIME berbentuk type/subtype (boleh type/*), ukuran tidak negatif
+func validateUploads(sc model.AchievementTypeSchema) error {
+	if sc.Uploads == nil {
+		return nil
+	}
+	if sc.Uploads.MaxSizeMB < 0 {
+		return fmt.Errorf("achievement schemas: %s uploads has negative max_size_mb", sc.Type)
+	}
+	for _, ft := range sc.Uploads.FileTypes {
+		if _, _, err := mime.ParseMediaType(ft); err != nil || !strings.Contains(ft, "/") {
+			return fmt.Errorf("achievement schemas: %s uploads has invalid file type %q", sc.Type, ft)
+		}
+	}
+	return nil
+}
+
+// LoadAchievementSchemas membaca registry dari file JSON.
+// path kosong → pakai DefaultAchievementSchemas.
+func LoadAchievementSchemas(path string) (*AchievementSchemas, error) {
+	if path == "" {
+		return NewAchievementSchemas(DefaultAchievementSchemas())
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("achievement schemas: read %s: %w", path, err)
+	}
+
+	var schemas []model.AchievementTypeSchema
+	if err := json.Unmarshal(raw, &schemas); err != nil {
+		return nil, fmt.Errorf("achievement schemas: parse %s: %w", path, err)
+	}
+
+	return NewAchievementSchemas(schemas)
+}
+
+func (r *AchievementSchemas) List() []model.AchievementTypeSchema {
+	return r.schemas
+}
+
+func (r *AchievementSchemas) Get(achievementType string) (model.AchievementTypeSchema, bool) {
+	sc, ok := r.byType[achievementType]
+	return sc, ok
+}
+
+// Validate: normalisasi alias + enum lalu cek aturan tiap field.
+// details dimodifikasi langsung; key di luar skema dibiarkan.
+func (r *AchievementSchemas) Validate(achievementType string, details map[string]any) []model.FieldError {
+	return r.validate(achievementType, details, false)
+}
+
+// ValidatePartial: sama seperti Validate tetapi field wajib boleh kosong
+// (details bawaan template hanya berisi sebagian field)
+func (r *AchievementSchemas) ValidatePartial(achievementType string, details map[string]any) []model.FieldError {
+	return r.validate(achievementType, details, true)
+}
+
+func (r *AchievementSchemas) validate(achievementType string, details map[string]any, partial bool) []model.FieldError {
+	sc, ok := r.byType[achievementType]
+	if !ok {
+		return []model.FieldError{{
+			Field:   "achievementType",
+			Message: "unknown achievement type: " + achievementType,
+		}}
+	}
+
+	var errs []model.FieldError
+
+	for _, f := range sc.Fields {
+		key := "details." + f.Name
+
+		// 1️⃣ ejaan lama → nama baku
+		for _, alias := range f.Aliases {
+			v, found := details[alias]
+			if !found {
+				continue
+			}
+			if _, exists := details[f.Name]; !exists {
+				details[f.Name] = v
+			}
+			delete(details, alias)
+		}
+
+		v, found := details[f.Name]
+		if !found || v == nil || v == "" {
+			if f.Required && !partial {
+				errs = append(errs, model.FieldError{Field: key, Message: "is required"})
+			}
+			continue
+		}
+
+		// 2️⃣ cek tipe + format
+		normalized, msg := checkDetailValue(f, v)
+		if msg != "" {
+			errs = append(errs, model.FieldError{Field: key, Message: msg})
+			continue
+		}
+		details[f.Name] = normalized
+	}
+
+	return errs
+}
+
+// DefaultUploadFileTypes: tipe file lampiran kalau skema tidak mengatur
+var DefaultUploadFileTypes = []string{"application/pdf", "image/jpeg", "image/png", "image/webp", "video/mp4", "video/webm"}
+
+// UploadPolicy: aturan lampiran efektif untuk tipe prestasi
+// (tipe tidak dikenal → default)
+func (r *AchievementSchemas) UploadPolicy(achievementType string) model.UploadPolicy {
+	policy := model.UploadPolicy{FileTypes: DefaultUploadFileTypes}
+	if sc, ok := r.byType[achievementType]; ok && sc.Uploads != nil {
+		policy.MaxSizeMB = sc.Uploads.MaxSizeMB
+		if len(sc.Uploads.FileTypes) > 0 {
+			policy.FileTypes = sc.Uploads.FileTypes
+		}
+	}
+	return policy
+}
+
+// CheckEvidence: requirement bukti tipe prestasi yang belum terpenuhi.
+// Dipanggil setelah Validate supaya alias details sudah dinormalisasi.
+func (r *AchievementSchemas) CheckEvidence(a *model.Achievement) []model.UnmetEvidence {
+	unmet := []model.UnmetEvidence{}
+
+	sc, ok := r.byType[a.AchievementType]
+	if !ok {
+		return unmet
+	}
+
+	for _, req := range sc.Evidence {
+		if !slices.ContainsFunc(req.AnyOf, func(opt model.EvidenceOption) bool {
+			return evidenceSatisfied(opt, a)
+		}) {
+			unmet = append(unmet, model.UnmetEvidence{
+				Requirement: req.Name,
+				Label:       req.Label,
+				AnyOf:       req.AnyOf,
+			})
+		}
+	}
+
+	return unmet
+}
+
+func evidenceSatisfied(opt model.EvidenceOption, a *model.Achievement) bool {
+	if opt.DetailField != "" {
+		v, ok := a.Details[opt.DetailField]
+		return ok && v != nil && v != ""
+	}
+
+	for _, att := range a.Attachments {
+		if att.Kind == opt.Kind && mediaTypeAllowed(att.FileType, opt.FileTypes) {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaTypeAllowed: allowed kosong = semua; "image/*" cocok dengan image/png dst
+func mediaTypeAllowed(fileType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	mt, _, err := mime.ParseMediaType(fileType)
+	if err != nil {
+		return false
+	}
+
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if a == mt || (strings.HasSuffix(a, "/*") && strings.HasPrefix(mt, strings.TrimSuffix(a, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkDetailValue(f model.DetailField, v any) (any, string) {
+	switch f.Type {
+	case model.FieldString:
+		s, ok := v.(string)
+		if !ok {
+			return nil, "must be a string"
+		}
+		s = strings.TrimSpace(s)
+		if len(f.Enum) > 0 {
+			for _, e := range f.Enum {
+				if strings.EqualFold(s, e) {
+					return e, ""
+				}
+			}
+			return nil, "must be one of: " + strings.Join(f.Enum, ", ")
+		}
+		return s, ""
+
+	case model.FieldInteger, model.FieldNumber:
+		n, ok := toFloat(v)
+		if !ok {
+			return nil, "must be a number"
+		}
+		if f.Type == model.FieldInteger && n != float64(int64(n)) {
+			return nil, "must be an integer"
+		}
+		if f.Min != nil && n < *f.Min {
+			return nil, fmt.Sprintf("must be at least %v", *f.Min)
+		}
+		if f.Max != nil && n > *f.Max {
+			return nil, fmt.Sprintf("must be at most %v", *f.Max)
+		}
+		// angka JSON (float64) disimpan sebagai integer; angka dari MongoDB dibiarkan
+		if _, isFloat := v.(float64); isFloat && f.Type == model.FieldInteger {
+			return int64(n), ""
+		}
+		return v, ""
+
+	case model.FieldBoolean:
+		if _, ok := v.(bool); !ok {
+			return nil, "must be a boolean"
+		}
+		return v, ""
+
+	case model.FieldDate:
+		s, ok := v.(string)
+		if !ok {
+			return nil, "must be a date (YYYY-MM-DD)"
+		}
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, "must be a date (YYYY-MM-DD)"
+		}
+		return s, ""
+
+	case model.FieldURL:
+		s, ok := v.(string)
+		if !ok {
+			return nil, "must be a URL"
+		}
+		u, err := url.ParseRequestURI(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, "must be a URL"
+		}
+		return s, ""
+
+	case model.FieldArray:
+		items, ok := v.([]any)
+		if a, isA := v.(primitive.A); isA {
+			items, ok = a, true
+		}
+		if !ok {
+			if _, isStrs := v.([]string); isStrs {
+				return v, ""
+			}
+			return nil, "must be a list"
+		}
+		for _, it := range items {
+			if _, isStr := it.(string); !isStr {
+				return nil, "must be a list of strings"
+			}
+		}
+		return v, ""
+	}
+
+	return v, ""
+}
+
+// toFloat: angka dari JSON (float64) maupun dari MongoDB (int32 / int64)
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
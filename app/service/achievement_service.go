@@ -1,12 +1,18 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"uas-backend/app/model"
 	"uas-backend/app/repository"
+	"uas-backend/app/storage"
+	"uas-backend/config"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -23,7 +29,10 @@ type CreateAchievementRequest struct {
 	Description     string         `json:"description"`
 	Details         map[string]any `json:"details"`
 	Tags            []string       `json:"tags"`
-	Points          int            `json:"points"`
+	// anggota tim (opsional); pemilik otomatis ikut sebagai leader
+	Members []model.AchievementMember `json:"members"`
+	// template (opsional): tipe, details, description & tags diambil dari template
+	TemplateID string `json:"templateId"`
 }
 
 /*
@@ -37,7 +46,8 @@ type UpdateAchievementRequest struct {
 	Description *string        `json:"description"`
 	Details     map[string]any `json:"details"`
 	Tags        []string       `json:"tags"`
-	Points      *int           `json:"points"`
+	// nil = tidak diubah, [] = jadi prestasi individu
+	Members []model.AchievementMember `json:"members"`
 }
 
 // package service (atau dto khusus kalau kamu punya)
@@ -45,37 +55,546 @@ type RejectAchievementRequest struct {
 	RejectionNote string `json:"rejection_note"`
 }
 
+// catatan opsional reviewer saat menyetujui satu tahap;
+// Points diisi kalau verifikator mengubah poin hasil hitungan (wajib Justification)
+type VerifyAchievementRequest struct {
+	Note          string `json:"note"`
+	Points        *int   `json:"points"`
+	Justification string `json:"justification"`
+}
+
+// catatan opsional mahasiswa saat membuka revisi
+type ReviseAchievementRequest struct {
+	Note string `json:"note"`
+}
+
+type RevokeAchievementRequest struct {
+	Reason string `json:"reason"`
+}
+
+// batas jumlah ID per request bulk
+const maxBulkItems = 100
+
+type BulkVerifyRequest struct {
+	IDs  []string `json:"ids"`
+	Note string   `json:"note"`
+}
+
+type BulkRejectRequest struct {
+	IDs           []string `json:"ids"`
+	RejectionNote string   `json:"rejection_note"`
+}
+
+// hasil per item di bulk verify / reject
+const (
+	BulkResultOK          = "ok"
+	BulkResultForbidden   = "forbidden"
+	BulkResultWrongStatus = "wrong_status"
+	BulkResultNotFound    = "not_found"
+	BulkResultInvalid     = "invalid"
+	BulkResultError       = "error"
+)
+
+type BulkItemResult struct {
+	ID      string                      `json:"id"`
+	Result  string                      `json:"result"`
+	Message string                      `json:"message"`
+	Data    *model.AchievementReference `json:"data,omitempty"`
+}
+
+type CreateCommentRequest struct {
+	Body          string               `json:"body"`
+	ParentID      string               `json:"parent_id"`
+	ChangeRequest *model.ChangeRequest `json:"change_request"`
+}
+
+// pihak yang boleh membaca & menulis di thread komentar
+var achievementParticipants = []model.WorkflowActor{
+	{Role: "Mahasiswa", Relation: model.RelationMember},
+	{Role: "Dosen Wali", Relation: model.RelationAdvisor},
+	{Role: "Admin"},
+}
+
+// field prestasi yang bisa ditandai change request (details.<key> juga boleh)
+var changeRequestFields = map[string]bool{
+	"title":           true,
+	"description":     true,
+	"achievementType": true,
+	"details":         true,
+	"tags":            true,
+}
+
 type AchievementService struct {
-	achievementRepo repository.AchievementRepository
-	referenceRepo   repository.AchievementReferenceRepository
-	studentRepo     repository.StudentRepository
-	lecturerRepo    repository.LecturerRepository
-}
-
-func NewAchievementService(
-	achievementRepo repository.AchievementRepository,
-	referenceRepo repository.AchievementReferenceRepository,
-	studentRepo repository.StudentRepository,
-	lecturerRepo repository.LecturerRepository,
-) *AchievementService {
+	achievementRepo  repository.AchievementRepository
+	referenceRepo    repository.AchievementReferenceRepository
+	studentRepo      repository.StudentRepository
+	lecturerRepo     repository.LecturerRepository
+	workflow         *AchievementWorkflow
+	approvals        *ApprovalChains
+	notificationRepo repository.NotificationRepository
+	commentRepo      repository.AchievementCommentRepository
+	schemas          *AchievementSchemas
+	points           *PointsRules
+	duplicates       *DuplicateDetector
+	versionRepo      repository.AchievementVersionRepository
+	templateRepo     repository.AchievementTemplateRepository
+	store            storage.Storage
+	uploadRepo       repository.UploadSessionRepository // upload bertahap
+	previewJobs      chan previewJob                    // diproses StartPreviewWorker
+}
+
+// AchievementDeps: dependency AchievementService, diisi sekali di route.go
+type AchievementDeps struct {
+	AchievementRepo  repository.AchievementRepository
+	ReferenceRepo    repository.AchievementReferenceRepository
+	StudentRepo      repository.StudentRepository
+	LecturerRepo     repository.LecturerRepository
+	Workflow         *AchievementWorkflow
+	Approvals        *ApprovalChains
+	NotificationRepo repository.NotificationRepository
+	CommentRepo      repository.AchievementCommentRepository
+	Schemas          *AchievementSchemas
+	Points           *PointsRules
+	VersionRepo      repository.AchievementVersionRepository
+	TemplateRepo     repository.AchievementTemplateRepository
+	Store            storage.Storage
+	UploadRepo       repository.UploadSessionRepository
+}
+
+func NewAchievementService(d AchievementDeps) *AchievementService {
 	return &AchievementService{
-		achievementRepo: achievementRepo,
-		referenceRepo:   referenceRepo,
-		studentRepo:     studentRepo,
-		lecturerRepo:    lecturerRepo,
+		achievementRepo:  d.AchievementRepo,
+		referenceRepo:    d.ReferenceRepo,
+		studentRepo:      d.StudentRepo,
+		lecturerRepo:     d.LecturerRepo,
+		workflow:         d.Workflow,
+		approvals:        d.Approvals,
+		notificationRepo: d.NotificationRepo,
+		commentRepo:      d.CommentRepo,
+		schemas:          d.Schemas,
+		points:           d.Points,
+		duplicates:       NewDuplicateDetector(d.AchievementRepo, d.StudentRepo),
+		versionRepo:      d.VersionRepo,
+		templateRepo:     d.TemplateRepo,
+		store:            d.Store,
+		uploadRepo:       d.UploadRepo,
+		previewJobs:      make(chan previewJob, previewQueueSize),
+	}
+}
+
+// versionWarning: dikirim ke client kalau snapshot gagal disimpan
+const versionNotSavedWarning = "changes saved, but the version history entry could not be recorded"
+
+// saveVersion: simpan snapshot dokumen setelah berubah. Perubahannya sudah
+// tersimpan di kedua store, jadi gagal di sini tidak boleh membuat request
+// terlihat gagal (client akan mengulang → prestasi dobel / status sudah pindah).
+// Kegagalan dicatat di log dan dikembalikan sebagai peringatan ("" = tersimpan).
+func (s *AchievementService) saveVersion(
+	c *fiber.Ctx,
+	a *model.Achievement,
+	reason string,
+	round int,
+) string {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	v := model.AchievementVersion{
+		AchievementID: a.ID.Hex(),
+		Reason:        reason,
+		Round:         round,
+		Snapshot:      *a,
+		CreatedBy:     claims.UserID,
+		CreatedAt:     time.Now(),
+	}
+	if err := s.versionRepo.Create(c.Context(), &v); err != nil {
+		fmt.Println("⚠️ SAVE VERSION FAILED:", a.ID.Hex(), reason, err)
+		return versionNotSavedWarning
+	}
+	return ""
+}
+
+// withVersionWarning: tambahkan peringatan saveVersion ke response
+func withVersionWarning(resp fiber.Map, warning string) fiber.Map {
+	if warning != "" {
+		resp["version_warning"] = warning
+	}
+	return resp
+}
+
+// duplicateWarnings: kandidat duplikat untuk ditampilkan sebagai peringatan.
+// Gagal cek tidak membatalkan request. Mahasiswa tidak boleh tahu siapa lagi
+// yang punya prestasi / file serupa: kandidat yang bukan miliknya (atau timnya)
+// hanya berisi skor & alasan. Reviewer & admin melihat semuanya.
+func (s *AchievementService) duplicateWarnings(
+	c *fiber.Ctx,
+	a *model.Achievement,
+) []model.DuplicateCandidate {
+	candidates, err := s.duplicates.Find(c.Context(), a)
+	if err != nil {
+		fmt.Println("⚠️ DUPLICATE CHECK FAILED:", err)
+		return []model.DuplicateCandidate{}
+	}
+
+	claims := c.Locals("user").(*model.JWTClaims)
+	if claims.Role != "Mahasiswa" {
+		return candidates
+	}
+	for i, cand := range candidates {
+		if !slices.Contains(cand.StudentIDs, claims.StudentID) {
+			candidates[i] = model.DuplicateCandidate{Score: cand.Score, Reasons: cand.Reasons}
+		}
+	}
+	return candidates
+}
+
+// notifyStudent: kirim notifikasi ke akun user milik mahasiswa.
+// Gagal kirim tidak membatalkan action yang sudah tersimpan.
+func (s *AchievementService) notifyStudent(
+	ctx context.Context,
+	studentID string,
+	n model.Notification,
+) {
+	student, err := s.studentRepo.GetStudentByID(ctx, studentID)
+	if err != nil || student == nil {
+		fmt.Println("⚠️ NOTIFY: student not found:", studentID)
+		return
+	}
+
+	n.UserID = student.UserID
+	n.IsRead = false
+	n.CreatedAt = time.Now()
+
+	if err := s.notificationRepo.Create(ctx, &n); err != nil {
+		fmt.Println("⚠️ NOTIFY FAILED:", err)
+	}
+}
+
+/* =======================
+   WORKFLOW HELPERS
+======================= */
+
+// adviseesLocal: key c.Locals untuk anak wali dosen login (lihat isAdvisorOf)
+const adviseesLocal = "advisees"
+
+// isAdvisorOf: apakah user (Dosen Wali) adalah dosen wali dari salah satu studentIDs.
+// Daftar anak wali diambil sekali per request dan disimpan di c.Locals, jadi
+// bulk verify / reject tidak mengambil ulang dosen & anak walinya untuk tiap item.
+func (s *AchievementService) isAdvisorOf(
+	c *fiber.Ctx,
+	userID string,
+	studentIDs ...string,
+) (bool, error) {
+	advisees, ok := c.Locals(adviseesLocal).(map[string]bool)
+	if !ok {
+		lecturer, err := s.lecturerRepo.GetLecturerProfile(c.Context(), userID)
+		if err != nil {
+			return false, fiber.NewError(fiber.StatusForbidden, "lecturer profile not found")
+		}
+
+		students, err := s.studentRepo.GetStudentsByAdvisor(c.Context(), lecturer.ID)
+		if err != nil {
+			return false, fiber.NewError(500, "failed to fetch advisees")
+		}
+
+		advisees = make(map[string]bool, len(students))
+		for _, st := range students {
+			advisees[st.ID] = true
+		}
+		c.Locals(adviseesLocal, advisees)
+	}
+
+	for _, id := range studentIDs {
+		if advisees[id] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// achievementStudentIDs: pemilik + anggota tim, tanpa duplikat (pemilik di depan)
+func achievementStudentIDs(a *model.Achievement) []string {
+	ids := []string{a.StudentID}
+	for _, m := range a.Members {
+		if !slices.Contains(ids, m.StudentID) {
+			ids = append(ids, m.StudentID)
+		}
+	}
+	return ids
+}
+
+// normalizeMembers: validasi daftar anggota tim. Pemilik selalu ikut
+// (jadi leader kalau belum ada leader), maksimal satu leader.
+// Hasil nil kalau ternyata hanya pemilik → prestasi individu.
+func (s *AchievementService) normalizeMembers(
+	ctx context.Context,
+	ownerID string,
+	members []model.AchievementMember,
+) ([]model.AchievementMember, error) {
+	result := []model.AchievementMember{}
+	seen := map[string]bool{}
+	leaders := 0
+
+	for _, m := range members {
+		m.StudentID = strings.TrimSpace(m.StudentID)
+		if m.Role == "" {
+			m.Role = model.MemberRoleMember
+		}
+
+		switch {
+		case m.StudentID == "":
+			return nil, fiber.NewError(fiber.StatusBadRequest, "member student_id is required")
+		case seen[m.StudentID]:
+			return nil, fiber.NewError(fiber.StatusBadRequest, "duplicate member: "+m.StudentID)
+		case m.Role != model.MemberRoleLeader && m.Role != model.MemberRoleMember:
+			return nil, fiber.NewError(fiber.StatusBadRequest, "invalid member role: "+m.Role)
+		}
+
+		if m.StudentID != ownerID {
+			if st, err := s.studentRepo.GetStudentByID(ctx, m.StudentID); err != nil || st == nil {
+				return nil, fiber.NewError(fiber.StatusBadRequest, "member student not found: "+m.StudentID)
+			}
+		}
+
+		if m.Role == model.MemberRoleLeader {
+			leaders++
+		}
+		seen[m.StudentID] = true
+		result = append(result, m)
+	}
+
+	if leaders > 1 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "a team can only have one leader")
+	}
+
+	if !seen[ownerID] {
+		role := model.MemberRoleMember
+		if leaders == 0 {
+			role = model.MemberRoleLeader
+		}
+		result = append([]model.AchievementMember{{StudentID: ownerID, Role: role}}, result...)
+	}
+
+	if len(result) == 1 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func hasPermission(c *fiber.Ctx, permission string) bool {
+	perms, _ := c.Locals("permissions").([]string)
+	for _, p := range perms {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeActors: cek permission + role + relasi actor terhadap prestasi.
+// Anggota tim hanya diambil dari PostgreSQL kalau relasi pemilik tidak cukup.
+func (s *AchievementService) authorizeActors(
+	c *fiber.Ctx,
+	permission string,
+	actors []model.WorkflowActor,
+	achievementID string,
+	ownerID string,
+) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	if permission != "" && !hasPermission(c, permission) {
+		return fiber.NewError(fiber.StatusForbidden, "access denied")
+	}
+
+	var memberIDs []string
+	membersLoaded := false
+	members := func() ([]string, error) {
+		if !membersLoaded {
+			ids, err := s.referenceRepo.GetMemberIDs(c.Context(), achievementID)
+			if err != nil {
+				return nil, fiber.NewError(500, "failed to fetch achievement members")
+			}
+			memberIDs, membersLoaded = ids, true
+		}
+		return memberIDs, nil
+	}
+
+	for _, actor := range actors {
+		if actor.Role != claims.Role {
+			continue
+		}
+
+		switch actor.Relation {
+		case "":
+			return nil
+
+		case model.RelationOwner:
+			if claims.StudentID != "" && claims.StudentID == ownerID {
+				return nil
+			}
+
+		case model.RelationMember:
+			if claims.StudentID == "" {
+				continue
+			}
+			if claims.StudentID == ownerID {
+				return nil
+			}
+			ids, err := members()
+			if err != nil {
+				return err
+			}
+			if slices.Contains(ids, claims.StudentID) {
+				return nil
+			}
+
+		case model.RelationAdvisor:
+			ok, err := s.isAdvisorOf(c, claims.UserID, ownerID)
+			if err != nil {
+				return err
+			}
+			if ok {
+				return nil
+			}
+			// dosen wali anggota tim lain juga boleh
+			ids, err := members()
+			if err != nil {
+				return err
+			}
+			if len(ids) > 0 {
+				ok, err := s.isAdvisorOf(c, claims.UserID, ids...)
+				if err != nil {
+					return err
+				}
+				if ok {
+					return nil
+				}
+			}
+		}
+	}
+
+	return fiber.NewError(fiber.StatusForbidden, "access denied")
+}
+
+// checkTransition: action harus dikenal, user berhak, dan status sekarang valid.
+// Status tidak valid → 409 Conflict.
+func (s *AchievementService) checkTransition(
+	c *fiber.Ctx,
+	action string,
+	ref *model.AchievementReference,
+) (*model.WorkflowTransition, error) {
+
+	t, err := s.workflow.Lookup(action)
+	if err != nil {
+		return nil, fiber.NewError(500, "workflow action not configured: "+action)
+	}
+	if err := s.checkActors(c, t, action, t.Actors, ref.MongoAchievementID, ref); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// checkLevelTransition: verify / reject memakai actor tahap verifikasi sekarang,
+// bukan actor workflow (tahap fakultas hanya Admin)
+func (s *AchievementService) checkLevelTransition(
+	c *fiber.Ctx,
+	action string,
+	achievementID string,
+	ref *model.AchievementReference,
+	level model.ApprovalLevel,
+) (*model.WorkflowTransition, error) {
+
+	t, err := s.workflow.Lookup(action)
+	if err != nil {
+		return nil, fiber.NewError(500, "workflow action not configured: "+action)
+	}
+	if err := s.checkActors(c, t, action, level.Actors, achievementID, ref); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *AchievementService) checkActors(
+	c *fiber.Ctx,
+	t *model.WorkflowTransition,
+	action string,
+	actors []model.WorkflowActor,
+	achievementID string,
+	ref *model.AchievementReference,
+) error {
+
+	if err := s.authorizeActors(c, t.Permission, actors, achievementID, ref.StudentID); err != nil {
+		return err
+	}
+
+	if !s.workflow.Allows(action, ref.Status) {
+		return fiber.NewError(
+			fiber.StatusConflict,
+			fmt.Sprintf("cannot %s achievement with status %s", action, ref.Status),
+		)
+	}
+
+	return nil
+}
+
+// currentLevel: tahap verifikasi yang sedang menunggu (dibatasi tahap terakhir)
+func currentLevel(chain model.ApprovalChain, ref *model.AchievementReference) (int, model.ApprovalLevel) {
+	idx := min(ref.ApprovalLevel, len(chain.Levels)-1)
+	return idx, chain.Levels[idx]
+}
+
+// statusChange: bentuk perubahan status dari transisi workflow + user JWT
+func statusChange(c *fiber.Ctx, t *model.WorkflowTransition, note *string) model.StatusChange {
+	claims := c.Locals("user").(*model.JWTClaims)
+	return model.StatusChange{
+		From: t.From,
+		To:   t.To,
+		By:   claims.UserID,
+		Note: note,
+	}
+}
+
+// transitionError: status berubah di tengah jalan → 409, selain itu → 500
+func transitionError(err error, msg string) error {
+	if errors.Is(err, repository.ErrStatusConflict) {
+		return fiber.NewError(fiber.StatusConflict, "achievement status has changed, please reload")
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, msg)
+}
+
+// getAchievement: ambil dokumen MongoDB dari path id
+func (s *AchievementService) getAchievement(c *fiber.Ctx, achievementID string) (*model.Achievement, error) {
+	objID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid achievement id")
+	}
+
+	achievement, err := s.achievementRepo.GetByID(c.Context(), objID)
+	if err != nil || achievement.IsDeleted {
+		return nil, fiber.NewError(fiber.StatusNotFound, "achievement not found")
 	}
+	return achievement, nil
+}
+
+// validationError: 400 dengan daftar error per field
+func validationError(c *fiber.Ctx, errs []model.FieldError) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"message": "validation failed",
+		"errors":  errs,
+	})
 }
 
 // CreateAchievement godoc
 // @Summary Buat prestasi baru
-// @Description Mahasiswa otomatis pakai student_id dari JWT, Admin wajib mengisi studentId
+// @Description Mahasiswa otomatis pakai student_id dari JWT, Admin wajib mengisi studentId.
+// @Description Prestasi tim: isi members (student_id + role leader/member), pemilik otomatis ikut.
+// @Description Response berisi warnings: prestasi lain yang kemungkinan duplikat (untuk mahasiswa, milik mahasiswa lain hanya skor & alasan).
+// @Description templateId (opsional): tipe, details bawaan, description & tags dari template; judul dari title_pattern kalau title kosong.
 // @Tags Achievements
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param body body service.CreateAchievementRequest true "Create Achievement Payload"
 // @Success 201 {object} map[string]interface{} "Achievement created"
-// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 400 {object} map[string]interface{} "Invalid request / details validation failed"
 // @Failure 403 {object} map[string]interface{} "Access denied"
 // @Failure 500 {object} map[string]interface{} "Failed to create achievement"
 // @Router /achievements [post]
@@ -113,60 +632,166 @@ func (s *AchievementService) CreateAchievement(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusForbidden, "access denied")
 	}
 
+	return s.createAchievement(c, studentID, req, nil)
+}
+
+// createAchievement: simpan prestasi baru (MongoDB + PostgreSQL) dari request
+// yang pemiliknya sudah ditentukan. renews diisi saat perpanjangan sertifikasi.
+func (s *AchievementService) createAchievement(
+	c *fiber.Ctx,
+	studentID string,
+	req CreateAchievementRequest,
+	renews *model.Achievement,
+) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	// mulai dari template (opsional)
+	var template *model.AchievementTemplate
+	if req.TemplateID != "" {
+		t, err := s.getActiveTemplate(c, req.TemplateID)
+		if err != nil {
+			return err
+		}
+		if errs := applyTemplate(t, &req); len(errs) > 0 {
+			return validationError(c, errs)
+		}
+		template = t
+	}
+
+	// validasi details sesuai skema tipe prestasi
+	if req.Details == nil {
+		req.Details = map[string]any{}
+	}
+	if errs := s.schemas.Validate(req.AchievementType, req.Details); len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	// judul dari pola template kalau mahasiswa tidak mengisi sendiri
+	if template != nil && strings.TrimSpace(req.Title) == "" {
+		title, errs := renderTitle(template.TitlePattern, req.Details)
+		if len(errs) > 0 {
+			return validationError(c, errs)
+		}
+		req.Title = title
+	}
+
+	// anggota tim (opsional)
+	members, err := s.normalizeMembers(c.Context(), studentID, req.Members)
+	if err != nil {
+		return err
+	}
+
 	now := time.Now()
 
 	achievement := &model.Achievement{
 		StudentID:       studentID,
+		Members:         members,
 		AchievementType: req.AchievementType,
 		Title:           req.Title,
 		Description:     req.Description,
 		Details:         req.Details,
 		Tags:            req.Tags,
-		Points:          req.Points,
 		Attachments:     []model.Attachment{},
+		TemplateID:      req.TemplateID,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
+	if errs := syncValidity(achievement); len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	// sertifikasi lama diklaim dulu (atomik) supaya dua perpanjangan paralel
+	// tidak sama-sama lolos; ID prestasi baru ditentukan di sini
+	if renews != nil {
+		achievement.ID = primitive.NewObjectID()
+		achievement.RenewsID = renews.ID.Hex()
+		claimed, err := s.achievementRepo.ClaimRenewal(c.Context(), renews.ID, achievement.ID.Hex())
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to renew certification")
+		}
+		if !claimed {
+			return fiber.NewError(fiber.StatusConflict, "certification already renewed")
+		}
+	}
 
-	// 1️⃣ MongoDB
+	// 1️⃣ MongoDB (gagal → klaim perpanjangan dilepas)
 	oid, err := s.achievementRepo.Create(c.Context(), achievement)
 	if err != nil {
+		s.releaseRenewal(c.Context(), renews, achievement.ID.Hex())
 		return fiber.NewError(500, "failed to create achievement")
 	}
 
-	// 2️⃣ PostgreSQL reference
+	// 2️⃣ PostgreSQL reference (gagal → kompensasi dokumen MongoDB)
 	if err := s.referenceRepo.CreateDraft(
 		c.Context(),
 		studentID, // ✅ FIX
 		oid.Hex(),
+		claims.UserID,
 	); err != nil {
+		s.compensateCreate(c.Context(), oid)
+		s.releaseRenewal(c.Context(), renews, achievement.ID.Hex())
 		return fiber.NewError(500, "failed to create achievement reference")
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "achievement created",
-		"data":    achievement,
-	})
+	// 3️⃣ anggota tim di PostgreSQL (dipakai filter & otorisasi)
+	if len(members) > 0 {
+		if err := s.referenceRepo.SetMembers(c.Context(), oid.Hex(), members); err != nil {
+			s.compensateCreate(c.Context(), oid)
+			s.releaseRenewal(c.Context(), renews, achievement.ID.Hex())
+			return fiber.NewError(500, "failed to save achievement members")
+		}
+	}
+
+	versionWarning := s.saveVersion(c, achievement, model.VersionCreate, 0)
+
+	// 4️⃣ peringatan kemungkinan duplikat (tidak memblokir)
+	resp := fiber.Map{
+		"message":  "achievement created",
+		"data":     achievement,
+		"warnings": s.duplicateWarnings(c, achievement),
+	}
+	if template != nil {
+		resp["required_attachments"] = template.RequiredAttachments
+	}
+	return c.Status(fiber.StatusCreated).JSON(withVersionWarning(resp, versionWarning))
+}
+
+// getActiveTemplate: template untuk create; nonaktif dianggap tidak ada
+func (s *AchievementService) getActiveTemplate(c *fiber.Ctx, templateID string) (*model.AchievementTemplate, error) {
+	id, err := primitive.ObjectIDFromHex(templateID)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid template id")
+	}
+
+	t, err := s.templateRepo.GetByID(c.Context(), id)
+	if err != nil || !t.IsActive {
+		return nil, fiber.NewError(fiber.StatusNotFound, "template not found")
+	}
+	return t, nil
 }
 
 // UploadAttachment godoc
 // @Summary Upload lampiran prestasi
 // @Description
-// Hanya boleh jika status prestasi masih draft
+// Hanya boleh jika status prestasi masih draft.
+// Tipe file dicek dari isi file sesuai skema tipe prestasi (uploads.file_types);
+// file identik disimpan sekali, upload ulang ke prestasi yang sama mengembalikan lampiran lama.
 // @Tags Achievements
 // @Security BearerAuth
 // @Accept multipart/form-data
 // @Produce json
 // @Param id path string true "Achievement ID"
 // @Param file formData file true "Attachment file"
+// @Param kind formData string false "Jenis bukti: certificate, appointment_letter, publication, photo, other (default other)"
 // @Success 200 {object} map[string]interface{} "Attachment uploaded"
 // @Failure 400 {object} map[string]interface{} "Invalid request"
 // @Failure 403 {object} map[string]interface{} "Access denied"
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 413 {object} map[string]interface{} "File too large / storage quota exceeded"
+// @Failure 415 {object} map[string]interface{} "File type not allowed"
 // @Failure 500 {object} map[string]interface{} "Failed to upload attachment"
 // @Router /achievements/{id}/attachments [post]
 func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
-	claims := c.Locals("user").(*model.JWTClaims)
 	achievementID := c.Params("id")
 
 	// 1️⃣ cek reference (PostgreSQL)
@@ -178,25 +803,9 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
 	}
 
-	if ref.Status != "draft" {
-		return fiber.NewError(
-			fiber.StatusBadRequest,
-			"attachments only allowed for draft achievement",
-		)
-	}
-
-	// 2️⃣ auth
-	if claims.Role == "Mahasiswa" {
-
-		// pastikan student_id ada di JWT
-		if claims.StudentID == "" {
-			return fiber.NewError(fiber.StatusForbidden, "student id missing")
-		}
-
-		// bandingkan STUDENT ID vs STUDENT ID
-		if ref.StudentID != claims.StudentID {
-			return fiber.NewError(fiber.StatusForbidden, "access denied")
-		}
+	// 2️⃣ workflow + auth (default: hanya draft, pemilik / admin)
+	if _, err := s.checkTransition(c, model.ActionUploadAttachment, ref); err != nil {
+		return err
 	}
 
 	// 3️⃣ ambil file
@@ -205,35 +814,65 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "file is required")
 	}
 
-	// ✅ pastikan folder uploads ada
-	_ = os.MkdirAll("./uploads", 0755)
+	// jenis bukti (dipakai cek evidence saat submit)
+	kind, err := attachmentKind(c, model.EvidenceOther)
+	if err != nil {
+		return err
+	}
 
-	// 4️⃣ simpan file
-	fileURL := "/uploads/" + file.Filename
-	if err := c.SaveFile(file, "."+fileURL); err != nil {
-		return fiber.NewError(500, "failed to save file")
+	// 4️⃣ aturan lampiran sesuai tipe prestasi (MongoDB)
+	achievement, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return err
 	}
 
-	// 5️⃣ parse ObjectID (PAKAI YANG ATAS)
-	objID, err := primitive.ObjectIDFromHex(achievementID)
+	// 5️⃣ ukuran, tipe asli dari isi file + hash (deteksi duplikat & key storage)
+	attachment, err := s.prepareUpload(achievement, file, kind)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "invalid achievement id")
+		return err
 	}
 
-	attachment := model.Attachment{
-		FileName:   file.Filename,
-		FileURL:    fileURL,
-		FileType:   file.Header.Get("Content-Type"),
-		UploadedAt: time.Now(),
+	// isi yang sama sudah dilampirkan → kembalikan lampiran lama
+	for _, att := range achievement.Attachments {
+		if att.Hash == attachment.Hash {
+			return c.JSON(fiber.Map{
+				"message": "attachment already uploaded",
+				"data":    att,
+			})
+		}
+	}
+
+	// kuota penyimpanan prestasi & mahasiswa (dipesan sampai lampiran tercatat)
+	release, q, err := s.reserveQuota(c.Context(), achievement, attachment.Size, 0)
+	if err != nil || q != nil {
+		return quotaResponse(c, q, err)
+	}
+	defer release()
+
+	// 6️⃣ simpan ke storage kalau isi ini belum pernah diupload
+	created, err := s.putUpload(c.Context(), file, attachment)
+	if err != nil {
+		return err
 	}
 
-	if err := s.achievementRepo.AddAttachment(
+	// 7️⃣ catat di MongoDB; gagal → hapus object baru supaya tidak jadi sampah
+	added, err := s.achievementRepo.AddAttachment(
 		c.Context(),
-		objID,
+		achievement.ID,
 		attachment,
-	); err != nil {
+	)
+	if err != nil {
+		if created {
+			s.discardUpload(c.Context(), attachment)
+		}
 		return fiber.NewError(500, "failed to save attachment")
 	}
+	if !added {
+		return s.alreadyAttached(c, achievement.ID, attachment)
+	}
+
+	// 8️⃣ thumbnail / pratinjau dibuat di background
+	s.enqueuePreview(achievement.ID, attachment)
 
 	return c.JSON(fiber.Map{
 		"message": "attachment uploaded",
@@ -252,13 +891,12 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 // @Param id path string true "Achievement ID"
 // @Param body body service.UpdateAchievementRequest true "Update Achievement Payload"
 // @Success 200 {object} map[string]interface{} "Achievement updated"
-// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 400 {object} map[string]interface{} "Invalid request / details validation failed"
 // @Failure 403 {object} map[string]interface{} "Access denied"
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
 // @Failure 500 {object} map[string]interface{} "Failed to update achievement"
 // @Router /achievements/{id} [put]
 func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
-	claims := c.Locals("user").(*model.JWTClaims)
 	achievementID := c.Params("id")
 
 	// parse ObjectID
@@ -273,15 +911,9 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
 	}
 
-	if ref.Status != "draft" {
-		return fiber.NewError(fiber.StatusBadRequest, "only draft achievements can be updated")
-	}
-
-	// 2️⃣ cek authorization
-	if claims.Role == "Mahasiswa" {
-		if claims.StudentID == "" || ref.StudentID != claims.StudentID {
-			return fiber.NewError(fiber.StatusForbidden, "access denied")
-		}
+	// 2️⃣ workflow + authorization
+	if _, err := s.checkTransition(c, model.ActionUpdate, ref); err != nil {
+		return err
 	}
 
 	// 3️⃣ parse request
@@ -304,25 +936,50 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		achievement.Description = *req.Description
 	}
 	if req.Details != nil {
+		// details baru harus lolos skema tipe prestasi
+		if errs := s.schemas.Validate(achievement.AchievementType, req.Details); len(errs) > 0 {
+			return validationError(c, errs)
+		}
 		achievement.Details = req.Details
+		if errs := syncValidity(achievement); len(errs) > 0 {
+			return validationError(c, errs)
+		}
 	}
 	if req.Tags != nil {
 		achievement.Tags = req.Tags
 	}
-	if req.Points != nil {
-		achievement.Points = *req.Points
+	previousMembers := achievement.Members
+	if req.Members != nil {
+		members, err := s.normalizeMembers(c.Context(), achievement.StudentID, req.Members)
+		if err != nil {
+			return err
+		}
+		achievement.Members = members
 	}
 	achievement.UpdatedAt = time.Now()
 
-	// 6️⃣ simpan perubahan
+	// 6️⃣ anggota tim (PostgreSQL, dipakai filter & otorisasi) dulu
+	if req.Members != nil {
+		if err := s.referenceRepo.SetMembers(c.Context(), achievementID, achievement.Members); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to save achievement members")
+		}
+	}
+
+	// 7️⃣ simpan field yang diedit saja (MongoDB); lampiran & pratinjau yang
+	// berubah bersamaan tidak tertimpa. Gagal → anggota tim dikembalikan
 	if err := s.achievementRepo.Update(c.Context(), achievement); err != nil {
+		if req.Members != nil {
+			s.restoreMembers(c.Context(), achievementID, previousMembers)
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, "failed to update achievement")
 	}
 
-	return c.JSON(fiber.Map{
+	versionWarning := s.saveVersion(c, achievement, model.VersionUpdate, ref.ResubmissionCount)
+
+	return c.JSON(withVersionWarning(fiber.Map{
 		"message": "achievement updated",
 		"data":    achievement,
-	})
+	}, versionWarning))
 }
 
 // DeleteAchievement godoc
@@ -341,7 +998,6 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 // @Failure 500 {object} map[string]interface{} "Failed to delete achievement"
 // @Router /achievements/{id} [delete]
 func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
-	claims := c.Locals("user").(*model.JWTClaims)
 	achievementID := c.Params("id")
 
 	// parse ObjectID
@@ -356,32 +1012,29 @@ func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
 	}
 
-	// hanya draft yang bisa dihapus
-	if ref.Status != "draft" {
-		return fiber.NewError(fiber.StatusBadRequest, "only draft achievements can be deleted")
+	// 2️⃣ workflow + authorization (default: hanya draft)
+	t, err := s.checkTransition(c, model.ActionDelete, ref)
+	if err != nil {
+		return err
 	}
 
-	// 2️⃣ cek authorization
-	switch claims.Role {
-	case "Mahasiswa":
-		if claims.StudentID == "" || ref.StudentID != claims.StudentID {
-			return fiber.NewError(fiber.StatusForbidden, "access denied")
-		}
-	case "Admin":
-		// admin bisa delete semua
-	default:
-		return fiber.NewError(fiber.StatusForbidden, "access denied")
+	// 3️⃣ update PostgreSQL reference (atomic terhadap status)
+	if _, err := s.referenceRepo.UpdateStatus(
+		c.Context(),
+		achievementID,
+		statusChange(c, t, nil),
+	); err != nil {
+		return transitionError(err, "failed to update achievement reference")
 	}
 
-	// 3️⃣ soft delete MongoDB
+	// 4️⃣ soft delete MongoDB (gagal → kembalikan status reference)
 	if err := s.achievementRepo.SoftDelete(c.Context(), objID); err != nil {
+		s.revertStatus(c, achievementID, t, ref.Status)
 		return fiber.NewError(fiber.StatusInternalServerError, "failed to soft delete achievement")
 	}
 
-	// 4️⃣ update PostgreSQL reference
-	if err := s.referenceRepo.MarkDeleted(c.Context(), achievementID); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "failed to update achievement reference")
-	}
+	// 5️⃣ draft perpanjangan: sertifikasi lama bisa diperpanjang lagi
+	s.releaseRenewalBy(c.Context(), achievementID)
 
 	return c.JSON(fiber.Map{
 		"message": "achievement deleted",
@@ -391,8 +1044,8 @@ func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
 // GetAchievements godoc
 // @Summary Ambil daftar prestasi
 // @Description
-// Mahasiswa: hanya prestasi miliknya
-// Dosen Wali: prestasi mahasiswa bimbingan
+// Mahasiswa: prestasi miliknya + prestasi tim yang diikuti
+// Dosen Wali: prestasi mahasiswa bimbingan (termasuk sebagai anggota tim)
 // Admin: semua prestasi
 // @Tags Achievements
 // @Security BearerAuth
@@ -520,9 +1173,10 @@ func (s *AchievementService) GetAchievements(c *fiber.Ctx) error {
 // GetAchievementByID godoc
 // @Summary Ambil detail prestasi
 // @Description
-// Mahasiswa hanya boleh lihat prestasi sendiri
-// Dosen Wali hanya prestasi mahasiswa bimbingan
+// Mahasiswa hanya boleh lihat prestasi sendiri / prestasi tim yang diikuti
+// Dosen Wali hanya prestasi mahasiswa bimbingan (termasuk anggota tim)
 // Admin bebas
+// Dosen Wali / Admin juga mendapat possible_duplicates (prestasi lain yang mirip)
 // @Tags Achievements
 // @Security BearerAuth
 // @Accept json
@@ -547,53 +1201,17 @@ func (s *AchievementService) GetAchievementByID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "achievement not found")
 	}
 
-	switch claims.Role {
-
-	case "Mahasiswa":
-		student, err := s.studentRepo.GetStudentProfile(
-			c.Context(),
-			claims.UserID,
-		)
-		if err != nil || achievement.StudentID != student.ID {
-			return fiber.NewError(fiber.StatusForbidden, "access denied")
-		}
-
-	case "Dosen Wali":
-		// 1️⃣ ambil lecturer dari user_id
-		lecturer, err := s.lecturerRepo.GetLecturerProfile(
-			c.Context(),
-			claims.UserID,
-		)
-		if err != nil {
-			return fiber.NewError(fiber.StatusForbidden, "lecturer profile not found")
-		}
-
-		// 2️⃣ ambil mahasiswa bimbingan
-		students, err := s.studentRepo.GetStudentsByAdvisor(
-			c.Context(),
-			lecturer.ID, // ✅ advisor_id
-		)
-		if err != nil {
-			return fiber.NewError(500, "failed to fetch advisees")
-		}
-
-		allowed := false
-		for _, st := range students {
-			if st.ID == achievement.StudentID {
-				allowed = true
-				break
-			}
-		}
-
-		if !allowed {
-			return fiber.NewError(fiber.StatusForbidden, "access denied")
-		}
-
-	case "Admin":
-		// full access
+	// anggota tim, dosen wali salah satu anggota, admin
+	if err := s.authorizeActors(c, "", achievementParticipants, id, achievement.StudentID); err != nil {
+		return err
+	}
 
-	default:
-		return fiber.NewError(fiber.StatusForbidden, "access denied")
+	// reviewer melihat panel "kemungkinan duplikat"
+	if claims.Role != "Mahasiswa" {
+		return c.JSON(fiber.Map{
+			"data":                achievement,
+			"possible_duplicates": s.duplicateWarnings(c, achievement),
+		})
 	}
 
 	return c.JSON(fiber.Map{"data": achievement})
@@ -602,19 +1220,22 @@ func (s *AchievementService) GetAchievementByID(c *fiber.Ctx) error {
 // SubmitAchievement godoc
 // @Summary Submit prestasi untuk verifikasi
 // @Description
-// Mengubah status dari draft ke submitted
+// Mengubah status dari draft ke submitted.
+// Response berisi warnings: prestasi lain yang kemungkinan duplikat (untuk mahasiswa, milik mahasiswa lain hanya skor & alasan).
+// Bukti wajib per tipe (dan required_attachments template asal) dicek;
+// yang kurang dikirim di unmet_requirements.
 // @Tags Achievements
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Achievement ID"
 // @Success 200 {object} map[string]interface{} "Achievement submitted"
-// @Failure 400 {object} map[string]interface{} "Invalid status"
+// @Failure 400 {object} map[string]interface{} "Details do not match achievement type schema / evidence requirements not met"
 // @Failure 403 {object} map[string]interface{} "Access denied"
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 409 {object} map[string]interface{} "Invalid status"
 // @Router /achievements/{id}/submit [post]
 func (s *AchievementService) SubmitAchievement(c *fiber.Ctx) error {
-	claims := c.Locals("user").(*model.JWTClaims)
 	achievementID := c.Params("id")
 
 	// 1️⃣ ambil reference
@@ -626,138 +1247,256 @@ func (s *AchievementService) SubmitAchievement(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
 	}
 
-	// 2️⃣ hanya draft
-	if ref.Status != "draft" {
-		return fiber.NewError(
-			fiber.StatusBadRequest,
-			"only draft achievement can be submitted",
-		)
+	// 2️⃣ workflow + authorization
+	t, err := s.checkTransition(c, model.ActionSubmit, ref)
+	if err != nil {
+		return err
 	}
 
-	// 3️⃣ authorization
-	switch claims.Role {
-	case "Mahasiswa":
-		if claims.StudentID == "" || ref.StudentID != claims.StudentID {
-			return fiber.NewError(fiber.StatusForbidden, "access denied")
-		}
+	// 3️⃣ tentukan tahap verifikasi pertama dari tipe prestasi
+	achievement, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return err
+	}
 
-	case "Admin":
-		// allowed
+	// details wajib lengkap sebelum masuk antrian verifikasi;
+	// ejaan lama yang dinormalisasi ikut disimpan
+	details := maps.Clone(achievement.Details)
+	if details == nil {
+		details = map[string]any{}
+	}
+	if errs := s.schemas.Validate(achievement.AchievementType, details); len(errs) > 0 {
+		return validationError(c, errs)
+	}
+	achievement.Details = details
+	if errs := syncValidity(achievement); len(errs) > 0 {
+		return validationError(c, errs)
+	}
 
-	default:
-		return fiber.NewError(fiber.StatusForbidden, "access denied")
+	// bukti wajib sesuai tipe (mis. sertifikat PDF untuk kompetisi),
+	// ditambah lampiran wajib template kalau dibuat dari template
+	templateUnmet, err := s.templateEvidence(c.Context(), achievement)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to check template requirements")
 	}
+	if unmet := append(s.schemas.CheckEvidence(achievement), templateUnmet...); len(unmet) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message":            "evidence requirements not met",
+			"unmet_requirements": unmet,
+		})
+	}
+
+	// poin dihitung server dari tabel aturan; override ronde sebelumnya gugur
+	achievement.PointsOverride = nil
+	s.points.Apply(achievement)
+	achievement.UpdatedAt = time.Now()
+
+	chain := s.approvals.Resolve(achievement)
 
-	// 4️⃣ update status → submitted
+	// 4️⃣ update status → submitted (atomic terhadap status)
 	updatedRef, err := s.referenceRepo.Submit(
 		c.Context(),
 		achievementID,
+		statusChange(c, t, nil),
+		chain.Levels[0].Name,
 	)
 	if err != nil {
-		return fiber.NewError(
-			fiber.StatusInternalServerError,
-			"failed to submit achievement",
-		)
+		return transitionError(err, "failed to submit achievement")
 	}
 
+	// simpan details + poin (MongoDB) setelah submit lolos; gagal → kembalikan status reference
+	if err := s.achievementRepo.SetSubmission(c.Context(), achievement); err != nil {
+		s.revertStatus(c, achievementID, t, ref.Status)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to calculate achievement points")
+	}
+
+	// snapshot = persis yang akan dilihat reviewer
+	versionWarning := s.saveVersion(c, achievement, model.VersionSubmit, ref.ResubmissionCount)
+
+	// 5️⃣ peringatan kemungkinan duplikat (lampiran sudah lengkap di tahap ini)
+	warnings := s.duplicateWarnings(c, achievement)
+
 	// 6️⃣ response
-	return c.JSON(fiber.Map{
-		"message": "achievement submitted for verification",
-		"data":    updatedRef,
-	})
+	return c.JSON(withVersionWarning(fiber.Map{
+		"message":  "achievement submitted for verification",
+		"data":     updatedRef,
+		"warnings": warnings,
+	}, versionWarning))
 }
 
 // VerifyAchievement godoc
 // @Summary Verifikasi prestasi
 // @Description
-// Hanya untuk Dosen Wali dan Admin
+// Menyetujui tahap verifikasi yang sedang berjalan (mis. dosen wali, lalu fakultas).
+// Status baru menjadi verified setelah tahap terakhir disetujui.
+// Verifikator boleh mengganti poin hasil hitungan dengan mengisi points + justification.
 // @Tags Achievements
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Achievement ID"
-// @Success 200 {object} map[string]interface{} "Achievement verified"
-// @Failure 400 {object} map[string]interface{} "Invalid status"
+// @Param body body service.VerifyAchievementRequest false "Verify Payload"
+// @Success 200 {object} map[string]interface{} "Achievement verified / level approved"
+// @Failure 400 {object} map[string]interface{} "Invalid points override"
 // @Failure 403 {object} map[string]interface{} "Access denied"
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 409 {object} map[string]interface{} "Invalid status"
 // @Router /achievements/{id}/verify [post]
 func (s *AchievementService) VerifyAchievement(c *fiber.Ctx) error {
-	claims := c.Locals("user").(*model.JWTClaims)
 	achievementID := c.Params("id")
 
-	// 1️⃣ role check
-	switch claims.Role {
-	case "Dosen Wali", "Admin":
-		// allowed
-	default:
-		return fiber.NewError(fiber.StatusForbidden, "access denied")
+	// body opsional
+	var req VerifyAchievementRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
 	}
 
-	// 2️⃣ ambil reference
+	updatedRef, message, err := s.verifyOne(c, achievementID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": message,
+		"data":    updatedRef,
+	})
+}
+
+// verifyOne: setujui tahap verifikasi untuk satu prestasi.
+// Dipakai VerifyAchievement dan BulkVerifyAchievements.
+func (s *AchievementService) verifyOne(
+	c *fiber.Ctx,
+	achievementID string,
+	req VerifyAchievementRequest,
+) (*model.AchievementReference, string, error) {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	// 1️⃣ ambil reference
 	ref, err := s.referenceRepo.GetByAchievementID(
 		c.Context(),
 		achievementID,
 	)
 	if err != nil {
-		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+		return nil, "", fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
 	}
 
-	// 3️⃣ hanya submitted yang bisa diverifikasi
-	if ref.Status != "submitted" {
-		return fiber.NewError(
-			fiber.StatusBadRequest,
-			"only submitted achievement can be verified",
-		)
+	// 2️⃣ ambil achievement → chain verifikasi sesuai tipe
+	achievement, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return nil, "", err
 	}
+	chain := s.approvals.Resolve(achievement)
 
-	// 4️⃣ khusus dosen wali → pastikan mahasiswa adalah anak walinya
-	if claims.Role == "Dosen Wali" {
-		lecturer, err := s.lecturerRepo.GetLecturerProfile(
-			c.Context(),
-			claims.UserID,
-		)
-		if err != nil {
-			return fiber.NewError(fiber.StatusForbidden, "lecturer profile not found")
-		}
+	// 3️⃣ tahap sekarang; actor tahap menggantikan actor workflow "verify"
+	levelIdx, level := currentLevel(chain, ref)
+	t, err := s.checkLevelTransition(c, model.ActionVerify, achievementID, ref, level)
+	if err != nil {
+		return nil, "", err
+	}
 
-		students, err := s.studentRepo.GetStudentsByAdvisor(
-			c.Context(),
-			lecturer.ID,
-		)
-		if err != nil {
-			return fiber.NewError(500, "failed to fetch advisees")
+	// 4️⃣ satu orang tidak boleh menyetujui dua tahap di ronde yang sama
+	approvals, err := s.referenceRepo.GetApprovals(c.Context(), achievementID)
+	if err != nil {
+		return nil, "", fiber.NewError(fiber.StatusInternalServerError, "failed to fetch approvals")
+	}
+	for _, a := range approvals {
+		if a.Round == ref.ResubmissionCount && a.ApprovedBy == claims.UserID {
+			return nil, "", fiber.NewError(fiber.StatusConflict, "you already approved a previous level")
 		}
+	}
 
-		allowed := false
-		for _, st := range students {
-			if st.ID == ref.StudentID {
-				allowed = true
-				break
-			}
+	var note *string
+	if req.Note != "" {
+		note = &req.Note
+	}
+
+	// override poin wajib disertai alasan
+	if req.Points != nil {
+		req.Justification = strings.TrimSpace(req.Justification)
+		if *req.Points < 0 {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "points must not be negative")
+		}
+		if req.Justification == "" {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "justification is required when overriding points")
 		}
+	}
+
+	nextLevel := ""
+	if levelIdx+1 < len(chain.Levels) {
+		nextLevel = chain.Levels[levelIdx+1].Name
+	}
 
-		if !allowed {
-			return fiber.NewError(fiber.StatusForbidden, "access denied")
+	// 5️⃣ override poin (MongoDB) disimpan sebelum approve; hanya field poin
+	// yang ditulis, jadi perubahan lain pada dokumen tidak tertimpa
+	var previous *model.Achievement
+	if req.Points != nil {
+		previous = &model.Achievement{
+			ID:             achievement.ID,
+			Points:         achievement.Points,
+			PointsVersion:  achievement.PointsVersion,
+			PointsOverride: achievement.PointsOverride,
+		}
+		calculated, _ := s.points.Calculate(achievement)
+		achievement.PointsOverride = &model.PointsOverride{
+			Points:           *req.Points,
+			CalculatedPoints: calculated,
+			Justification:    req.Justification,
+			By:               claims.UserID,
+			At:               time.Now(),
+		}
+		s.points.Apply(achievement)
+		if err := s.achievementRepo.SetPoints(c.Context(), achievement); err != nil {
+			return nil, "", fiber.NewError(fiber.StatusInternalServerError, "failed to save points override")
 		}
 	}
 
-	// 5️⃣ verify (PostgreSQL)
-	updatedRef, err := s.referenceRepo.Verify(
+	// 6️⃣ approve (PostgreSQL) — tahap terakhir sekalian verified;
+	// gagal → override poin dikembalikan
+	updatedRef, err := s.referenceRepo.Approve(
 		c.Context(),
 		achievementID,
-		claims.UserID,
+		statusChange(c, t, note),
+		model.AchievementApproval{
+			Round:      ref.ResubmissionCount,
+			Level:      ref.ApprovalLevel,
+			LevelName:  level.Name,
+			ApprovedBy: claims.UserID,
+			Note:       note,
+		},
+		nextLevel,
 	)
 	if err != nil {
-		return fiber.NewError(
-			fiber.StatusInternalServerError,
-			"failed to verify achievement",
-		)
+		if previous != nil {
+			s.restorePoints(c.Context(), previous)
+		}
+		return nil, "", transitionError(err, "failed to verify achievement")
 	}
 
-	return c.JSON(fiber.Map{
-		"message": "achievement verified",
-		"data":    updatedRef,
-	})
+	if nextLevel != "" {
+		return updatedRef, fmt.Sprintf("level %s approved, waiting for %s", level.Name, nextLevel), nil
+	}
+
+	return updatedRef, "achievement verified", nil
+}
+
+// relationAllows: filter antrian per relasi actor. allowed berisi anak wali
+// (advisor) atau mahasiswa login (owner/member); prestasi tim lolos kalau
+// salah satu anggotanya cocok.
+func relationAllows(relation string, a *model.Achievement, allowed map[string]bool) bool {
+	switch relation {
+	case "":
+		return true
+	case model.RelationOwner:
+		return allowed[a.StudentID]
+	}
+	for _, id := range achievementStudentIDs(a) {
+		if allowed[id] {
+			return true
+		}
+	}
+	return false
 }
 
 // / RejectAchievement godoc
@@ -775,18 +1514,9 @@ func (s *AchievementService) VerifyAchievement(c *fiber.Ctx) error {
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
 // @Router /achievements/{id}/reject [post]
 func (s *AchievementService) RejectAchievement(c *fiber.Ctx) error {
-	claims := c.Locals("user").(*model.JWTClaims)
 	achievementID := c.Params("id")
 
-	// 1️⃣ role check
-	switch claims.Role {
-	case "Dosen Wali", "Admin":
-		// allowed
-	default:
-		return fiber.NewError(fiber.StatusForbidden, "access denied")
-	}
-
-	// 2️⃣ parse request
+	// 1️⃣ parse request
 	var req RejectAchievementRequest
 	if err := c.BodyParser(&req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
@@ -796,76 +1526,562 @@ func (s *AchievementService) RejectAchievement(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "rejection_note is required")
 	}
 
-	// 3️⃣ ambil reference
+	updatedRef, err := s.rejectOne(c, achievementID, req.RejectionNote)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "achievement rejected",
+		"data":    updatedRef,
+	})
+}
+
+// rejectOne: tolak satu prestasi.
+// Dipakai RejectAchievement dan BulkRejectAchievements.
+func (s *AchievementService) rejectOne(
+	c *fiber.Ctx,
+	achievementID string,
+	note string,
+) (*model.AchievementReference, error) {
+
+	// 1️⃣ ambil reference
 	ref, err := s.referenceRepo.GetByAchievementID(
 		c.Context(),
 		achievementID,
 	)
 	if err != nil {
-		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+		return nil, fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
 	}
 
-	// 4️⃣ hanya submitted
-	if ref.Status != "submitted" {
-		return fiber.NewError(
-			fiber.StatusBadRequest,
-			"only submitted achievement can be rejected",
-		)
+	// 2️⃣ actor tahap sekarang, sama dengan verify (dosen wali hanya anak walinya,
+	// tahap fakultas hanya Admin)
+	achievement, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return nil, err
+	}
+	_, level := currentLevel(s.approvals.Resolve(achievement), ref)
+	t, err := s.checkLevelTransition(c, model.ActionReject, achievementID, ref, level)
+	if err != nil {
+		return nil, err
 	}
 
-	// 5️⃣ dosen wali → validasi anak wali
-	if claims.Role == "Dosen Wali" {
-		lecturer, err := s.lecturerRepo.GetLecturerProfile(
-			c.Context(),
-			claims.UserID,
-		)
-		if err != nil {
-			return fiber.NewError(fiber.StatusForbidden, "lecturer profile not found")
+	// 3️⃣ reject (PostgreSQL)
+	updatedRef, err := s.referenceRepo.Reject(
+		c.Context(),
+		achievementID,
+		statusChange(c, t, &note),
+	)
+	if err != nil {
+		return nil, transitionError(err, "failed to reject achievement")
+	}
+
+	return updatedRef, nil
+}
+
+// BulkVerifyAchievements godoc
+// @Summary Verifikasi banyak prestasi sekaligus
+// @Description
+// Setiap ID diproses sendiri-sendiri dengan aturan yang sama seperti /achievements/{id}/verify
+// (dosen wali hanya anak walinya). Satu item gagal tidak membatalkan item lain.
+// @Tags Achievements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body service.BulkVerifyRequest true "Bulk Verify Payload"
+// @Success 200 {object} map[string]interface{} "Per-item result report"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Router /achievements/bulk/verify [post]
+func (s *AchievementService) BulkVerifyAchievements(c *fiber.Ctx) error {
+	var req BulkVerifyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	ids, err := bulkIDs(req.IDs)
+	if err != nil {
+		return err
+	}
+
+	results := make([]BulkItemResult, 0, len(ids))
+	for _, id := range ids {
+		updatedRef, message, err := s.verifyOne(c, id, VerifyAchievementRequest{Note: req.Note})
+		results = append(results, bulkItemResult(id, updatedRef, message, err))
+	}
+
+	return bulkResponse(c, results)
+}
+
+// BulkRejectAchievements godoc
+// @Summary Tolak banyak prestasi sekaligus
+// @Description
+// Setiap ID diproses sendiri-sendiri dengan aturan yang sama seperti /achievements/{id}/reject.
+// Catatan penolakan yang sama dipakai untuk semua item.
+// @Tags Achievements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body service.BulkRejectRequest true "Bulk Reject Payload"
+// @Success 200 {object} map[string]interface{} "Per-item result report"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Router /achievements/bulk/reject [post]
+func (s *AchievementService) BulkRejectAchievements(c *fiber.Ctx) error {
+	var req BulkRejectRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if strings.TrimSpace(req.RejectionNote) == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "rejection_note is required")
+	}
+
+	ids, err := bulkIDs(req.IDs)
+	if err != nil {
+		return err
+	}
+
+	results := make([]BulkItemResult, 0, len(ids))
+	for _, id := range ids {
+		updatedRef, err := s.rejectOne(c, id, req.RejectionNote)
+		results = append(results, bulkItemResult(id, updatedRef, "achievement rejected", err))
+	}
+
+	return bulkResponse(c, results)
+}
+
+// bulkIDs: wajib ada, dibatasi, ID ganda diproses sekali
+func bulkIDs(ids []string) ([]string, error) {
+	seen := map[string]bool{}
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
 		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
 
-		students, err := s.studentRepo.GetStudentsByAdvisor(
-			c.Context(),
-			lecturer.ID,
+	if len(unique) == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "ids is required")
+	}
+	if len(unique) > maxBulkItems {
+		return nil, fiber.NewError(
+			fiber.StatusBadRequest,
+			fmt.Sprintf("at most %d ids per request", maxBulkItems),
 		)
-		if err != nil {
-			return fiber.NewError(500, "failed to fetch advisees")
+	}
+	return unique, nil
+}
+
+// bulkItemResult: terjemahkan error handler satuan ke hasil per item
+func bulkItemResult(
+	id string,
+	ref *model.AchievementReference,
+	message string,
+	err error,
+) BulkItemResult {
+	if err == nil {
+		return BulkItemResult{ID: id, Result: BulkResultOK, Message: message, Data: ref}
+	}
+
+	result := BulkItemResult{ID: id, Result: BulkResultError, Message: err.Error()}
+
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		switch fe.Code {
+		case fiber.StatusForbidden:
+			result.Result = BulkResultForbidden
+		case fiber.StatusConflict:
+			result.Result = BulkResultWrongStatus
+		case fiber.StatusNotFound:
+			result.Result = BulkResultNotFound
+		case fiber.StatusBadRequest:
+			result.Result = BulkResultInvalid
 		}
+	}
+	return result
+}
 
-		allowed := false
-		for _, st := range students {
-			if st.ID == ref.StudentID {
-				allowed = true
-				break
-			}
+func bulkResponse(c *fiber.Ctx, results []BulkItemResult) error {
+	summary := map[string]int{}
+	for _, r := range results {
+		summary[r.Result]++
+	}
+
+	return c.JSON(fiber.Map{
+		"summary": summary,
+		"data":    results,
+	})
+}
+
+// ReviseAchievement godoc
+// @Summary Revisi prestasi yang ditolak
+// @Description
+// Mengembalikan prestasi rejected ke draft agar bisa diedit dan disubmit ulang.
+// Catatan penolakan sebelumnya tetap tersimpan. Jumlah revisi dibatasi per prestasi.
+// @Tags Achievements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param body body service.ReviseAchievementRequest false "Revise Payload"
+// @Success 200 {object} map[string]interface{} "Achievement reopened for revision"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 409 {object} map[string]interface{} "Invalid status / resubmission limit reached"
+// @Router /achievements/{id}/revise [post]
+func (s *AchievementService) ReviseAchievement(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// body opsional
+	var req ReviseAchievementRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 		}
+	}
+
+	// 1️⃣ ambil reference
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+
+	// 2️⃣ workflow + authorization (default: rejected → draft, pemilik / admin)
+	t, err := s.checkTransition(c, model.ActionRevise, ref)
+	if err != nil {
+		return err
+	}
+
+	// 3️⃣ batas submit ulang
+	maxResubmissions := config.MaxResubmissions()
+	if ref.ResubmissionCount >= maxResubmissions {
+		return fiber.NewError(
+			fiber.StatusConflict,
+			fmt.Sprintf("resubmission limit reached (%d)", maxResubmissions),
+		)
+	}
+
+	var note *string
+	if req.Note != "" {
+		note = &req.Note
+	}
+
+	// 4️⃣ revise (PostgreSQL) — rejection_note tetap ada
+	updatedRef, err := s.referenceRepo.Revise(
+		c.Context(),
+		achievementID,
+		statusChange(c, t, note),
+		maxResubmissions,
+	)
+	if err != nil {
+		return transitionError(err, "failed to revise achievement")
+	}
+
+	return c.JSON(fiber.Map{
+		"message":            "achievement reopened for revision",
+		"data":               updatedRef,
+		"resubmissions_left": maxResubmissions - updatedRef.ResubmissionCount,
+	})
+}
+
+// WithdrawAchievement godoc
+// @Summary Tarik kembali prestasi yang sudah disubmit
+// @Description
+// Mengembalikan prestasi submitted ke draft selama belum ada reviewer yang memproses.
+// @Tags Achievements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} map[string]interface{} "Achievement withdrawn"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 409 {object} map[string]interface{} "Already reviewed / invalid status"
+// @Router /achievements/{id}/withdraw [post]
+func (s *AchievementService) WithdrawAchievement(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// 1️⃣ ambil reference
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+
+	// 2️⃣ workflow + authorization (default: submitted → draft, pemilik / admin)
+	t, err := s.checkTransition(c, model.ActionWithdraw, ref)
+	if err != nil {
+		return err
+	}
 
-		if !allowed {
-			return fiber.NewError(fiber.StatusForbidden, "access denied")
+	// 3️⃣ withdraw (atomic terhadap verify / reject)
+	updatedRef, err := s.referenceRepo.Withdraw(
+		c.Context(),
+		achievementID,
+		statusChange(c, t, nil),
+	)
+	if err != nil {
+		if errors.Is(err, repository.ErrStatusConflict) {
+			return fiber.NewError(
+				fiber.StatusConflict,
+				"achievement is already being reviewed and can no longer be withdrawn",
+			)
 		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to withdraw achievement")
 	}
 
-	// 6️⃣ reject (PostgreSQL)
-	updatedRef, err := s.referenceRepo.Reject(
+	return c.JSON(fiber.Map{
+		"message": "achievement withdrawn",
+		"data":    updatedRef,
+	})
+}
+
+// RevokeAchievement godoc
+// @Summary Cabut verifikasi prestasi
+// @Description
+// Admin mencabut prestasi yang sudah diverifikasi (mis. sertifikat terbukti palsu).
+// Alasan wajib diisi, tercatat di riwayat status, dan mahasiswa (termasuk anggota tim) mendapat notifikasi.
+// Prestasi revoked tidak lagi dihitung di laporan.
+// @Tags Achievements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param body body RevokeAchievementRequest true "Revoke reason"
+// @Success 200 {object} map[string]interface{} "Achievement revoked"
+// @Failure 400 {object} map[string]interface{} "Reason is required"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 409 {object} map[string]interface{} "Invalid status"
+// @Router /achievements/{id}/revoke [post]
+func (s *AchievementService) RevokeAchievement(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// 1️⃣ parse body (alasan wajib)
+	var req RevokeAchievementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "revoke reason is required")
+	}
+
+	// 2️⃣ ambil reference
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+
+	// 3️⃣ workflow + authorization (default: verified → revoked, admin saja)
+	t, err := s.checkTransition(c, model.ActionRevoke, ref)
+	if err != nil {
+		return err
+	}
+
+	// 4️⃣ update status + riwayat (alasan jadi note)
+	updatedRef, err := s.referenceRepo.UpdateStatus(
 		c.Context(),
 		achievementID,
-		req.RejectionNote,
+		statusChange(c, t, &reason),
 	)
 	if err != nil {
-		return fiber.NewError(
-			fiber.StatusInternalServerError,
-			"failed to reject achievement",
-		)
+		return transitionError(err, "failed to revoke achievement")
+	}
+
+	// 5️⃣ notifikasi ke pemilik + anggota tim
+	title := "Prestasi"
+	studentIDs := []string{ref.StudentID}
+	if objID, err := primitive.ObjectIDFromHex(achievementID); err == nil {
+		if ach, err := s.achievementRepo.GetByID(c.Context(), objID); err == nil {
+			title = ach.Title
+			studentIDs = achievementStudentIDs(ach)
+		}
+	}
+	for _, studentID := range studentIDs {
+		s.notifyStudent(c.Context(), studentID, model.Notification{
+			Type:          model.NotificationAchievementRevoked,
+			Title:         "Verifikasi prestasi dicabut",
+			Message:       fmt.Sprintf("Verifikasi untuk \"%s\" dicabut oleh admin: %s", title, reason),
+			AchievementID: achievementID,
+		})
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "achievement rejected",
+		"message": "achievement revoked",
 		"data":    updatedRef,
 	})
 }
 
+// GetAchievementComments godoc
+// @Summary Ambil thread komentar prestasi
+// @Description Komentar dari mahasiswa anggota, dosen wali, dan admin. Thread tetap utuh walau prestasi direvisi.
+// @Tags Achievements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} map[string]interface{} "List comments"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Router /achievements/{id}/comments [get]
+func (s *AchievementService) GetAchievementComments(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// 1️⃣ ambil achievement (sekalian validasi id)
+	achievement, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return err
+	}
+
+	// 2️⃣ hanya anggota, dosen wali, admin
+	if err := s.authorizeActors(c, "", achievementParticipants, achievementID, achievement.StudentID); err != nil {
+		return err
+	}
+
+	// 3️⃣ ambil thread
+	comments, err := s.commentRepo.FindByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch comments")
+	}
+
+	return c.JSON(fiber.Map{"data": comments})
+}
+
+// CreateAchievementComment godoc
+// @Summary Tambah komentar pada prestasi
+// @Description
+// Komentar bisa berupa balasan (parent_id) dan bisa ditandai sebagai change request
+// untuk satu field atau satu attachment (attachment_id). Change request hanya oleh dosen wali / admin.
+// @Tags Achievements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param body body CreateCommentRequest true "Comment"
+// @Success 201 {object} map[string]interface{} "Comment created"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Router /achievements/{id}/comments [post]
+func (s *AchievementService) CreateAchievementComment(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+	achievementID := c.Params("id")
+
+	// 1️⃣ parse body
+	var req CreateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "comment body is required")
+	}
+
+	// 2️⃣ ambil achievement + reference
+	achievement, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return err
+	}
+
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+
+	// 3️⃣ hanya anggota, dosen wali, admin
+	if err := s.authorizeActors(c, "", achievementParticipants, achievementID, achievement.StudentID); err != nil {
+		return err
+	}
+
+	comment := model.AchievementComment{
+		AchievementID: achievementID,
+		AuthorID:      claims.UserID,
+		AuthorRole:    claims.Role,
+		Body:          req.Body,
+		Round:         ref.ResubmissionCount,
+		CreatedAt:     time.Now(),
+	}
+
+	// 4️⃣ balasan harus ke komentar di prestasi yang sama
+	if req.ParentID != "" {
+		parentID, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid parent_id")
+		}
+
+		parent, err := s.commentRepo.GetByID(c.Context(), parentID)
+		if err != nil || parent.AchievementID != achievementID {
+			return fiber.NewError(fiber.StatusBadRequest, "parent comment not found")
+		}
+		comment.ParentID = &parentID
+	}
+
+	// 5️⃣ change request: hanya reviewer, targetnya harus jelas
+	if req.ChangeRequest != nil {
+		if claims.Role == "Mahasiswa" {
+			return fiber.NewError(fiber.StatusForbidden, "only reviewers can request changes")
+		}
+		if err := validateChangeRequest(req.ChangeRequest, achievement); err != nil {
+			return err
+		}
+		comment.ChangeRequest = req.ChangeRequest
+	}
+
+	// 6️⃣ simpan
+	if err := s.commentRepo.Create(c.Context(), &comment); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to create comment")
+	}
+
+	// 7️⃣ komentar reviewer → kabari pemilik + anggota tim
+	if claims.Role != "Mahasiswa" {
+		for _, studentID := range achievementStudentIDs(achievement) {
+			s.notifyStudent(c.Context(), studentID, model.Notification{
+				Type:          model.NotificationAchievementComment,
+				Title:         "Komentar baru pada prestasi",
+				Message:       fmt.Sprintf("Ada komentar baru pada \"%s\"", achievement.Title),
+				AchievementID: achievementID,
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "comment created",
+		"data":    comment,
+	})
+}
+
+// validateChangeRequest: tepat satu target (field / attachment) yang memang ada.
+// Attachment dirujuk lewat ID (nama file bisa sama / berubah saat diganti);
+// nama file saat diminta disimpan untuk ditampilkan.
+func validateChangeRequest(cr *model.ChangeRequest, achievement *model.Achievement) error {
+	cr.Field = strings.TrimSpace(cr.Field)
+	cr.AttachmentID = strings.TrimSpace(cr.AttachmentID)
+	cr.AttachmentName = ""
+
+	if (cr.Field == "") == (cr.AttachmentID == "") {
+		return fiber.NewError(fiber.StatusBadRequest, "change request must target either a field or an attachment")
+	}
+
+	if cr.Field != "" {
+		root, _, _ := strings.Cut(cr.Field, ".")
+		if !changeRequestFields[root] || (root != "details" && root != cr.Field) {
+			return fiber.NewError(fiber.StatusBadRequest, "unknown field: "+cr.Field)
+		}
+		return nil
+	}
+
+	att, ok := findAttachment(achievement, cr.AttachmentID)
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "attachment not found: "+cr.AttachmentID)
+	}
+	cr.AttachmentName = att.FileName
+	return nil
+}
+
 // GetAchievementHistory godoc
 // @Summary Ambil riwayat status prestasi
-// @Description Menampilkan timeline status: draft, submitted, verified, rejected
+// @Description Menampilkan timeline status dari riwayat: draft, submitted, verified, rejected, revisi, dst.
 // @Tags Achievements
 // @Security BearerAuth
 // @Accept json
@@ -876,7 +2092,6 @@ func (s *AchievementService) RejectAchievement(c *fiber.Ctx) error {
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
 // @Router /achievements/{id}/history [get]
 func (s *AchievementService) GetAchievementHistory(c *fiber.Ctx) error {
-	claims := c.Locals("user").(*model.JWTClaims)
 	achievementID := c.Params("id")
 
 	// 1️⃣ ambil reference (SUMBER STATUS)
@@ -899,70 +2114,144 @@ func (s *AchievementService) GetAchievementHistory(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "achievement not found")
 	}
 
-	// 3️⃣ AUTH (tetap seperti punyamu)
-	switch claims.Role {
-	case "Mahasiswa":
-		if claims.StudentID == "" || ref.StudentID != claims.StudentID {
-			return fiber.NewError(fiber.StatusForbidden, "access denied")
-		}
-	case "Admin":
-		// ok
-	case "Dosen Wali":
-		lecturer, err := s.lecturerRepo.GetLecturerProfile(c.Context(), claims.UserID)
-		if err != nil {
-			return fiber.NewError(fiber.StatusForbidden, "lecturer profile not found")
-		}
-		students, _ := s.studentRepo.GetStudentsByAdvisor(c.Context(), lecturer.ID)
+	// 3️⃣ AUTH: anggota tim, dosen wali anggota, admin
+	if err := s.authorizeActors(c, "", achievementParticipants, achievementID, ref.StudentID); err != nil {
+		return err
+	}
 
-		allowed := false
-		for _, st := range students {
-			if st.ID == ref.StudentID {
-				allowed = true
+	// 4️⃣ HISTORY dari tabel riwayat status
+	history, err := s.referenceRepo.GetHistory(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch achievement history")
+	}
+
+	approvals, err := s.referenceRepo.GetApprovals(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch achievement approvals")
+	}
+
+	// 5️⃣ RESPONSE
+	return c.JSON(model.AchievementHistoryResponse{
+		Achievement: *achievement,
+		History:     history,
+		Approvals:   approvals,
+	})
+}
+
+// GetAchievementVersions godoc
+// @Summary Riwayat versi dokumen prestasi
+// @Description
+// Snapshot dokumen setelah create, setiap update, dan setiap submit (versi lama tidak pernah diubah).
+// changes_since_rejection berisi perubahan sejak versi yang terakhir ditolak reviewer.
+// @Tags Achievements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} map[string]interface{} "List versions"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Router /achievements/{id}/versions [get]
+func (s *AchievementService) GetAchievementVersions(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// 1️⃣ reference (ronde sekarang) + otorisasi
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+	if err := s.authorizeActors(c, "", achievementParticipants, achievementID, ref.StudentID); err != nil {
+		return err
+	}
+
+	// 2️⃣ semua versi
+	versions, err := s.versionRepo.FindByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch achievement versions")
+	}
+
+	// 3️⃣ perubahan sejak submit terakhir yang ditolak
+	var sinceRejection *model.AchievementDiff
+	if len(versions) > 0 {
+		latest := &versions[len(versions)-1]
+		for i := len(versions) - 1; i >= 0; i-- {
+			v := &versions[i]
+			if v.Reason == model.VersionSubmit && v.Round < ref.ResubmissionCount {
+				if v.Version < latest.Version {
+					sinceRejection = &model.AchievementDiff{
+						From:    v.Version,
+						To:      latest.Version,
+						Changes: DiffAchievements(&v.Snapshot, &latest.Snapshot),
+					}
+				}
 				break
 			}
 		}
-		if !allowed {
-			return fiber.NewError(fiber.StatusForbidden, "access denied")
-		}
-	default:
-		return fiber.NewError(fiber.StatusForbidden, "access denied")
 	}
 
-	// 4️⃣ BENTUK HISTORY DARI reference
-	history := []model.AchievementStatusHistory{}
-
-	// draft (selalu ada)
-	history = append(history, model.AchievementStatusHistory{
-		Status:    "draft",
-		UpdatedAt: ref.CreatedAt,
+	return c.JSON(fiber.Map{
+		"data":                    versions,
+		"changes_since_rejection": sinceRejection,
 	})
+}
 
-	if ref.SubmittedAt != nil {
-		history = append(history, model.AchievementStatusHistory{
-			Status:    "submitted",
-			UpdatedAt: *ref.SubmittedAt,
-		})
+// GetAchievementVersionDiff godoc
+// @Summary Perbedaan dua versi prestasi
+// @Description Perubahan per field dari versi from ke versi to. Default: versi terakhir dibanding versi sebelumnya.
+// @Tags Achievements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param from query int false "Versi awal"
+// @Param to query int false "Versi akhir"
+// @Success 200 {object} model.AchievementDiff
+// @Failure 400 {object} map[string]interface{} "Invalid version"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Version not found"
+// @Router /achievements/{id}/versions/diff [get]
+func (s *AchievementService) GetAchievementVersionDiff(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// 1️⃣ otorisasi
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+	if err := s.authorizeActors(c, "", achievementParticipants, achievementID, ref.StudentID); err != nil {
+		return err
 	}
 
-	if ref.VerifiedAt != nil {
-		history = append(history, model.AchievementStatusHistory{
-			Status:    "verified",
-			UpdatedAt: *ref.VerifiedAt,
-			UpdatedBy: ref.VerifiedBy,
-		})
+	// 2️⃣ ambil versi
+	versions, err := s.versionRepo.FindByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch achievement versions")
+	}
+	if len(versions) == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "achievement has no versions")
 	}
 
-	if ref.Status == "rejected" && ref.RejectionNote != nil {
-		history = append(history, model.AchievementStatusHistory{
-			Status:    "rejected",
-			Note:      ref.RejectionNote,
-			UpdatedAt: ref.UpdatedAt,
-		})
+	// 3️⃣ default: versi terakhir vs sebelumnya
+	to := c.QueryInt("to", versions[len(versions)-1].Version)
+	from := c.QueryInt("from", to-1)
+	if from < 1 || to < 1 || from == to {
+		return fiber.NewError(fiber.StatusBadRequest, "from and to must be two different versions")
 	}
 
-	// 5️⃣ RESPONSE
-	return c.JSON(model.AchievementHistoryResponse{
-		Achievement: *achievement,
-		History:     history,
+	byVersion := map[int]*model.AchievementVersion{}
+	for i := range versions {
+		byVersion[versions[i].Version] = &versions[i]
+	}
+	fromV, ok := byVersion[from]
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("version %d not found", from))
+	}
+	toV, ok := byVersion[to]
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("version %d not found", to))
+	}
+
+	return c.JSON(model.AchievementDiff{
+		From:    from,
+		To:      to,
+		Changes: DiffAchievements(&fromV.Snapshot, &toV.Snapshot),
 	})
 }
@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+=======================
+
+	TEMPLATE REQUEST DTO
+
+=======================
+*/
+type AchievementTemplateRequest struct {
+	Name                string         `json:"name"`
+	AchievementType     string         `json:"achievementType"`
+	TitlePattern        string         `json:"title_pattern"`
+	Description         string         `json:"description"`
+	Details             map[string]any `json:"details"`
+	RequiredAttachments []string       `json:"required_attachments"`
+	Tags                []string       `json:"tags"`
+	IsActive            *bool          `json:"is_active"` // default true
+}
+
+type AchievementTemplateService struct {
+	templateRepo repository.AchievementTemplateRepository
+	schemas      *AchievementSchemas
+}
+
+func NewAchievementTemplateService(
+	templateRepo repository.AchievementTemplateRepository,
+	schemas *AchievementSchemas,
+) *AchievementTemplateService {
+	return &AchievementTemplateService{
+		templateRepo: templateRepo,
+		schemas:      schemas,
+	}
+}
+
+// canManageTemplates: admin & dosen wali boleh membuat template
+func canManageTemplates(claims *model.JWTClaims) bool {
+	return claims.Role == "Admin" || claims.Role == "Dosen Wali"
+}
+
+// GetAchievementTemplates godoc
+// @Summary Ambil daftar template prestasi
+// @Description Mahasiswa hanya melihat template aktif. Admin / Dosen Wali bisa menambah include_inactive=true.
+// @Tags Achievement Templates
+// @Security BearerAuth
+// @Produce json
+// @Param type query string false "Filter achievementType"
+// @Param include_inactive query bool false "Sertakan template nonaktif (admin / dosen wali)"
+// @Success 200 {object} map[string]interface{} "List achievement templates"
+// @Failure 500 {object} map[string]interface{} "Failed to fetch templates"
+// @Router /achievement-templates [get]
+func (s *AchievementTemplateService) GetAchievementTemplates(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	activeOnly := !(canManageTemplates(claims) && c.QueryBool("include_inactive"))
+
+	templates, err := s.templateRepo.FindAll(c.Context(), c.Query("type"), activeOnly)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch templates")
+	}
+
+	return c.JSON(fiber.Map{"data": templates})
+}
+
+// GetAchievementTemplateByID godoc
+// @Summary Ambil detail template prestasi
+// @Tags Achievement Templates
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} model.AchievementTemplate
+// @Failure 404 {object} map[string]interface{} "Template not found"
+// @Router /achievement-templates/{id} [get]
+func (s *AchievementTemplateService) GetAchievementTemplateByID(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	t, err := s.getTemplate(c)
+	if err != nil {
+		return err
+	}
+	if !t.IsActive && !canManageTemplates(claims) {
+		return fiber.NewError(fiber.StatusNotFound, "template not found")
+	}
+
+	return c.JSON(fiber.Map{"data": t})
+}
+
+// CreateAchievementTemplate godoc
+// @Summary Buat template prestasi
+// @Description Hanya Admin / Dosen Wali. Details divalidasi sebagian sesuai skema tipe; placeholder {key} di title_pattern harus field skema; required_attachments berisi jenis bukti (certificate, appointment_letter, publication, photo, other) yang wajib saat submit.
+// @Tags Achievement Templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body service.AchievementTemplateRequest true "Template payload"
+// @Success 201 {object} model.AchievementTemplate
+// @Failure 400 {object} map[string]interface{} "Validation failed"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Router /achievement-templates [post]
+func (s *AchievementTemplateService) CreateAchievementTemplate(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+	if !canManageTemplates(claims) {
+		return fiber.NewError(fiber.StatusForbidden, "access denied")
+	}
+
+	var req AchievementTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if errs := s.validateTemplate(&req); len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	now := time.Now()
+	t := &model.AchievementTemplate{CreatedBy: claims.UserID, CreatedAt: now, UpdatedAt: now}
+	applyTemplateRequest(t, &req)
+
+	if err := s.templateRepo.Create(c.Context(), t); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to create template")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "template created",
+		"data":    t,
+	})
+}
+
+// UpdateAchievementTemplate godoc
+// @Summary Ubah template prestasi
+// @Description Admin semua template, Dosen Wali hanya template buatannya. Prestasi yang sudah dibuat tidak ikut berubah, kecuali required_attachments yang dicek saat submit.
+// @Tags Achievement Templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param body body service.AchievementTemplateRequest true "Template payload"
+// @Success 200 {object} model.AchievementTemplate
+// @Failure 400 {object} map[string]interface{} "Validation failed"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Template not found"
+// @Router /achievement-templates/{id} [put]
+func (s *AchievementTemplateService) UpdateAchievementTemplate(c *fiber.Ctx) error {
+	t, err := s.getOwnedTemplate(c)
+	if err != nil {
+		return err
+	}
+
+	var req AchievementTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if errs := s.validateTemplate(&req); len(errs) > 0 {
+		return validationError(c, errs)
+	}
+
+	applyTemplateRequest(t, &req)
+
+	if err := s.templateRepo.Update(c.Context(), t); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to update template")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "template updated",
+		"data":    t,
+	})
+}
+
+// DeleteAchievementTemplate godoc
+// @Summary Hapus template prestasi
+// @Description Admin semua template, Dosen Wali hanya template buatannya.
+// @Tags Achievement Templates
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} map[string]interface{} "Template deleted"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Template not found"
+// @Router /achievement-templates/{id} [delete]
+func (s *AchievementTemplateService) DeleteAchievementTemplate(c *fiber.Ctx) error {
+	t, err := s.getOwnedTemplate(c)
+	if err != nil {
+		return err
+	}
+
+	if err := s.templateRepo.Delete(c.Context(), t.ID); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to delete template")
+	}
+
+	return c.JSON(fiber.Map{"message": "template deleted"})
+}
+
+func (s *AchievementTemplateService) getTemplate(c *fiber.Ctx) (*model.AchievementTemplate, error) {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid template id")
+	}
+
+	t, err := s.templateRepo.GetByID(c.Context(), id)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "template not found")
+	}
+	return t, nil
+}
+
+// getOwnedTemplate: template yang boleh diubah / dihapus user ini
+func (s *AchievementTemplateService) getOwnedTemplate(c *fiber.Ctx) (*model.AchievementTemplate, error) {
+	claims := c.Locals("user").(*model.JWTClaims)
+	if !canManageTemplates(claims) {
+		return nil, fiber.NewError(fiber.StatusForbidden, "access denied")
+	}
+
+	t, err := s.getTemplate(c)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Role != "Admin" && t.CreatedBy != claims.UserID {
+		return nil, fiber.NewError(fiber.StatusForbidden, "only the template creator can change it")
+	}
+	return t, nil
+}
+
+// validateTemplate: tipe dikenal, details valid sebagian, placeholder judul
+// & jenis bukti wajib dikenal
+func (s *AchievementTemplateService) validateTemplate(req *AchievementTemplateRequest) []model.FieldError {
+	var errs []model.FieldError
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		errs = append(errs, model.FieldError{Field: "name", Message: "is required"})
+	}
+	if strings.TrimSpace(req.TitlePattern) == "" {
+		errs = append(errs, model.FieldError{Field: "title_pattern", Message: "is required"})
+	}
+
+	sc, ok := s.schemas.Get(req.AchievementType)
+	if !ok {
+		return append(errs, model.FieldError{
+			Field:   "achievementType",
+			Message: "unknown achievement type: " + req.AchievementType,
+		})
+	}
+
+	if req.Details == nil {
+		req.Details = map[string]any{}
+	}
+	errs = append(errs, s.schemas.ValidatePartial(req.AchievementType, req.Details)...)
+
+	for i, kind := range req.RequiredAttachments {
+		if !slices.Contains(model.EvidenceKinds, kind) {
+			errs = append(errs, model.FieldError{
+				Field:   fmt.Sprintf("required_attachments[%d]", i),
+				Message: "must be one of: " + strings.Join(model.EvidenceKinds, ", "),
+			})
+		}
+	}
+
+	for _, key := range titlePlaceholders(req.TitlePattern) {
+		if !slices.ContainsFunc(sc.Fields, func(f model.DetailField) bool { return f.Name == key }) {
+			errs = append(errs, model.FieldError{
+				Field:   "title_pattern",
+				Message: "unknown placeholder {" + key + "}",
+			})
+		}
+	}
+
+	return errs
+}
+
+func applyTemplateRequest(t *model.AchievementTemplate, req *AchievementTemplateRequest) {
+	t.Name = req.Name
+	t.AchievementType = req.AchievementType
+	t.TitlePattern = strings.TrimSpace(req.TitlePattern)
+	t.Description = req.Description
+	t.Details = req.Details
+	t.RequiredAttachments = emptyIfNil(req.RequiredAttachments)
+	t.Tags = emptyIfNil(req.Tags)
+	t.IsActive = req.IsActive == nil || *req.IsActive
+}
+
+/* =======================
+   CREATE DARI TEMPLATE
+======================= */
+
+var titlePlaceholder = regexp.MustCompile(`\{(\w+)\}`)
+
+func titlePlaceholders(pattern string) []string {
+	var keys []string
+	for _, m := range titlePlaceholder.FindAllStringSubmatch(pattern, -1) {
+		keys = append(keys, m[1])
+	}
+	return keys
+}
+
+// applyTemplate: isi request create dari template. Details template
+// terkunci (nilai berbeda dari mahasiswa ditolak), description & tags
+// jadi nilai awal.
+func applyTemplate(t *model.AchievementTemplate, req *CreateAchievementRequest) []model.FieldError {
+	var errs []model.FieldError
+
+	if req.AchievementType != "" && req.AchievementType != t.AchievementType {
+		errs = append(errs, model.FieldError{
+			Field:   "achievementType",
+			Message: "must be " + t.AchievementType + " (set by template)",
+		})
+	}
+	req.AchievementType = t.AchievementType
+
+	if req.Details == nil {
+		req.Details = map[string]any{}
+	}
+	for k, v := range t.Details {
+		if own, ok := req.Details[k]; ok && fmt.Sprint(own) != fmt.Sprint(v) {
+			errs = append(errs, model.FieldError{Field: "details." + k, Message: "is set by template"})
+		}
+		req.Details[k] = v
+	}
+
+	if req.Description == "" {
+		req.Description = t.Description
+	}
+
+	tags := slices.Clone(t.Tags)
+	for _, tag := range req.Tags {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	req.Tags = tags
+
+	return errs
+}
+
+// templateEvidence: jenis bukti wajib dari template (prestasi yang dibuat dari
+// template) yang belum ada lampirannya. Template yang sudah dihapus tidak lagi
+// mewajibkan apa pun.
+func (s *AchievementService) templateEvidence(ctx context.Context, a *model.Achievement) ([]model.UnmetEvidence, error) {
+	unmet := []model.UnmetEvidence{}
+	if a.TemplateID == "" {
+		return unmet, nil
+	}
+	id, err := primitive.ObjectIDFromHex(a.TemplateID)
+	if err != nil {
+		return unmet, nil
+	}
+
+	t, err := s.templateRepo.GetByID(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return unmet, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, kind := range t.RequiredAttachments {
+		opt := model.EvidenceOption{Kind: kind}
+		if slices.Contains(t.RequiredAttachments[:i], kind) || evidenceSatisfied(opt, a) {
+			continue
+		}
+		unmet = append(unmet, model.UnmetEvidence{
+			Requirement: "template." + kind,
+			Label:       "Lampiran wajib template " + t.Name,
+			AnyOf:       []model.EvidenceOption{opt},
+		})
+	}
+	return unmet, nil
+}
+
+// renderTitle: ganti {key} dengan nilai details (setelah validasi skema)
+func renderTitle(pattern string, details map[string]any) (string, []model.FieldError) {
+	var errs []model.FieldError
+
+	title := titlePlaceholder.ReplaceAllStringFunc(pattern, func(m string) string {
+		key := m[1 : len(m)-1]
+		v, ok := details[key]
+		if !ok || v == nil || v == "" {
+			errs = append(errs, model.FieldError{Field: "details." + key, Message: "is required by the template title"})
+			return m
+		}
+		return fmt.Sprint(v)
+	})
+
+	return strings.TrimSpace(title), errs
+}
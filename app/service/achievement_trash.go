@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/app/storage"
+	"uas-backend/config"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetTrash godoc
+// @Summary Tempat sampah prestasi
+// @Description
+// Prestasi yang dihapus (soft delete) beserta jadwal hapus permanennya.
+// Mahasiswa: miliknya sendiri. Admin: semua, bisa difilter student_id.
+// @Tags Achievements
+// @Security BearerAuth
+// @Produce json
+// @Param student_id query string false "Filter mahasiswa (admin)"
+// @Success 200 {array} model.TrashItem
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 500 {object} map[string]interface{} "Failed to fetch trash"
+// @Router /achievements/trash [get]
+func (s *AchievementService) GetTrash(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	var studentIDs []string
+	switch claims.Role {
+	case "Mahasiswa":
+		if claims.StudentID == "" {
+			return fiber.NewError(fiber.StatusForbidden, "student profile not found")
+		}
+		studentIDs = []string{claims.StudentID}
+	case "Admin":
+		if id := c.Query("student_id"); id != "" {
+			studentIDs = []string{id}
+		}
+	default:
+		return fiber.NewError(fiber.StatusForbidden, "access denied")
+	}
+
+	achievements, err := s.achievementRepo.FindDeleted(c.Context(), studentIDs)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch trash")
+	}
+
+	retention := config.TrashRetention()
+	result := []model.TrashItem{}
+	for _, a := range achievements {
+		deletedAt := a.UpdatedAt
+		if a.DeletedAt != nil {
+			deletedAt = *a.DeletedAt
+		}
+		result = append(result, model.TrashItem{
+			Achievement: a,
+			DeletedAt:   deletedAt,
+			PurgeAt:     deletedAt.Add(retention),
+		})
+	}
+
+	return c.JSON(fiber.Map{"data": result})
+}
+
+// RestoreAchievement godoc
+// @Summary Pulihkan prestasi dari tempat sampah
+// @Description Status kembali ke draft. Hanya pemilik / admin, sebelum dihapus permanen.
+// @Tags Achievements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} map[string]interface{} "Achievement restored"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 409 {object} map[string]interface{} "Achievement is not deleted"
+// @Router /achievements/{id}/restore [post]
+func (s *AchievementService) RestoreAchievement(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	objID, err := primitive.ObjectIDFromHex(achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid achievement id")
+	}
+
+	// 1️⃣ reference (PostgreSQL)
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+
+	// 2️⃣ workflow + authorization (deleted → draft)
+	t, err := s.checkTransition(c, model.ActionRestore, ref)
+	if err != nil {
+		return err
+	}
+
+	// 3️⃣ update PostgreSQL reference (atomic terhadap status)
+	updatedRef, err := s.referenceRepo.UpdateStatus(
+		c.Context(),
+		achievementID,
+		statusChange(c, t, nil),
+	)
+	if err != nil {
+		return transitionError(err, "failed to restore achievement reference")
+	}
+
+	// 4️⃣ keluarkan dari tempat sampah (MongoDB); gagal → kembali ke deleted
+	if err := s.achievementRepo.Restore(c.Context(), objID); err != nil {
+		s.revertStatus(c, achievementID, t, ref.Status)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to restore achievement")
+	}
+
+	// 5️⃣ draft perpanjangan: klaim sertifikasi lama dipasang lagi
+	s.reclaimRenewal(c.Context(), objID)
+
+	return c.JSON(fiber.Map{
+		"message": "achievement restored",
+		"data":    updatedRef,
+	})
+}
+
+// PurgeTrashNow godoc
+// @Summary Jalankan purge tempat sampah sekarang
+// @Description Hapus permanen prestasi yang sudah melewati masa simpan (beserta file lampiran). Job yang sama berjalan berkala.
+// @Tags Achievements
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} model.TrashPurgeReport
+// @Failure 500 {object} map[string]interface{} "Failed to purge trash"
+// @Router /achievements/trash/purge [post]
+func (s *AchievementService) PurgeTrashNow(c *fiber.Ctx) error {
+	report, err := s.PurgeTrash(c.Context(), time.Now().Add(-config.TrashRetention()))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to purge trash")
+	}
+	return c.JSON(report)
+}
+
+/* =======================
+   PURGE JOB
+======================= */
+
+// PurgeTrash: hapus permanen prestasi yang dihapus sebelum cutoff.
+// Urutan: PostgreSQL dulu (sekaligus memastikan belum dipulihkan),
+// lalu file lampiran (storage), komentar, versi, dan dokumen MongoDB.
+func (s *AchievementService) PurgeTrash(ctx context.Context, cutoff time.Time) (model.TrashPurgeReport, error) {
+	var report model.TrashPurgeReport
+
+	candidates, err := s.achievementRepo.FindDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return report, err
+	}
+
+	for i := range candidates {
+		a := &candidates[i]
+		id := a.ID.Hex()
+		report.Checked++
+
+		// 1️⃣ reference + riwayat; false = sudah dipulihkan → file & dokumen tidak disentuh
+		purged, err := s.referenceRepo.Purge(ctx, id)
+		if err != nil {
+			return report, fmt.Errorf("purge reference %s: %w", id, err)
+		}
+		if !purged {
+			continue
+		}
+
+		// 2️⃣ file lampiran yang tidak dipakai prestasi lain
+		for _, att := range a.Attachments {
+			removed, err := s.removeAttachmentObject(ctx, a.ID, att)
+			if err != nil {
+				log.Printf("⚠️ Trash purge: %s: %v", att.StorageKey(), err)
+				continue
+			}
+			if removed {
+				report.FilesRemoved++
+			}
+		}
+
+		// 3️⃣ data MongoDB
+		if err := s.commentRepo.DeleteByAchievementID(ctx, id); err != nil {
+			return report, fmt.Errorf("purge comments %s: %w", id, err)
+		}
+		if err := s.versionRepo.DeleteByAchievementID(ctx, id); err != nil {
+			return report, fmt.Errorf("purge versions %s: %w", id, err)
+		}
+		if err := s.achievementRepo.HardDelete(ctx, a.ID); err != nil {
+			return report, fmt.Errorf("purge achievement %s: %w", id, err)
+		}
+		// dokumen yang masuk tempat sampah tanpa DeleteAchievement (mis. rekonsiliasi)
+		// mungkin masih memegang klaim perpanjangan
+		if err := s.achievementRepo.ReleaseRenewalBy(ctx, id); err != nil {
+			return report, fmt.Errorf("release renewal %s: %w", id, err)
+		}
+
+		report.Purged++
+	}
+
+	return report, nil
+}
+
+// removeAttachmentObject: hapus file dari storage kalau tidak dirujuk prestasi lain
+func (s *AchievementService) removeAttachmentObject(
+	ctx context.Context,
+	owner primitive.ObjectID,
+	att model.Attachment,
+) (bool, error) {
+	// key tidak valid (mis. path lama di luar uploads) tidak disentuh
+	key := att.StorageKey()
+	if storage.ValidKey(key) != nil {
+		return false, nil
+	}
+
+	inUse, err := s.achievementRepo.AttachmentInUse(ctx, key, owner)
+	if err != nil || inUse {
+		return false, err
+	}
+
+	if err := s.store.Delete(ctx, key); err != nil {
+		return false, err
+	}
+
+	// pratinjau ikut file asli (key turunan dari key yang sama)
+	if p := att.Preview; p != nil {
+		for _, k := range []string{p.ThumbnailKey, p.PreviewKey} {
+			if k == "" {
+				continue
+			}
+			if err := s.store.Delete(ctx, k); err != nil {
+				log.Printf("⚠️ Preview cleanup %s: %v", k, err)
+			}
+		}
+	}
+	return true, nil
+}
+
+// StartTrashPurgeJob: jalankan PurgeTrash berkala sampai ctx selesai
+func (s *AchievementService) StartTrashPurgeJob(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := s.PurgeTrash(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.Printf("⚠️ Trash purge failed: %v", err)
+		} else if report.Purged > 0 {
+			log.Printf("🗑️ Trash purge: %d achievements, %d files removed", report.Purged, report.FilesRemoved)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type AchievementTypeService struct {
+	schemas *AchievementSchemas
+}
+
+func NewAchievementTypeService(schemas *AchievementSchemas) *AchievementTypeService {
+	return &AchievementTypeService{schemas: schemas}
+}
+
+// GetAchievementTypes godoc
+// @Summary Ambil daftar tipe prestasi beserta skema details
+// @Description Dipakai frontend untuk membangun form details per tipe (field wajib, enum, format).
+// @Tags Achievement Types
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{} "List achievement type schemas"
+// @Router /achievement-types [get]
+func (s *AchievementTypeService) GetAchievementTypes(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"data": s.schemas.List()})
+}
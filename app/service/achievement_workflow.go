@@ -0,0 +1,226 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"uas-backend/app/model"
+)
+
+var (
+	ErrUnknownAction     = errors.New("unknown workflow action")
+	ErrIllegalTransition = errors.New("illegal status transition")
+)
+
+// AchievementWorkflow: satu-satunya sumber aturan status prestasi.
+// Semua handler wajib lewat sini sebelum mengubah status.
+type AchievementWorkflow struct {
+	def         model.WorkflowDefinition
+	states      map[string]bool
+	transitions map[string]model.WorkflowTransition
+}
+
+// DefaultWorkflowDefinition dipakai kalau tidak ada file konfigurasi
+func DefaultWorkflowDefinition() model.WorkflowDefinition {
+	owner := model.WorkflowActor{Role: "Mahasiswa", Relation: model.RelationOwner}
+	advisor := model.WorkflowActor{Role: "Dosen Wali", Relation: model.RelationAdvisor}
+	admin := model.WorkflowActor{Role: "Admin"}
+
+	return model.WorkflowDefinition{
+		States: []string{
+			model.StatusDraft,
+			model.StatusSubmitted,
+			model.StatusVerified,
+			model.StatusRejected,
+			model.StatusDeleted,
+			model.StatusRevoked,
+		},
+		Transitions: []model.WorkflowTransition{
+			{
+				Action:     model.ActionUpdate,
+				From:       []string{model.StatusDraft},
+				To:         model.StatusDraft,
+				Permission: "achievement:update",
+				Actors:     []model.WorkflowActor{owner, admin},
+			},
+			{
+				Action:     model.ActionUploadAttachment,
+				From:       []string{model.StatusDraft},
+				To:         model.StatusDraft,
+				Permission: "achievement:update",
+				Actors:     []model.WorkflowActor{owner, admin},
+			},
+			{
+				Action: model.ActionDelete,
+				From:   []string{model.StatusDraft},
+				To:     model.StatusDeleted,
+				Actors: []model.WorkflowActor{owner, admin},
+			},
+			{
+				Action: model.ActionSubmit,
+				From:   []string{model.StatusDraft},
+				To:     model.StatusSubmitted,
+				Actors: []model.WorkflowActor{owner, admin},
+			},
+			{
+				Action: model.ActionVerify,
+				From:   []string{model.StatusSubmitted},
+				To:     model.StatusVerified,
+				Actors: []model.WorkflowActor{advisor, admin},
+			},
+			{
+				Action: model.ActionReject,
+				From:   []string{model.StatusSubmitted},
+				To:     model.StatusRejected,
+				Actors: []model.WorkflowActor{advisor, admin},
+			},
+			{
+				Action: model.ActionRevise,
+				From:   []string{model.StatusRejected},
+				To:     model.StatusDraft,
+				Actors: []model.WorkflowActor{owner, admin},
+			},
+			{
+				Action: model.ActionWithdraw,
+				From:   []string{model.StatusSubmitted},
+				To:     model.StatusDraft,
+				Actors: []model.WorkflowActor{owner, admin},
+			},
+			{
+				Action: model.ActionRevoke,
+				From:   []string{model.StatusVerified},
+				To:     model.StatusRevoked,
+				Actors: []model.WorkflowActor{admin},
+			},
+			{
+				Action: model.ActionRestore,
+				From:   []string{model.StatusDeleted},
+				To:     model.StatusDraft,
+				Actors: []model.WorkflowActor{owner, admin},
+			},
+		},
+	}
+}
+
+// RequiredWorkflowActions: semua action yang dipanggil handler AchievementService
+var RequiredWorkflowActions = []string{
+	model.ActionUpdate,
+	model.ActionUploadAttachment,
+	model.ActionDelete,
+	model.ActionSubmit,
+	model.ActionVerify,
+	model.ActionReject,
+	model.ActionRevise,
+	model.ActionWithdraw,
+	model.ActionRevoke,
+	model.ActionRestore,
+}
+
+func NewAchievementWorkflow(def model.WorkflowDefinition) (*AchievementWorkflow, error) {
+	w := &AchievementWorkflow{
+		def:         def,
+		states:      map[string]bool{},
+		transitions: map[string]model.WorkflowTransition{},
+	}
+
+	for _, st := range def.States {
+		w.states[st] = true
+	}
+
+	for _, t := range def.Transitions {
+		if t.Action == "" {
+			return nil, errors.New("workflow: transition without action")
+		}
+		if _, dup := w.transitions[t.Action]; dup {
+			return nil, fmt.Errorf("workflow: duplicate action %q", t.Action)
+		}
+		if !w.states[t.To] {
+			return nil, fmt.Errorf("workflow: action %q targets unknown state %q", t.Action, t.To)
+		}
+		if len(t.From) == 0 {
+			return nil, fmt.Errorf("workflow: action %q has no source state", t.Action)
+		}
+		for _, from := range t.From {
+			if !w.states[from] {
+				return nil, fmt.Errorf("workflow: action %q starts from unknown state %q", t.Action, from)
+			}
+		}
+		if len(t.Actors) == 0 {
+			return nil, fmt.Errorf("workflow: action %q has no actors", t.Action)
+		}
+		for _, a := range t.Actors {
+			switch a.Relation {
+			case "", model.RelationOwner, model.RelationMember, model.RelationAdvisor:
+			default:
+				return nil, fmt.Errorf("workflow: action %q has unknown relation %q", t.Action, a.Relation)
+			}
+		}
+		w.transitions[t.Action] = t
+	}
+
+	// action bawaan wajib ada karena dipanggil langsung oleh handler;
+	// yang hilang ditolak saat start, bukan 500 saat endpoint dipakai
+	for _, action := range RequiredWorkflowActions {
+		if _, ok := w.transitions[action]; !ok {
+			return nil, fmt.Errorf("workflow: missing required action %q", action)
+		}
+	}
+
+	return w, nil
+}
+
+// LoadAchievementWorkflow membaca definisi dari file JSON.
+// path kosong → pakai DefaultWorkflowDefinition.
+func LoadAchievementWorkflow(path string) (*AchievementWorkflow, error) {
+	if path == "" {
+		return NewAchievementWorkflow(DefaultWorkflowDefinition())
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("workflow: read %s: %w", path, err)
+	}
+
+	var def model.WorkflowDefinition
+	if err := json.Unmarshal(raw, &def); err != nil {
+		return nil, fmt.Errorf("workflow: parse %s: %w", path, err)
+	}
+
+	return NewAchievementWorkflow(def)
+}
+
+// Lookup: ambil definisi transisi untuk action (tanpa cek status)
+func (w *AchievementWorkflow) Lookup(action string) (*model.WorkflowTransition, error) {
+	t, ok := w.transitions[action]
+	if !ok {
+		return nil, ErrUnknownAction
+	}
+	return &t, nil
+}
+
+// Transition: cek apakah action boleh dijalankan dari status sekarang
+func (w *AchievementWorkflow) Transition(action, from string) (*model.WorkflowTransition, error) {
+	t, err := w.Lookup(action)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, st := range t.From {
+		if st == from {
+			return t, nil
+		}
+	}
+
+	return nil, ErrIllegalTransition
+}
+
+func (w *AchievementWorkflow) Allows(action, from string) bool {
+	_, err := w.Transition(action, from)
+	return err == nil
+}
+
+func (w *AchievementWorkflow) Definition() model.WorkflowDefinition {
+	return w.def
+}
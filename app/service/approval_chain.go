@@ -0,0 +1,173 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"uas-backend/app/model"
+)
+
+// ApprovalChains: aturan verifikasi bertingkat per tipe prestasi
+type ApprovalChains struct {
+	chains []model.ApprovalChain
+}
+
+// DefaultApprovalChains: publikasi & kompetisi internasional butuh
+// persetujuan dosen wali lalu fakultas, sisanya cukup dosen wali.
+func DefaultApprovalChains() []model.ApprovalChain {
+	advisor := model.ApprovalLevel{
+		Name: "advisor",
+		Actors: []model.WorkflowActor{
+			{Role: "Dosen Wali", Relation: model.RelationAdvisor},
+			{Role: "Admin"},
+		},
+	}
+	faculty := model.ApprovalLevel{
+		Name: "faculty",
+		Actors: []model.WorkflowActor{
+			{Role: "Admin"},
+		},
+	}
+
+	return []model.ApprovalChain{
+		{
+			AchievementType: "publication",
+			Levels:          []model.ApprovalLevel{advisor, faculty},
+		},
+		{
+			AchievementType: "competition",
+			When:            map[string]string{"competitionLevel": "international"},
+			Levels:          []model.ApprovalLevel{advisor, faculty},
+		},
+		{
+			Levels: []model.ApprovalLevel{advisor},
+		},
+	}
+}
+
+func NewApprovalChains(chains []model.ApprovalChain) (*ApprovalChains, error) {
+	hasFallback := false
+
+	for i, chain := range chains {
+		if len(chain.Levels) == 0 {
+			return nil, fmt.Errorf("approval chain #%d has no levels", i)
+		}
+
+		names := map[string]bool{}
+		for _, lvl := range chain.Levels {
+			if lvl.Name == "" {
+				return nil, fmt.Errorf("approval chain #%d has a level without name", i)
+			}
+			if names[lvl.Name] {
+				return nil, fmt.Errorf("approval chain #%d has duplicate level %q", i, lvl.Name)
+			}
+			names[lvl.Name] = true
+
+			if len(lvl.Actors) == 0 {
+				return nil, fmt.Errorf("approval level %q has no actors", lvl.Name)
+			}
+			for _, a := range lvl.Actors {
+				switch a.Relation {
+				case "", model.RelationOwner, model.RelationMember, model.RelationAdvisor:
+				default:
+					return nil, fmt.Errorf("approval level %q has unknown relation %q", lvl.Name, a.Relation)
+				}
+			}
+		}
+
+		if chain.AchievementType == "" && len(chain.When) == 0 {
+			hasFallback = true
+		}
+	}
+
+	if !hasFallback {
+		return nil, errors.New("approval chains need a fallback chain without achievementType / when")
+	}
+
+	return &ApprovalChains{chains: chains}, nil
+}
+
+// LoadApprovalChains membaca daftar chain dari file JSON.
+// path kosong → pakai DefaultApprovalChains.
+func LoadApprovalChains(path string) (*ApprovalChains, error) {
+	if path == "" {
+		return NewApprovalChains(DefaultApprovalChains())
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("approval chains: read %s: %w", path, err)
+	}
+
+	var chains []model.ApprovalChain
+	if err := json.Unmarshal(raw, &chains); err != nil {
+		return nil, fmt.Errorf("approval chains: parse %s: %w", path, err)
+	}
+
+	return NewApprovalChains(chains)
+}
+
+// Resolve: chain pertama yang cocok dengan tipe + details prestasi
+func (a *ApprovalChains) Resolve(achievement *model.Achievement) model.ApprovalChain {
+	for _, chain := range a.chains {
+		if chain.AchievementType != "" && chain.AchievementType != achievement.AchievementType {
+			continue
+		}
+
+		matched := true
+		for key, want := range chain.When {
+			got, ok := achievement.Details[key]
+			if !ok || !strings.EqualFold(fmt.Sprint(got), want) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return chain
+		}
+	}
+
+	// tidak terjadi karena NewApprovalChains mewajibkan fallback
+	return a.chains[len(a.chains)-1]
+}
+
+// LevelNames: semua nama tahap unik di semua chain
+func (a *ApprovalChains) LevelNames() []string {
+	var names []string
+	seen := map[string]bool{}
+
+	for _, chain := range a.chains {
+		for _, lvl := range chain.Levels {
+			if !seen[lvl.Name] {
+				seen[lvl.Name] = true
+				names = append(names, lvl.Name)
+			}
+		}
+	}
+	return names
+}
+
+// LevelActors: gabungan actor untuk nama level tertentu di semua chain
+// (dipakai untuk antrian reviewer per level)
+func (a *ApprovalChains) LevelActors(name string) []model.WorkflowActor {
+	var actors []model.WorkflowActor
+	seen := map[model.WorkflowActor]bool{}
+
+	for _, chain := range a.chains {
+		for _, lvl := range chain.Levels {
+			if lvl.Name != name {
+				continue
+			}
+			for _, actor := range lvl.Actors {
+				if !seen[actor] {
+					seen[actor] = true
+					actors = append(actors, actor)
+				}
+			}
+		}
+	}
+	return actors
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/app/storage"
+	"uas-backend/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// path link download bertanda tangan (route publik, lihat route.FileRoutes)
+const signedAttachmentPath = "/api/v1/files/achievements/%s/attachments/%s"
+
+// DownloadAttachment godoc
+// @Summary Download lampiran prestasi
+// @Description
+// Hak akses sama dengan detail prestasi (anggota tim, dosen wali, admin).
+// Mendukung header Range (satu rentang). ?download=true → Content-Disposition attachment.
+// Pratinjau PDF = gambar terbesar yang tertanam di halaman pertama (bukan render halaman);
+// PDF teks / vektor tanpa gambar berstatus unavailable dan tidak punya varian.
+// @Tags Achievements
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param id path string true "Achievement ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Param download query bool false "Paksa simpan sebagai file"
+// @Param variant query string false "thumbnail / preview (JPEG, kalau pratinjau sudah siap)"
+// @Success 200 {file} file
+// @Success 206 {file} file "Partial content"
+// @Failure 400 {object} map[string]interface{} "Invalid variant"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Attachment / preview not found"
+// @Failure 416 {object} map[string]interface{} "Range not satisfiable"
+// @Router /achievements/{id}/attachments/{attachmentId} [get]
+func (s *AchievementService) DownloadAttachment(c *fiber.Ctx) error {
+	att, err := s.authorizedAttachment(c)
+	if err != nil {
+		return err
+	}
+	if att, err = attachmentVariant(att, c.Query("variant")); err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	return s.serveAttachment(c, att)
+}
+
+// GetAttachmentLink godoc
+// @Summary Link download lampiran sementara
+// @Description
+// URL bertanda tangan (HMAC) yang bisa dipakai di <img>/<a> tanpa header Authorization.
+// Berlaku singkat (ATTACHMENT_URL_TTL_MINUTES, default 15 menit).
+// @Tags Achievements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Param variant query string false "thumbnail / preview"
+// @Success 200 {object} map[string]interface{} "url & expires_at"
+// @Failure 400 {object} map[string]interface{} "Invalid variant"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Attachment / preview not found"
+// @Router /achievements/{id}/attachments/{attachmentId}/link [get]
+func (s *AchievementService) GetAttachmentLink(c *fiber.Ctx) error {
+	att, err := s.authorizedAttachment(c)
+	if err != nil {
+		return err
+	}
+	variant := c.Query("variant")
+	if _, err := attachmentVariant(att, variant); err != nil {
+		return err
+	}
+
+	expires := time.Now().Add(config.AttachmentURLTTL()).Truncate(time.Second)
+	return c.JSON(fiber.Map{
+		"url":        signedAttachmentURL(c.Params("id"), c.Params("attachmentId"), variant, expires),
+		"expires_at": expires,
+	})
+}
+
+// DownloadSignedAttachment godoc
+// @Summary Download lampiran lewat link bertanda tangan
+// @Description Tanpa JWT; link dari GET /achievements/{id}/attachments/{attachmentId}/link.
+// @Tags Files
+// @Produce octet-stream
+// @Param id path string true "Achievement ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Param expires query int true "Unix time kedaluwarsa"
+// @Param signature query string true "HMAC-SHA256"
+// @Param variant query string false "thumbnail / preview"
+// @Success 200 {file} file
+// @Success 206 {file} file "Partial content"
+// @Failure 403 {object} map[string]interface{} "Invalid or expired link"
+// @Failure 404 {object} map[string]interface{} "Attachment not found"
+// @Router /files/achievements/{id}/attachments/{attachmentId} [get]
+func (s *AchievementService) DownloadSignedAttachment(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	attachmentID := c.Params("attachmentId")
+	variant := c.Query("variant")
+
+	// 1️⃣ tanda tangan + masa berlaku
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil || !hmac.Equal(
+		[]byte(attachmentSignature(achievementID, attachmentID, variant, expires)),
+		[]byte(c.Query("signature")),
+	) {
+		return fiber.NewError(fiber.StatusForbidden, "invalid download link")
+	}
+	if time.Now().Unix() > expires {
+		return fiber.NewError(fiber.StatusForbidden, "download link expired")
+	}
+
+	// 2️⃣ prestasi & lampiran masih ada
+	achievement, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return err
+	}
+	att, ok := findAttachment(achievement, attachmentID)
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, "attachment not found")
+	}
+	if att, err = attachmentVariant(att, variant); err != nil {
+		return err
+	}
+
+	// link sudah membuktikan akses; boleh di-cache browser sampai kedaluwarsa
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("private, max-age=%d", max(expires-time.Now().Unix(), 0)))
+	return s.serveAttachment(c, att)
+}
+
+// authorizedAttachment: aturan akses sama dengan GetAchievementByID
+func (s *AchievementService) authorizedAttachment(c *fiber.Ctx) (model.Attachment, error) {
+	achievementID := c.Params("id")
+
+	achievement, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	// anggota tim, dosen wali salah satu anggota, admin
+	if err := s.authorizeActors(c, "", achievementParticipants, achievementID, achievement.StudentID); err != nil {
+		return model.Attachment{}, err
+	}
+
+	att, ok := findAttachment(achievement, c.Params("attachmentId"))
+	if !ok {
+		return model.Attachment{}, fiber.NewError(fiber.StatusNotFound, "attachment not found")
+	}
+	return att, nil
+}
+
+// attachmentVariant: lampiran asli, atau JPEG thumbnail / pratinjau yang sudah siap
+func attachmentVariant(att model.Attachment, variant string) (model.Attachment, error) {
+	if variant == "" {
+		return att, nil
+	}
+	if variant != model.VariantThumbnail && variant != model.VariantPreview {
+		return model.Attachment{}, fiber.NewError(fiber.StatusBadRequest,
+			"variant must be one of: "+model.VariantThumbnail+", "+model.VariantPreview)
+	}
+
+	p := att.Preview
+	if p == nil || p.Status != model.PreviewReady {
+		return model.Attachment{}, fiber.NewError(fiber.StatusNotFound, "preview not available")
+	}
+	key := p.ThumbnailKey
+	if variant == model.VariantPreview {
+		key = p.PreviewKey
+	}
+
+	return model.Attachment{
+		ID:       att.ID,
+		FileName: strings.TrimSuffix(att.FileName, path.Ext(att.FileName)) + "-" + variant + ".jpg",
+		Key:      key,
+		FileType: "image/jpeg",
+		Hash:     att.Hash + "-" + variant, // ETag
+	}, nil
+}
+
+func findAttachment(a *model.Achievement, attachmentID string) (model.Attachment, bool) {
+	for _, att := range a.Attachments {
+		if att.Matches(attachmentID) {
+			return att, true
+		}
+	}
+	return model.Attachment{}, false
+}
+
+// serveAttachment: stream isi lampiran dari storage (utuh atau satu rentang)
+func (s *AchievementService) serveAttachment(c *fiber.Ctx, att model.Attachment) error {
+	key := att.StorageKey()
+	if storage.ValidKey(key) != nil {
+		return fiber.NewError(fiber.StatusNotFound, "attachment file not found")
+	}
+
+	// 1️⃣ header: tipe dari upload (hasil sniffing), jangan ditebak ulang browser
+	contentType := att.FileType
+	if contentType == "" {
+		contentType = fiber.MIMEOctetStream
+	}
+	disposition := "inline"
+	if c.QueryBool("download") {
+		disposition = "attachment"
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{"filename": att.FileName}))
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+
+	// key berbasis hash → isi tidak pernah berubah
+	if att.Hash != "" {
+		etag := `"` + att.Hash + `"`
+		c.Set(fiber.HeaderETag, etag)
+		if c.Get(fiber.HeaderIfNoneMatch) == etag {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+	}
+
+	// 2️⃣ Range (hanya kalau ukuran diketahui; multi-range → file utuh)
+	if att.Size > 0 {
+		c.Set(fiber.HeaderAcceptRanges, "bytes")
+
+		if c.Get(fiber.HeaderRange) != "" {
+			r, err := c.Range(int(att.Size))
+			switch {
+			case errors.Is(err, fiber.ErrRangeUnsatisfiable):
+				c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", att.Size))
+				return fiber.NewError(fiber.StatusRequestedRangeNotSatisfiable, "range not satisfiable")
+
+			case err == nil && r.Type == "bytes" && len(r.Ranges) == 1:
+				start, end := int64(r.Ranges[0].Start), int64(r.Ranges[0].End)
+				body, err := s.store.GetRange(c.Context(), key, start, end-start+1)
+				if err != nil {
+					return storageReadError(err)
+				}
+				c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, end, att.Size))
+				c.Status(fiber.StatusPartialContent)
+				return c.SendStream(body, int(end-start+1))
+			}
+		}
+	}
+
+	// 3️⃣ file utuh
+	body, err := s.store.Get(c.Context(), key)
+	if err != nil {
+		return storageReadError(err)
+	}
+	size := -1
+	if att.Size > 0 {
+		size = int(att.Size)
+	}
+	return c.SendStream(body, size)
+}
+
+func storageReadError(err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "attachment file not found")
+	}
+	fmt.Println("⚠️ STORAGE READ FAILED:", err)
+	return fiber.NewError(fiber.StatusInternalServerError, "failed to read attachment")
+}
+
+/* =======================
+   LINK BERTANDA TANGAN
+======================= */
+
+// attachmentSignature: HMAC-SHA256(achievementID/attachmentID/variant/expires)
+func attachmentSignature(achievementID, attachmentID, variant string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(config.AttachmentURLSecret()))
+	fmt.Fprintf(mac, "%s/%s/%s/%d", achievementID, attachmentID, variant, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func signedAttachmentURL(achievementID, attachmentID, variant string, expires time.Time) string {
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	q.Set("signature", attachmentSignature(achievementID, attachmentID, variant, expires.Unix()))
+	if variant != "" {
+		q.Set("variant", variant)
+	}
+
+	return fmt.Sprintf(signedAttachmentPath, url.PathEscape(achievementID), url.PathEscape(attachmentID)) + "?" + q.Encode()
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"uas-backend/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeleteAttachment godoc
+// @Summary Hapus lampiran prestasi
+// @Description
+// Hanya selama prestasi masih bisa diedit (default: draft, pemilik / admin).
+// File di storage ikut dihapus kalau tidak dipakai prestasi lain.
+// @Tags Achievements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 200 {object} map[string]interface{} "Attachment deleted"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Attachment not found"
+// @Failure 409 {object} map[string]interface{} "Achievement is not editable"
+// @Failure 500 {object} map[string]interface{} "Failed to delete attachment"
+// @Router /achievements/{id}/attachments/{attachmentId} [delete]
+func (s *AchievementService) DeleteAttachment(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	attachmentID := c.Params("attachmentId")
+
+	// 1️⃣ cek reference (PostgreSQL)
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+
+	// 2️⃣ workflow + auth: aturan sama dengan upload lampiran
+	if _, err := s.checkTransition(c, model.ActionUploadAttachment, ref); err != nil {
+		return err
+	}
+
+	// 3️⃣ lampiran yang dihapus
+	achievement, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return err
+	}
+	old, ok := findAttachment(achievement, attachmentID)
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, "attachment not found")
+	}
+
+	// 4️⃣ hapus dari MongoDB (false → sudah dihapus request lain)
+	removed, err := s.achievementRepo.RemoveAttachment(c.Context(), achievement.ID, attachmentID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to delete attachment")
+	}
+	if !removed {
+		return fiber.NewError(fiber.StatusNotFound, "attachment not found")
+	}
+	achievement.Attachments = slices.DeleteFunc(achievement.Attachments, func(a model.Attachment) bool {
+		return a.Matches(attachmentID)
+	})
+
+	// 5️⃣ bersihkan storage + catat di riwayat edit
+	s.releaseAttachment(c.Context(), achievement, old)
+	versionWarning := s.saveVersion(c, achievement, model.VersionAttachmentDelete, ref.ResubmissionCount)
+
+	return c.JSON(withVersionWarning(fiber.Map{
+		"message": "attachment deleted",
+		"data":    old,
+	}, versionWarning))
+}
+
+// ReplaceAttachment godoc
+// @Summary Ganti file lampiran prestasi
+// @Description
+// Lampiran baru (ID baru) menggantikan lampiran lama di posisi yang sama.
+// kind kosong → jenis bukti lampiran lama. Aturan ukuran / tipe sama dengan upload.
+// @Tags Achievements
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Param file formData file true "File pengganti"
+// @Param kind formData string false "Jenis bukti: certificate, appointment_letter, publication, photo, other (default jenis lampiran lama)"
+// @Success 200 {object} map[string]interface{} "Attachment replaced"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Attachment not found"
+// @Failure 409 {object} map[string]interface{} "File already attached"
+// @Failure 413 {object} map[string]interface{} "File too large / storage quota exceeded"
+// @Failure 415 {object} map[string]interface{} "File type not allowed"
+// @Failure 500 {object} map[string]interface{} "Failed to replace attachment"
+// @Router /achievements/{id}/attachments/{attachmentId} [put]
+func (s *AchievementService) ReplaceAttachment(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	attachmentID := c.Params("attachmentId")
+
+	// 1️⃣ cek reference (PostgreSQL)
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+
+	// 2️⃣ workflow + auth: aturan sama dengan upload lampiran
+	if _, err := s.checkTransition(c, model.ActionUploadAttachment, ref); err != nil {
+		return err
+	}
+
+	// 3️⃣ file pengganti
+	file, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "file is required")
+	}
+
+	// 4️⃣ lampiran lama
+	achievement, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return err
+	}
+	old, ok := findAttachment(achievement, attachmentID)
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, "attachment not found")
+	}
+
+	oldKind := old.Kind
+	if oldKind == "" {
+		oldKind = model.EvidenceOther
+	}
+	kind, err := attachmentKind(c, oldKind)
+	if err != nil {
+		return err
+	}
+
+	// 5️⃣ ukuran, tipe & hash file baru
+	attachment, err := s.prepareUpload(achievement, file, kind)
+	if err != nil {
+		return err
+	}
+
+	// isi yang sama sudah jadi lampiran lain
+	for _, att := range achievement.Attachments {
+		if att.Hash == attachment.Hash && !att.Matches(attachmentID) {
+			return fiber.NewError(fiber.StatusConflict, "file is already attached as another attachment")
+		}
+	}
+
+	// kuota: ukuran lampiran lama dikembalikan (dipesan sampai penggantian tercatat)
+	release, q, err := s.reserveQuota(c.Context(), achievement, attachment.Size, old.Size)
+	if err != nil || q != nil {
+		return quotaResponse(c, q, err)
+	}
+	defer release()
+
+	// 6️⃣ storage
+	created, err := s.putUpload(c.Context(), file, attachment)
+	if err != nil {
+		return err
+	}
+
+	// 7️⃣ ganti di MongoDB; gagal → hapus object baru
+	replaced, err := s.achievementRepo.ReplaceAttachment(c.Context(), achievement.ID, attachmentID, attachment)
+	if err != nil || !replaced {
+		if created {
+			s.discardUpload(c.Context(), attachment)
+		}
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to replace attachment")
+		}
+		return fiber.NewError(fiber.StatusNotFound, "attachment not found")
+	}
+	for i := range achievement.Attachments {
+		if achievement.Attachments[i].Matches(attachmentID) {
+			achievement.Attachments[i] = attachment
+		}
+	}
+	achievement.UpdatedAt = attachment.UploadedAt
+
+	// 8️⃣ bersihkan file lama + catat di riwayat edit
+	s.releaseAttachment(c.Context(), achievement, old)
+	versionWarning := s.saveVersion(c, achievement, model.VersionAttachmentReplace, ref.ResubmissionCount)
+	s.enqueuePreview(achievement.ID, attachment)
+
+	return c.JSON(withVersionWarning(fiber.Map{
+		"message": "attachment replaced",
+		"data":    attachment,
+	}, versionWarning))
+}
+
+// releaseAttachment: hapus file lampiran lama dari storage kalau tidak
+// dirujuk lampiran lain di prestasi ini maupun prestasi lain.
+// Gagal hapus hanya dicatat; perubahan lampiran sudah tersimpan.
+func (s *AchievementService) releaseAttachment(ctx context.Context, a *model.Achievement, old model.Attachment) {
+	key := old.StorageKey()
+	for _, att := range a.Attachments {
+		if att.StorageKey() == key {
+			return
+		}
+	}
+
+	if _, err := s.removeAttachmentObject(ctx, a.ID, old); err != nil {
+		fmt.Println("⚠️ ATTACHMENT CLEANUP FAILED:", key, err)
+	}
+}
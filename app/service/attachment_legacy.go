@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+
+	"uas-backend/app/storage"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BackfillLegacyAttachments: lampiran dari sebelum ada storage (hanya FileURL
+// "/uploads/x", tanpa ID / hash) diberi ID, hash & ukuran supaya bisa diunduh,
+// dihapus, dan diganti lewat endpoint lampiran. Aman dijalankan berulang.
+func (s *AchievementService) BackfillLegacyAttachments(ctx context.Context) (int, error) {
+	achievements, err := s.achievementRepo.FindLegacyAttachments(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for _, a := range achievements {
+		for _, legacy := range a.Attachments {
+			if legacy.ID != "" {
+				continue
+			}
+
+			att := legacy
+			att.ID = primitive.NewObjectID().Hex()
+
+			// hash & ukuran dari isi file; file hilang tetap diberi ID (download → 404)
+			if att.Hash == "" {
+				hash, size, err := s.hashObject(ctx, att.StorageKey())
+				if err != nil {
+					log.Printf("⚠️ Legacy attachment %s/%s: %v", a.ID.Hex(), att.FileName, err)
+				} else {
+					att.Hash, att.Size = hash, size
+				}
+			}
+
+			// false = sudah diisi proses lain
+			ok, err := s.achievementRepo.BackfillAttachment(ctx, a.ID, legacy, att)
+			if err != nil {
+				return done, fmt.Errorf("backfill attachment %s: %w", a.ID.Hex(), err)
+			}
+			if ok {
+				done++
+			}
+		}
+	}
+	return done, nil
+}
+
+// hashObject: sha256 (hex) + ukuran isi file di storage
+func (s *AchievementService) hashObject(ctx context.Context, key string) (string, int64, error) {
+	if err := storage.ValidKey(key); err != nil {
+		return "", 0, err
+	}
+
+	r, err := s.store.Get(ctx, key)
+	if err != nil {
+		return "", 0, err
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, r)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
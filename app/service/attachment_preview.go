@@ -0,0 +1,208 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/app/preview"
+	"uas-backend/app/storage"
+	"uas-backend/config"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// antrean pratinjau; kalau penuh, lampiran tetap pending dan diambil sapuan berikutnya
+const previewQueueSize = 64
+
+// batas waktu membuat pratinjau satu lampiran
+const previewTimeout = time.Minute
+
+type previewJob struct {
+	achievementID primitive.ObjectID
+	attachment    model.Attachment
+}
+
+// pratinjau disimpan di samping file asli (key berbasis hash → ikut dedupe)
+func thumbnailKey(key string) string { return key + ".thumb.jpg" }
+func previewKey(key string) string   { return key + ".preview.jpg" }
+
+// pendingPreview: status awal lampiran yang bisa dibuatkan pratinjau
+func pendingPreview(mediaType string) *model.AttachmentPreview {
+	if !preview.Supported(mediaType) {
+		return nil
+	}
+	return &model.AttachmentPreview{Status: model.PreviewPending}
+}
+
+// enqueuePreview: dipanggil setelah lampiran tersimpan; tidak pernah memblok request
+func (s *AchievementService) enqueuePreview(achievementID primitive.ObjectID, att model.Attachment) {
+	if att.Preview == nil || att.Preview.Status != model.PreviewPending {
+		return
+	}
+
+	select {
+	case s.previewJobs <- previewJob{achievementID: achievementID, attachment: att}:
+	default:
+		fmt.Println("⚠️ PREVIEW QUEUE FULL, deferred:", att.ID)
+	}
+}
+
+// GeneratePreview: buat thumbnail + pratinjau JPEG satu lampiran lalu simpan statusnya.
+// PDF tidak di-render: "halaman pertama" = gambar terbesar yang tertanam di halaman 1
+// (sertifikat hasil scan). PDF teks / vektor tanpa gambar, seperti banyak sertifikat
+// digital, dan isi lain yang tidak bisa dipratinjau → unavailable. File asli hilang
+// → failed. Gangguan storage sementara → tetap pending (status tidak ditulis),
+// jadi diambil lagi oleh sapuan berikutnya.
+func (s *AchievementService) GeneratePreview(
+	ctx context.Context,
+	achievementID primitive.ObjectID,
+	att model.Attachment,
+) (model.AttachmentPreview, error) {
+	result, err := s.renderPreview(ctx, att)
+	if result.Status == model.PreviewPending {
+		return result, err
+	}
+
+	if err := s.achievementRepo.SetAttachmentPreview(ctx, achievementID, att.ID, result); err != nil {
+		return result, fmt.Errorf("save preview status: %w", err)
+	}
+	return result, err
+}
+
+func (s *AchievementService) renderPreview(ctx context.Context, att model.Attachment) (model.AttachmentPreview, error) {
+	unavailable := model.AttachmentPreview{Status: model.PreviewUnavailable}
+	failed := model.AttachmentPreview{Status: model.PreviewFailed}
+	retry := model.AttachmentPreview{Status: model.PreviewPending}
+
+	key := att.StorageKey()
+	if key == "" || !preview.Supported(att.FileType) {
+		return unavailable, nil
+	}
+
+	// 1️⃣ baca file asli (maks. batas upload); hanya file yang hilang yang permanen
+	r, err := s.store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return failed, err
+	}
+	if err != nil {
+		return retry, err
+	}
+	data, err := io.ReadAll(io.LimitReader(r, config.AttachmentMaxSize()+1))
+	r.Close()
+	if err != nil {
+		return retry, err
+	}
+
+	// 2️⃣ gambar sumber; tidak bisa di-decode bukan error sistem
+	img, err := preview.Decode(data, att.FileType)
+	if err != nil {
+		return unavailable, nil
+	}
+
+	// 3️⃣ dua ukuran JPEG
+	result := model.AttachmentPreview{
+		Status:       model.PreviewReady,
+		ThumbnailKey: thumbnailKey(key),
+		PreviewKey:   previewKey(key),
+	}
+	for _, variant := range []struct {
+		key  string
+		size int
+	}{
+		{result.PreviewKey, preview.PreviewSize},
+		{result.ThumbnailKey, preview.ThumbnailSize},
+	} {
+		fitted := preview.Fit(img, variant.size)
+		out, err := preview.EncodeJPEG(fitted)
+		if err != nil {
+			return failed, err
+		}
+		if err := s.store.Put(ctx, variant.key, bytes.NewReader(out), int64(len(out)), "image/jpeg"); err != nil {
+			return retry, err
+		}
+		if variant.size == preview.PreviewSize {
+			result.Width, result.Height = fitted.Bounds().Dx(), fitted.Bounds().Dy()
+		}
+	}
+
+	return result, nil
+}
+
+// GeneratePendingPreviews: proses semua lampiran yang masih pending
+// (antrean penuh, server restart sebelum antrean habis)
+func (s *AchievementService) GeneratePendingPreviews(ctx context.Context) (int, error) {
+	achievements, err := s.achievementRepo.FindPendingPreviews(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for _, a := range achievements {
+		for _, att := range a.Attachments {
+			if att.Preview == nil || att.Preview.Status != model.PreviewPending {
+				continue
+			}
+			if s.runPreviewJob(ctx, previewJob{achievementID: a.ID, attachment: att}) {
+				done++
+			}
+		}
+	}
+	return done, nil
+}
+
+// runPreviewJob: satu lampiran; panic dari decoder (file rusak) tidak boleh
+// menjatuhkan server → lampiran ditandai failed supaya tidak diambil sapuan lagi
+func (s *AchievementService) runPreviewJob(ctx context.Context, job previewJob) (ok bool) {
+	ctx, cancel := context.WithTimeout(ctx, previewTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("⚠️ Preview %s/%s panicked: %v", job.achievementID.Hex(), job.attachment.ID, r)
+			failed := model.AttachmentPreview{Status: model.PreviewFailed}
+			if err := s.achievementRepo.SetAttachmentPreview(ctx, job.achievementID, job.attachment.ID, failed); err != nil {
+				log.Printf("⚠️ Preview %s/%s status not saved: %v", job.achievementID.Hex(), job.attachment.ID, err)
+			}
+			ok = false
+		}
+	}()
+
+	if _, err := s.GeneratePreview(ctx, job.achievementID, job.attachment); err != nil {
+		log.Printf("⚠️ Preview %s/%s failed: %v", job.achievementID.Hex(), job.attachment.ID, err)
+		return false
+	}
+	return true
+}
+
+// StartPreviewWorker: proses antrean pratinjau satu per satu,
+// plus sapuan lampiran pending setiap interval, sampai ctx selesai
+func (s *AchievementService) StartPreviewWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	sweep := func() {
+		if n, err := s.GeneratePendingPreviews(ctx); err != nil {
+			log.Printf("⚠️ Preview sweep failed: %v", err)
+		} else if n > 0 {
+			log.Printf("🖼️ Generated %d pending attachment previews", n)
+		}
+	}
+	sweep()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.previewJobs:
+			s.runPreviewJob(ctx, job)
+		case <-ticker.C:
+			sweep()
+		}
+	}
+}
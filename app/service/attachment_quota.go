@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// quotaReservationTTL: pesanan kuota yang tidak dilepas (proses mati di tengah
+// upload) berhenti dihitung setelah ini
+const quotaReservationTTL = 30 * time.Minute
+
+// checkQuota: apakah lampiran baru sebesar add (menggantikan freed byte) masih muat
+// di kuota prestasi & kuota mahasiswa pemilik. Upload bertahap yang belum selesai dan
+// pesanan kuota upload lain yang sedang berjalan ikut dihitung. nil = muat.
+func (s *AchievementService) checkQuota(
+	ctx context.Context,
+	a *model.Achievement,
+	add, freed int64,
+	reserved []model.QuotaReservation,
+) (*model.QuotaExceeded, error) {
+
+	// file pengganti lebih kecil → pemakaian tidak bertambah
+	if add <= freed {
+		return nil, nil
+	}
+
+	sessions, err := s.uploadRepo.FindOpenByStudent(ctx, a.StudentID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	var studentReserved, achievementReserved int64
+	for _, sess := range sessions {
+		studentReserved += sess.Size
+		if sess.AchievementID == a.ID.Hex() {
+			achievementReserved += sess.Size
+		}
+	}
+	for _, res := range reserved {
+		studentReserved += res.Bytes
+		if res.AchievementID == a.ID.Hex() {
+			achievementReserved += res.Bytes
+		}
+	}
+
+	// 1️⃣ kuota prestasi: cukup dari dokumen yang sudah dibaca
+	var achievementUsed int64
+	for _, att := range a.Attachments {
+		achievementUsed += att.Size
+	}
+	if q := quotaCheck(model.QuotaScopeAchievement, achievementUsed+achievementReserved-freed, add,
+		config.AttachmentAchievementQuota()); q != nil {
+		return q, nil
+	}
+
+	// 2️⃣ kuota mahasiswa: semua prestasi miliknya
+	usage, err := s.achievementRepo.StorageByStudent(ctx, []string{a.StudentID}, 1)
+	if err != nil {
+		return nil, err
+	}
+	var studentUsed int64
+	if len(usage) > 0 {
+		studentUsed = usage[0].UsedBytes
+	}
+	return quotaCheck(model.QuotaScopeStudent, studentUsed+studentReserved-freed, add,
+		config.AttachmentStudentQuota()), nil
+}
+
+// reserveQuota: checkQuota di bawah kunci per mahasiswa pemilik, lalu pesan
+// add-freed byte. Kunci hanya ditahan selama cek + pencatatan pesanan; pesanan
+// yang menahan kuota sampai release dipanggil (setelah lampiran / sesi upload
+// tercatat), supaya upload paralel tidak sama-sama lolos dan file tidak dikirim
+// ke storage sambil memegang koneksi database. Prestasi dibaca ulang di bawah kunci.
+func (s *AchievementService) reserveQuota(
+	ctx context.Context,
+	a *model.Achievement,
+	add, freed int64,
+) (release func(), q *model.QuotaExceeded, err error) {
+
+	// pemakaian tidak bertambah → tidak perlu memesan
+	if add <= freed {
+		return func() {}, nil, nil
+	}
+
+	res := &model.QuotaReservation{
+		StudentID:     a.StudentID,
+		AchievementID: a.ID.Hex(),
+		Bytes:         add - freed,
+		ExpiresAt:     time.Now().Add(quotaReservationTTL),
+	}
+	ok, err := s.referenceRepo.ReserveStudentQuota(ctx, res, func(reserved []model.QuotaReservation) (bool, error) {
+		fresh, err := s.achievementRepo.GetByID(ctx, a.ID)
+		if err != nil {
+			return false, err
+		}
+		q, err = s.checkQuota(ctx, fresh, add, freed, reserved)
+		return q == nil, err
+	})
+	if err != nil || !ok {
+		return nil, q, err
+	}
+
+	return func() {
+		// gagal lepas → pesanan kedaluwarsa sendiri setelah quotaReservationTTL
+		if err := s.referenceRepo.ReleaseQuotaReservation(context.Background(), res.ID); err != nil {
+			fmt.Println("⚠️ QUOTA RESERVATION RELEASE FAILED:", res.ID, err)
+		}
+	}, nil, nil
+}
+
+func quotaCheck(scope string, used, add, quota int64) *model.QuotaExceeded {
+	if used+add <= quota {
+		return nil
+	}
+	return &model.QuotaExceeded{
+		Scope:          scope,
+		UsedBytes:      max(used, 0),
+		QuotaBytes:     quota,
+		RequestedBytes: add,
+	}
+}
+
+// quotaResponse: dipanggil kalau checkQuota gagal (500) atau kuota habis (413, JSON)
+func quotaResponse(c *fiber.Ctx, q *model.QuotaExceeded, err error) error {
+	if err != nil {
+		fmt.Println("⚠️ STORAGE QUOTA CHECK FAILED:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to check storage quota")
+	}
+	return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+		"message": fmt.Sprintf("%s storage quota exceeded: %s of %s used, file needs %s",
+			q.Scope, formatMB(q.UsedBytes), formatMB(q.QuotaBytes), formatMB(q.RequestedBytes)),
+		"quota": q,
+	})
+}
+
+func formatMB(n int64) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
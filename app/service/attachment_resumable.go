@@ -0,0 +1,587 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/app/storage"
+	"uas-backend/config"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// header upload bertahap (nama sama dengan protokol tus)
+const (
+	headerUploadOffset   = "Upload-Offset"
+	headerUploadLength   = "Upload-Length"
+	headerUploadChecksum = "Upload-Checksum" // "sha256 <base64>"
+)
+
+// kode tus untuk checksum potongan / file yang tidak cocok
+const statusChecksumMismatch = 460
+
+// byte awal yang dipakai http.DetectContentType
+const sniffLength = 512
+
+type CreateUploadRequest struct {
+	FileName string `json:"file_name"`
+	Size     int64  `json:"size"`
+	Kind     string `json:"kind"`
+	// sha256 (hex) seluruh file, opsional; dicek saat upload selesai
+	Checksum string `json:"checksum"`
+}
+
+// CreateUpload godoc
+// @Summary Mulai upload lampiran bertahap
+// @Description
+// Untuk file besar / koneksi tidak stabil: buat sesi, lalu kirim isi file per potongan
+// lewat PATCH /achievements/{id}/uploads/{uploadId}. Aturan ukuran & tipe sama dengan upload biasa.
+// @Tags Achievements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param body body service.CreateUploadRequest true "File yang akan diupload"
+// @Success 201 {object} map[string]interface{} "Upload session created"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 409 {object} map[string]interface{} "Achievement is not editable"
+// @Failure 413 {object} map[string]interface{} "File too large / storage quota exceeded"
+// @Router /achievements/{id}/uploads [post]
+func (s *AchievementService) CreateUpload(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+
+	// 1️⃣ workflow + auth: aturan sama dengan upload lampiran
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+	if _, err := s.checkTransition(c, model.ActionUploadAttachment, ref); err != nil {
+		return err
+	}
+
+	// 2️⃣ validasi request
+	var req CreateUploadRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Size <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "size is required")
+	}
+	if req.Kind == "" {
+		req.Kind = model.EvidenceOther
+	}
+	if !slices.Contains(model.EvidenceKinds, req.Kind) {
+		return fiber.NewError(fiber.StatusBadRequest, "kind must be one of: "+strings.Join(model.EvidenceKinds, ", "))
+	}
+	req.Checksum = strings.ToLower(req.Checksum)
+	if b, err := hex.DecodeString(req.Checksum); req.Checksum != "" && (err != nil || len(b) != sha256.Size) {
+		return fiber.NewError(fiber.StatusBadRequest, "checksum must be a hex sha256")
+	}
+
+	// 3️⃣ batas ukuran sesuai tipe prestasi (ditolak sebelum ada byte yang dikirim)
+	achievement, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return err
+	}
+	if limit := uploadLimit(s.schemas.UploadPolicy(achievement.AchievementType), config.AttachmentResumableMaxSize()); req.Size > limit {
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge,
+			fmt.Sprintf("file too large (max %d MB)", limit>>20))
+	}
+
+	// kuota dipesan sejak sesi dibuat: sesi terbuka ikut dihitung checkQuota;
+	// pesanan sementara menutup jeda sampai sesi tersimpan supaya dua sesi
+	// paralel tidak sama-sama lolos cek
+	release, q, err := s.reserveQuota(c.Context(), achievement, req.Size, 0)
+	if err != nil || q != nil {
+		return quotaResponse(c, q, err)
+	}
+	defer release()
+
+	// 4️⃣ simpan sesi
+	claims := c.Locals("user").(*model.JWTClaims)
+	now := time.Now()
+	session := model.UploadSession{
+		AchievementID: achievementID,
+		StudentID:     achievement.StudentID,
+		FileName:      cleanFileName(req.FileName),
+		Kind:          req.Kind,
+		Size:          req.Size,
+		Checksum:      req.Checksum,
+		Chunks:        []model.UploadChunk{},
+		CreatedBy:     claims.UserID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		ExpiresAt:     now.Add(config.AttachmentUploadTTL()),
+	}
+	if err := s.uploadRepo.Create(c.Context(), &session); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to create upload session")
+	}
+
+	c.Set(fiber.HeaderLocation, "/api/v1/achievements/"+achievementID+"/uploads/"+session.ID.Hex())
+	setUploadHeaders(c, &session)
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":    "upload session created",
+		"data":       session,
+		"chunk_size": config.AttachmentChunkSize(),
+	})
+}
+
+// GetUpload godoc
+// @Summary Status upload lampiran bertahap
+// @Description
+// Offset = byte yang sudah diterima; lanjutkan upload dari offset ini (juga lewat header Upload-Offset, bisa HEAD).
+// @Tags Achievements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param uploadId path string true "Upload session ID"
+// @Success 200 {object} map[string]interface{} "Upload session"
+// @Failure 404 {object} map[string]interface{} "Upload not found"
+// @Failure 410 {object} map[string]interface{} "Upload session expired"
+// @Router /achievements/{id}/uploads/{uploadId} [get]
+func (s *AchievementService) GetUpload(c *fiber.Ctx) error {
+	session, err := s.uploadSession(c)
+	if err != nil {
+		return err
+	}
+
+	setUploadHeaders(c, session)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(fiber.Map{
+		"data":       session,
+		"chunk_size": config.AttachmentChunkSize(),
+	})
+}
+
+// PatchUpload godoc
+// @Summary Kirim satu potongan upload lampiran bertahap
+// @Description
+// Body = isi file mulai dari Upload-Offset (application/offset+octet-stream).
+// Header Upload-Checksum "sha256 <base64>" opsional. Potongan terakhir menggabungkan file
+// jadi lampiran prestasi (201). PATCH kosong di offset akhir mengulang penggabungan yang gagal.
+// @Tags Achievements
+// @Security BearerAuth
+// @Accept octet-stream
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param uploadId path string true "Upload session ID"
+// @Param Upload-Offset header int true "Offset potongan"
+// @Param Upload-Checksum header string false "sha256 <base64> isi potongan"
+// @Success 200 {object} map[string]interface{} "Chunk received"
+// @Success 201 {object} map[string]interface{} "Attachment uploaded"
+// @Failure 400 {object} map[string]interface{} "Invalid chunk"
+// @Failure 404 {object} map[string]interface{} "Upload not found"
+// @Failure 409 {object} map[string]interface{} "Offset mismatch / upload is already being finished"
+// @Failure 410 {object} map[string]interface{} "Upload session expired"
+// @Failure 413 {object} map[string]interface{} "Chunk too large"
+// @Failure 415 {object} map[string]interface{} "File type not allowed"
+// @Failure 460 {object} map[string]interface{} "Checksum mismatch"
+// @Router /achievements/{id}/uploads/{uploadId} [patch]
+func (s *AchievementService) PatchUpload(c *fiber.Ctx) error {
+	// 1️⃣ sesi (workflow + auth dicek ulang tiap potongan)
+	session, err := s.uploadSession(c)
+	if err != nil {
+		return err
+	}
+
+	// 2️⃣ potongan harus melanjutkan tepat dari offset tersimpan
+	offset, err := strconv.ParseInt(c.Get(headerUploadOffset), 10, 64)
+	if err != nil || offset < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "Upload-Offset header is required")
+	}
+	if offset != session.Offset {
+		setUploadHeaders(c, session)
+		return fiber.NewError(fiber.StatusConflict,
+			fmt.Sprintf("offset mismatch (expected %d)", session.Offset))
+	}
+
+	body := c.Body()
+	if len(body) == 0 && session.Offset < session.Size {
+		return fiber.NewError(fiber.StatusBadRequest, "chunk is empty")
+	}
+
+	if len(body) > 0 {
+		// 3️⃣ ukuran & checksum potongan
+		if int64(len(body)) > config.AttachmentChunkSize() {
+			return fiber.NewError(fiber.StatusRequestEntityTooLarge,
+				fmt.Sprintf("chunk too large (max %d bytes)", config.AttachmentChunkSize()))
+		}
+		if session.Offset+int64(len(body)) > session.Size {
+			return fiber.NewError(fiber.StatusRequestEntityTooLarge, "chunk exceeds upload size")
+		}
+		if err := checkChunkChecksum(c.Get(headerUploadChecksum), body); err != nil {
+			return err
+		}
+
+		// 4️⃣ simpan potongan + progres
+		if err := s.appendChunk(c, session, body); err != nil {
+			return err
+		}
+	}
+
+	// 5️⃣ belum lengkap → client lanjut dari offset baru
+	setUploadHeaders(c, session)
+	if session.Offset < session.Size {
+		return c.JSON(fiber.Map{
+			"message": "chunk received",
+			"data":    session,
+		})
+	}
+
+	// 6️⃣ lengkap → gabungkan jadi lampiran
+	return s.finishUpload(c, session)
+}
+
+// CancelUpload godoc
+// @Summary Batalkan upload lampiran bertahap
+// @Tags Achievements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Achievement ID"
+// @Param uploadId path string true "Upload session ID"
+// @Success 200 {object} map[string]interface{} "Upload cancelled"
+// @Failure 404 {object} map[string]interface{} "Upload not found"
+// @Router /achievements/{id}/uploads/{uploadId} [delete]
+func (s *AchievementService) CancelUpload(c *fiber.Ctx) error {
+	session, err := s.uploadSession(c)
+	if err != nil {
+		return err
+	}
+
+	s.discardSession(c.Context(), session)
+	return c.JSON(fiber.Map{
+		"message": "upload cancelled",
+	})
+}
+
+// uploadSession: sesi dari path; hanya pembuat sesi, selama prestasi masih bisa diedit
+func (s *AchievementService) uploadSession(c *fiber.Ctx) (*model.UploadSession, error) {
+	achievementID := c.Params("id")
+
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+	if _, err := s.checkTransition(c, model.ActionUploadAttachment, ref); err != nil {
+		return nil, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Params("uploadId"))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "upload not found")
+	}
+	session, err := s.uploadRepo.GetByID(c.Context(), id)
+	claims := c.Locals("user").(*model.JWTClaims)
+	if err != nil || session.AchievementID != achievementID || session.CreatedBy != claims.UserID {
+		return nil, fiber.NewError(fiber.StatusNotFound, "upload not found")
+	}
+
+	// belum disapu job pembersihan, tapi sudah tidak boleh dilanjutkan
+	if time.Now().After(session.ExpiresAt) {
+		return nil, fiber.NewError(fiber.StatusGone, "upload session expired")
+	}
+	return session, nil
+}
+
+func setUploadHeaders(c *fiber.Ctx, session *model.UploadSession) {
+	c.Set(headerUploadOffset, strconv.FormatInt(session.Offset, 10))
+	c.Set(headerUploadLength, strconv.FormatInt(session.Size, 10))
+}
+
+// checkChunkChecksum: header kosong = tidak dicek
+func checkChunkChecksum(header string, body []byte) error {
+	if header == "" {
+		return nil
+	}
+
+	algo, value, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(algo, "sha256") {
+		return fiber.NewError(fiber.StatusBadRequest, "unsupported checksum algorithm (use sha256)")
+	}
+	want, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid Upload-Checksum header")
+	}
+
+	sum := sha256.Sum256(body)
+	if !bytes.Equal(sum[:], want) {
+		return fiber.NewError(statusChecksumMismatch, "chunk checksum mismatch")
+	}
+	return nil
+}
+
+// sessionHash: sha256 berjalan dari state tersimpan
+func sessionHash(session *model.UploadSession) (hash.Hash, error) {
+	h := sha256.New()
+	if len(session.HashState) > 0 {
+		if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(session.HashState); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// appendChunk: simpan potongan ke storage lalu majukan offset sesi.
+// Key potongan unik per request, jadi potongan paralel di offset sama tidak saling menimpa.
+func (s *AchievementService) appendChunk(c *fiber.Ctx, session *model.UploadSession, body []byte) error {
+	from := session.Offset
+	headBefore := len(session.Head)
+
+	h, err := sessionHash(session)
+	if err != nil {
+		fmt.Println("⚠️ UPLOAD HASH STATE INVALID:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to save chunk")
+	}
+	h.Write(body)
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to save chunk")
+	}
+
+	chunk := model.UploadChunk{
+		Key:    fmt.Sprintf("uploads/%s/%012d-%s", session.ID.Hex(), from, primitive.NewObjectID().Hex()),
+		Offset: from,
+		Size:   int64(len(body)),
+	}
+	if n := sniffLength - headBefore; n > 0 {
+		session.Head = append(session.Head, body[:min(n, len(body))]...)
+	}
+	session.Offset += chunk.Size
+	session.Chunks = append(session.Chunks, chunk)
+	session.HashState = state
+	session.UpdatedAt = time.Now()
+	session.ExpiresAt = session.UpdatedAt.Add(config.AttachmentUploadTTL())
+
+	// tipe file dicek begitu byte awal lengkap, bukan setelah seluruh video terkirim
+	if headBefore < sniffLength && (len(session.Head) == sniffLength || session.Offset == session.Size) {
+		achievement, err := s.getAchievement(c, session.AchievementID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkUploadType(achievement, session.Kind, sniffMediaType(session.Head)); err != nil {
+			s.discardSession(c.Context(), session)
+			return err
+		}
+	}
+
+	if err := s.store.Put(c.Context(), chunk.Key, bytes.NewReader(body), chunk.Size, "application/octet-stream"); err != nil {
+		fmt.Println("⚠️ STORAGE PUT FAILED:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to save chunk")
+	}
+
+	// offset sudah dimajukan request lain → potongan ini tidak dipakai
+	advanced, err := s.uploadRepo.Advance(c.Context(), session, from)
+	if err != nil || !advanced {
+		if err := s.store.Delete(c.Context(), chunk.Key); err != nil {
+			fmt.Println("⚠️ STORAGE CLEANUP FAILED:", err)
+		}
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to save chunk")
+		}
+		return fiber.NewError(fiber.StatusConflict, "offset mismatch (chunk already received)")
+	}
+	return nil
+}
+
+// uploadFinishLease: klaim penggabungan yang lebih lama dari ini dianggap
+// ditinggalkan (proses mati di tengah), sesi boleh digabung ulang
+const uploadFinishLease = 15 * time.Minute
+
+// finishUpload: gabungkan potongan jadi satu object (key dari hash isi, ikut dedupe),
+// catat sebagai lampiran, lalu hapus sesi. Hanya satu request yang boleh menggabungkan
+// (PATCH akhir paralel / retry → 409). Gagal simpan → klaim dilepas, sesi bisa diulang.
+func (s *AchievementService) finishUpload(c *fiber.Ctx, session *model.UploadSession) (err error) {
+	// 1️⃣ klaim sesi
+	now := time.Now()
+	claimed, err := s.uploadRepo.ClaimFinish(c.Context(), session.ID, now, now.Add(-uploadFinishLease))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to save file")
+	}
+	if !claimed {
+		return fiber.NewError(fiber.StatusConflict, "upload is already being finished")
+	}
+	// gagal → klaim dilepas (sesi yang sudah dibuang tidak terpengaruh)
+	defer func() {
+		if err != nil {
+			if err := s.uploadRepo.ReleaseFinish(context.Background(), session.ID); err != nil {
+				fmt.Println("⚠️ RELEASE UPLOAD CLAIM FAILED:", err)
+			}
+		}
+	}()
+
+	// 2️⃣ aturan lampiran sesuai tipe prestasi (dibaca setelah klaim)
+	achievement, err := s.getAchievement(c, session.AchievementID)
+	if err != nil {
+		return err
+	}
+	mediaType := sniffMediaType(session.Head)
+	if err := s.checkUploadType(achievement, session.Kind, mediaType); err != nil {
+		s.discardSession(c.Context(), session)
+		return err
+	}
+
+	// 3️⃣ hash dari state berjalan; checksum seluruh file kalau client mengirimkannya
+	h, err := sessionHash(session)
+	if err != nil {
+		fmt.Println("⚠️ UPLOAD HASH STATE INVALID:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to save file")
+	}
+	info := uploadInfo{Hash: hex.EncodeToString(h.Sum(nil)), MediaType: mediaType}
+	if session.Checksum != "" && session.Checksum != info.Hash {
+		s.discardSession(c.Context(), session)
+		return fiber.NewError(statusChecksumMismatch, "file checksum mismatch")
+	}
+
+	// isi yang sama sudah dilampirkan → kembalikan lampiran lama
+	for _, att := range achievement.Attachments {
+		if att.Hash == info.Hash {
+			s.discardSession(c.Context(), session)
+			return c.JSON(fiber.Map{
+				"message": "attachment already uploaded",
+				"data":    att,
+			})
+		}
+	}
+
+	attachment := newAttachment(session.FileName, info, session.Size, session.Kind)
+
+	// 4️⃣ gabungkan potongan ke storage kalau isi ini belum pernah diupload
+	exists, err := s.store.Exists(c.Context(), attachment.Key)
+	if err != nil {
+		fmt.Println("⚠️ STORAGE CHECK FAILED:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to save file")
+	}
+	if !exists {
+		r := &chunkReader{ctx: c.Context(), store: s.store, chunks: session.Chunks}
+		err := s.store.Put(c.Context(), attachment.Key, r, attachment.Size, attachment.FileType)
+		r.Close()
+		if err != nil {
+			fmt.Println("⚠️ STORAGE PUT FAILED:", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to save file")
+		}
+	}
+
+	// 5️⃣ catat di MongoDB; gagal → hapus object baru supaya tidak jadi sampah
+	added, err := s.achievementRepo.AddAttachment(c.Context(), achievement.ID, attachment)
+	if err != nil {
+		if !exists {
+			s.discardUpload(c.Context(), attachment)
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to save attachment")
+	}
+	if !added {
+		s.discardSession(c.Context(), session)
+		return s.alreadyAttached(c, achievement.ID, attachment)
+	}
+
+	// 6️⃣ pratinjau di background, potongan sementara dibuang
+	s.enqueuePreview(achievement.ID, attachment)
+	s.discardSession(c.Context(), session)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "attachment uploaded",
+		"data":    attachment,
+	})
+}
+
+// discardSession: hapus potongan di storage lalu sesinya; gagal hanya dicatat
+// (potongan yatim tidak dirujuk lampiran mana pun)
+func (s *AchievementService) discardSession(ctx context.Context, session *model.UploadSession) {
+	for _, chunk := range session.Chunks {
+		if err := s.store.Delete(ctx, chunk.Key); err != nil {
+			fmt.Println("⚠️ STORAGE CLEANUP FAILED:", err)
+		}
+	}
+	if err := s.uploadRepo.Delete(ctx, session.ID); err != nil {
+		fmt.Println("⚠️ DELETE UPLOAD SESSION FAILED:", err)
+	}
+}
+
+// CleanupUploads: buang sesi upload yang kedaluwarsa sebelum now
+func (s *AchievementService) CleanupUploads(ctx context.Context, now time.Time) (int, error) {
+	sessions, err := s.uploadRepo.FindExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	for i := range sessions {
+		s.discardSession(ctx, &sessions[i])
+	}
+	return len(sessions), nil
+}
+
+// StartUploadCleanupJob: jalankan CleanupUploads berkala sampai ctx selesai
+func (s *AchievementService) StartUploadCleanupJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.CleanupUploads(ctx, time.Now())
+		if err != nil {
+			log.Printf("⚠️ Upload cleanup failed: %v", err)
+		} else if n > 0 {
+			log.Printf("🧹 Removed %d abandoned upload sessions", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// chunkReader: baca potongan berurutan, satu object terbuka dalam satu waktu
+type chunkReader struct {
+	ctx    context.Context
+	store  storage.Storage
+	chunks []model.UploadChunk
+	cur    io.ReadCloser
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+			rc, err := r.store.Get(r.ctx, r.chunks[0].Key)
+			if err != nil {
+				return 0, fmt.Errorf("read chunk %s: %w", r.chunks[0].Key, err)
+			}
+			r.cur, r.chunks = rc, r.chunks[1:]
+		}
+
+		n, err := r.cur.Read(p)
+		if err == io.EOF {
+			r.cur.Close()
+			r.cur = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.cur == nil {
+		return nil
+	}
+	return r.cur.Close()
+}
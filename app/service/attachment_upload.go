@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"uas-backend/app/model"
+	"uas-backend/config"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// panjang maksimal nama file asli yang disimpan sebagai metadata
+const maxFileNameLength = 255
+
+// uploadInfo: hasil pemeriksaan isi file upload
+type uploadInfo struct {
+	Hash      string // sha256 isi file (hex)
+	MediaType string // hasil sniffing, bukan Content-Type dari client
+}
+
+// inspectUpload: hash + sniffing MIME (512 byte pertama) dalam satu kali baca
+func inspectUpload(file *multipart.FileHeader) (uploadInfo, error) {
+	f, err := file.Open()
+	if err != nil {
+		return uploadInfo{}, err
+	}
+	defer f.Close()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return uploadInfo{}, err
+	}
+	head = head[:n]
+
+	h := sha256.New()
+	h.Write(head)
+	if _, err := io.Copy(h, f); err != nil {
+		return uploadInfo{}, err
+	}
+
+	return uploadInfo{
+		Hash:      hex.EncodeToString(h.Sum(nil)),
+		MediaType: sniffMediaType(head),
+	}, nil
+}
+
+// sniffMediaType: tipe asli dari byte awal isi file (tanpa parameter charset dsb.)
+func sniffMediaType(head []byte) string {
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	return mediaType
+}
+
+// contentKey: key dari hash isi → file identik hanya disimpan sekali
+// dan nama file dari client tidak pernah jadi bagian path
+func contentKey(hash string) string {
+	return "sha256/" + hash[:2] + "/" + hash
+}
+
+// cleanFileName: nama asli hanya untuk tampilan / download;
+// buang path, karakter kontrol & tanda kutip
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	for len(name) > maxFileNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	return name
+}
+
+// uploadLimit: batas ukuran lampiran (byte) untuk tipe prestasi;
+// batas per tipe hanya bisa lebih kecil dari batas global (upload biasa / bertahap)
+func uploadLimit(policy model.UploadPolicy, limit int64) int64 {
+	if policy.MaxSizeMB > 0 {
+		limit = min(limit, policy.MaxSizeMB<<20)
+	}
+	return limit
+}
+
+// attachmentKind: field form "kind" (jenis bukti), fallback kalau kosong
+func attachmentKind(c *fiber.Ctx, fallback string) (string, error) {
+	kind := c.FormValue("kind", fallback)
+	if !slices.Contains(model.EvidenceKinds, kind) {
+		return "", fiber.NewError(fiber.StatusBadRequest, "kind must be one of: "+strings.Join(model.EvidenceKinds, ", "))
+	}
+	return kind, nil
+}
+
+// prepareUpload: cek ukuran & tipe file sesuai tipe prestasi,
+// lalu susun metadata lampiran baru (belum disimpan)
+func (s *AchievementService) prepareUpload(
+	a *model.Achievement,
+	file *multipart.FileHeader,
+	kind string,
+) (model.Attachment, error) {
+	policy := s.schemas.UploadPolicy(a.AchievementType)
+
+	if limit := uploadLimit(policy, config.AttachmentMaxSize()); file.Size > limit {
+		return model.Attachment{}, fiber.NewError(fiber.StatusRequestEntityTooLarge,
+			fmt.Sprintf("file too large (max %d MB)", limit>>20))
+	}
+
+	info, err := inspectUpload(file)
+	if err != nil {
+		return model.Attachment{}, fiber.NewError(fiber.StatusBadRequest, "failed to read file")
+	}
+	if err := s.checkUploadType(a, kind, info.MediaType); err != nil {
+		return model.Attachment{}, err
+	}
+
+	return newAttachment(file.Filename, info, file.Size, kind), nil
+}
+
+// checkUploadType: tipe file (hasil sniffing) harus diizinkan tipe prestasi;
+// video hanya untuk jenis bukti dokumentasi
+func (s *AchievementService) checkUploadType(a *model.Achievement, kind, mediaType string) error {
+	policy := s.schemas.UploadPolicy(a.AchievementType)
+	if !mediaTypeAllowed(mediaType, policy.FileTypes) {
+		return fiber.NewError(fiber.StatusUnsupportedMediaType,
+			fmt.Sprintf("file type %s is not allowed for %s (allowed: %s)",
+				mediaType, a.AchievementType, strings.Join(policy.FileTypes, ", ")))
+	}
+	if strings.HasPrefix(mediaType, "video/") && !slices.Contains(model.VideoEvidenceKinds, kind) {
+		return fiber.NewError(fiber.StatusUnsupportedMediaType,
+			fmt.Sprintf("video is only allowed for kind: %s", strings.Join(model.VideoEvidenceKinds, ", ")))
+	}
+	return nil
+}
+
+// newAttachment: metadata lampiran baru (belum disimpan)
+func newAttachment(fileName string, info uploadInfo, size int64, kind string) model.Attachment {
+	return model.Attachment{
+		ID:         primitive.NewObjectID().Hex(),
+		FileName:   cleanFileName(fileName),
+		Key:        contentKey(info.Hash),
+		FileType:   info.MediaType,
+		Size:       size,
+		Kind:       kind,
+		Hash:       info.Hash,
+		UploadedAt: time.Now(),
+		Preview:    pendingPreview(info.MediaType),
+	}
+}
+
+// putUpload: simpan isi file ke storage kalau key belum ada.
+// true = object baru dibuat (boleh dihapus lagi kalau langkah berikutnya gagal)
+func (s *AchievementService) putUpload(
+	ctx context.Context,
+	file *multipart.FileHeader,
+	att model.Attachment,
+) (bool, error) {
+	exists, err := s.store.Exists(ctx, att.Key)
+	if err != nil {
+		fmt.Println("⚠️ STORAGE CHECK FAILED:", err)
+		return false, fiber.NewError(500, "failed to save file")
+	}
+	if exists {
+		return false, nil
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return false, fiber.NewError(fiber.StatusBadRequest, "failed to read file")
+	}
+	defer src.Close()
+
+	if err := s.store.Put(ctx, att.Key, src, file.Size, att.FileType); err != nil {
+		fmt.Println("⚠️ STORAGE PUT FAILED:", err)
+		return false, fiber.NewError(500, "failed to save file")
+	}
+	return true, nil
+}
+
+// discardUpload: hapus object yang baru dibuat putUpload supaya tidak jadi sampah
+func (s *AchievementService) discardUpload(ctx context.Context, att model.Attachment) {
+	if err := s.store.Delete(ctx, att.Key); err != nil {
+		fmt.Println("⚠️ STORAGE CLEANUP FAILED:", err)
+	}
+}
+
+// alreadyAttached: AddAttachment menolak karena isi yang sama tercatat duluan
+// (request paralel / retry) → kembalikan lampiran yang sudah ada. Object di
+// storage tidak dihapus: key-nya dari hash isi, dipakai lampiran tersebut.
+func (s *AchievementService) alreadyAttached(c *fiber.Ctx, id primitive.ObjectID, att model.Attachment) error {
+	if a, err := s.achievementRepo.GetByID(c.Context(), id); err == nil {
+		for _, existing := range a.Attachments {
+			if existing.Hash == att.Hash {
+				att = existing
+				break
+			}
+		}
+	}
+	return c.JSON(fiber.Map{
+		"message": "attachment already uploaded",
+		"data":    att,
+	})
+}
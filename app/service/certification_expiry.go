@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"strings"
+	"time"
+
+	"uas-backend/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// tipe prestasi yang punya masa berlaku
+const certificationType = "certification"
+
+// details sertifikasi lama yang tidak ikut ke perpanjangan
+var renewalResetFields = []string{"issuedDate", "validUntil", "certificationNumber"}
+
+// RenewAchievementRequest: semua field opsional, menimpa data sertifikasi lama.
+// details digabung ke details lama (issuedDate wajib diisi ulang).
+type RenewAchievementRequest struct {
+	Title       *string        `json:"title"`
+	Description *string        `json:"description"`
+	Details     map[string]any `json:"details"`
+	Tags        []string       `json:"tags"`
+}
+
+// syncValidity: isi ValidFrom / ValidUntil dari details sertifikasi.
+// Dipanggil setelah validasi skema (alias sudah jadi nama baku).
+func syncValidity(a *model.Achievement) []model.FieldError {
+	a.ValidFrom, a.ValidUntil = nil, nil
+	if a.AchievementType != certificationType {
+		return nil
+	}
+
+	a.ValidFrom = detailDate(a.Details, "issuedDate")
+	a.ValidUntil = detailDate(a.Details, "validUntil")
+
+	if a.ValidFrom != nil && a.ValidUntil != nil && a.ValidUntil.Before(*a.ValidFrom) {
+		return []model.FieldError{{Field: "details.validUntil", Message: "must not be before issuedDate"}}
+	}
+	return nil
+}
+
+func detailDate(details map[string]any, key string) *time.Time {
+	t, err := time.Parse("2006-01-02", detailString(details, key))
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// RenewAchievement godoc
+// @Summary Perpanjang sertifikasi
+// @Description
+// Buat prestasi baru (draft) dari sertifikasi terverifikasi; data lama disalin
+// kecuali issuedDate, validUntil & certificationNumber. Prestasi baru menyimpan
+// renews_id, prestasi lama renewed_by_id. Hanya pemilik / admin, sekali per sertifikasi;
+// kalau draft perpanjangan dihapus, sertifikasi lama bisa diperpanjang lagi.
+// @Tags Achievements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Achievement ID (sertifikasi lama)"
+// @Param body body service.RenewAchievementRequest true "Data sertifikasi baru"
+// @Success 201 {object} map[string]interface{} "Achievement created"
+// @Failure 400 {object} map[string]interface{} "Invalid request / details validation failed"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 409 {object} map[string]interface{} "Not a verified certification / already renewed"
+// @Router /achievements/{id}/renew [post]
+func (s *AchievementService) RenewAchievement(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+	achievementID := c.Params("id")
+
+	var body RenewAchievementRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&body); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	// 1️⃣ sertifikasi lama (MongoDB)
+	old, err := s.getAchievement(c, achievementID)
+	if err != nil {
+		return err
+	}
+
+	// 2️⃣ hanya pemilik / admin
+	switch claims.Role {
+	case "Mahasiswa":
+		if claims.StudentID == "" || claims.StudentID != old.StudentID {
+			return fiber.NewError(fiber.StatusForbidden, "access denied")
+		}
+	case "Admin":
+	default:
+		return fiber.NewError(fiber.StatusForbidden, "access denied")
+	}
+
+	// 3️⃣ harus sertifikasi terverifikasi yang belum diperpanjang
+	if old.AchievementType != certificationType {
+		return fiber.NewError(fiber.StatusConflict, "only certifications can be renewed")
+	}
+	if old.RenewedByID != "" {
+		return fiber.NewError(fiber.StatusConflict, "certification already renewed by "+old.RenewedByID)
+	}
+	ref, err := s.referenceRepo.GetByAchievementID(c.Context(), achievementID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "achievement reference not found")
+	}
+	if ref.Status != model.StatusVerified {
+		return fiber.NewError(fiber.StatusConflict, "only verified certifications can be renewed")
+	}
+
+	// 4️⃣ salin data lama, lalu timpa dengan body
+	details := maps.Clone(old.Details)
+	if details == nil {
+		details = map[string]any{}
+	}
+	for _, k := range renewalResetFields {
+		delete(details, k)
+	}
+	maps.Copy(details, body.Details)
+
+	req := CreateAchievementRequest{
+		AchievementType: old.AchievementType,
+		Title:           old.Title,
+		Description:     old.Description,
+		Details:         details,
+		Tags:            old.Tags,
+		Members:         old.Members,
+	}
+	if body.Title != nil && strings.TrimSpace(*body.Title) != "" {
+		req.Title = *body.Title
+	}
+	if body.Description != nil {
+		req.Description = *body.Description
+	}
+	if body.Tags != nil {
+		req.Tags = body.Tags
+	}
+
+	return s.createAchievement(c, old.StudentID, req, old)
+}
+
+/* =======================
+   PENGINGAT KEDALUWARSA
+======================= */
+
+// RunExpiryReminders: kirim pengingat untuk sertifikasi terverifikasi yang
+// kedaluwarsa dalam rentang window. Tiap validUntil hanya diingatkan sekali;
+// sertifikasi yang sudah diperpanjang dilewati. Hasil = jumlah pengingat.
+func (s *AchievementService) RunExpiryReminders(ctx context.Context, now time.Time, window time.Duration) (int, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	achievements, err := s.achievementRepo.FindExpiring(ctx, today, now.Add(window))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range achievements {
+		a := &achievements[i]
+		if a.ValidUntil == nil || a.RenewedByID != "" ||
+			(a.RemindedFor != nil && a.RemindedFor.Equal(*a.ValidUntil)) {
+			continue
+		}
+
+		// 1️⃣ hanya yang sudah terverifikasi (PostgreSQL)
+		ref, err := s.referenceRepo.GetByAchievementID(ctx, a.ID.Hex())
+		if err != nil || ref.Status != model.StatusVerified {
+			continue
+		}
+
+		// 2️⃣ tandai dulu supaya tidak dobel kalau job jalan paralel
+		marked, err := s.achievementRepo.MarkExpiryReminded(ctx, a.ID, *a.ValidUntil)
+		if err != nil {
+			return sent, fmt.Errorf("mark reminder %s: %w", a.ID.Hex(), err)
+		}
+		if !marked {
+			continue
+		}
+
+		// 3️⃣ pemilik + anggota tim
+		days := int(a.ValidUntil.Sub(today).Hours() / 24)
+		for _, studentID := range achievementStudentIDs(a) {
+			s.notifyStudent(ctx, studentID, model.Notification{
+				Type:  model.NotificationCertExpiring,
+				Title: "Sertifikasi segera kedaluwarsa",
+				Message: fmt.Sprintf("Sertifikasi \"%s\" berlaku sampai %s (%d hari lagi). Ajukan perpanjangan bila sudah diperbarui.",
+					a.Title, a.ValidUntil.Format("2006-01-02"), days),
+				AchievementID: a.ID.Hex(),
+			})
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// StartExpiryReminderJob: jalankan RunExpiryReminders berkala sampai ctx selesai
+func (s *AchievementService) StartExpiryReminderJob(ctx context.Context, interval, window time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := s.RunExpiryReminders(ctx, time.Now(), window)
+		if err != nil {
+			log.Printf("⚠️ Certification expiry reminders failed: %v", err)
+		} else if sent > 0 {
+			log.Printf("⏰ Certification expiry reminders: %d sent", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/app/repository"
+)
+
+// ConsistencyService: deteksi & perbaikan data prestasi yang tidak sinkron
+// antara MongoDB dan PostgreSQL. PostgreSQL jadi sumber kebenaran untuk status
+// (workflow) dan anggota tim (dipakai otorisasi & filter); dokumen MongoDB hanya
+// menyimpan salinan: status cukup dibandingkan terhapus / tidak, karena status
+// lain tidak ikut ditulis ke dokumen. Dokumen tanpa reference tidak dibuang:
+// reference-nya dibuat ulang (anggota dari dokumen) dan dokumennya masuk tempat sampah.
+type ConsistencyService struct {
+	achievementRepo repository.AchievementRepository
+	referenceRepo   repository.AchievementReferenceRepository
+}
+
+func NewConsistencyService(
+	achievementRepo repository.AchievementRepository,
+	referenceRepo repository.AchievementReferenceRepository,
+) *ConsistencyService {
+	return &ConsistencyService{
+		achievementRepo: achievementRepo,
+		referenceRepo:   referenceRepo,
+	}
+}
+
+// Reconcile: bandingkan kedua store. repair=false hanya melaporkan.
+// Data yang berubah dalam rentang grace dilewati karena mungkin
+// masih di tengah request (create / delete belum selesai).
+func (s *ConsistencyService) Reconcile(
+	ctx context.Context,
+	repair bool,
+	grace time.Duration,
+) (model.ConsistencyReport, error) {
+
+	report := model.ConsistencyReport{Issues: []model.ConsistencyIssue{}}
+	cutoff := time.Now().Add(-grace)
+
+	// 1️⃣ ambil kedua sisi
+	docs, err := s.achievementRepo.FindSyncStates(ctx)
+	if err != nil {
+		return report, err
+	}
+	refs, err := s.referenceRepo.GetAll(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Documents = len(docs)
+	report.References = len(refs)
+
+	members, err := s.referenceRepo.GetAllMembers(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	byID := make(map[string]*model.AchievementReference, len(refs))
+	for _, ref := range refs {
+		byID[ref.MongoAchievementID] = ref
+	}
+
+	// 2️⃣ dokumen MongoDB → reference
+	seen := make(map[string]bool, len(docs))
+	for i := range docs {
+		doc := &docs[i]
+		id := doc.ID.Hex()
+		seen[id] = true
+		deleted := doc.IsDeleted
+
+		ref, ok := byID[id]
+		switch {
+		case !ok:
+			if doc.CreatedAt.After(cutoff) {
+				report.Skipped++
+				continue
+			}
+			issue := model.ConsistencyIssue{Kind: model.IssueOrphanDocument, AchievementID: id, DocumentDeleted: &deleted}
+			if repair {
+				s.fix(&issue, s.adoptOrphan(ctx, doc))
+			}
+			report.Issues = append(report.Issues, issue)
+
+		case (ref.Status == model.StatusDeleted) != doc.IsDeleted:
+			if ref.UpdatedAt.After(cutoff) {
+				report.Skipped++
+				continue
+			}
+			issue := model.ConsistencyIssue{
+				Kind:            model.IssueStatusMismatch,
+				AchievementID:   id,
+				ReferenceStatus: ref.Status,
+				DocumentDeleted: &deleted,
+			}
+			if repair {
+				s.fix(&issue, s.achievementRepo.SyncStatus(ctx, doc.ID, ref.Status))
+			}
+			report.Issues = append(report.Issues, issue)
+
+		case !sameMembers(doc.Members, members[id]):
+			if doc.UpdatedAt.After(cutoff) {
+				report.Skipped++
+				continue
+			}
+			issue := model.ConsistencyIssue{Kind: model.IssueMemberMismatch, AchievementID: id}
+			if repair {
+				s.fix(&issue, s.achievementRepo.SyncMembers(ctx, doc.ID, members[id]))
+			}
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	// 3️⃣ reference tanpa dokumen
+	for _, ref := range refs {
+		if seen[ref.MongoAchievementID] {
+			continue
+		}
+		if ref.CreatedAt.After(cutoff) {
+			report.Skipped++
+			continue
+		}
+		issue := model.ConsistencyIssue{
+			Kind:            model.IssueOrphanReference,
+			AchievementID:   ref.MongoAchievementID,
+			ReferenceStatus: ref.Status,
+		}
+		if repair {
+			s.fix(&issue, s.referenceRepo.Discard(ctx, ref.MongoAchievementID))
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	return report, nil
+}
+
+// adoptOrphan: reference dibuat ulang berstatus deleted (beserta anggota tim)
+// dan dokumen dipindah ke tempat sampah. Pemilik masih bisa memulihkannya;
+// kalau tidak, purge menghapusnya bersama file lampiran.
+func (s *ConsistencyService) adoptOrphan(ctx context.Context, doc *model.Achievement) error {
+	if err := s.referenceRepo.RecreateDeleted(
+		ctx,
+		doc.StudentID,
+		doc.ID.Hex(),
+		"reconcile: reference was missing, moved to trash",
+	); err != nil {
+		return err
+	}
+	if len(doc.Members) > 0 {
+		if err := s.referenceRepo.SetMembers(ctx, doc.ID.Hex(), doc.Members); err != nil {
+			return err
+		}
+	}
+	if doc.IsDeleted {
+		return nil
+	}
+	return s.achievementRepo.SoftDelete(ctx, doc.ID)
+}
+
+// sameMembers: anggota (student_id + peran) sama, urutan diabaikan
+func sameMembers(doc, ref []model.AchievementMember) bool {
+	if len(doc) != len(ref) {
+		return false
+	}
+	roles := make(map[string]string, len(ref))
+	for _, m := range ref {
+		roles[m.StudentID] = m.Role
+	}
+	for _, m := range doc {
+		if role, ok := roles[m.StudentID]; !ok || role != m.Role {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *ConsistencyService) fix(issue *model.ConsistencyIssue, err error) {
+	if err != nil {
+		issue.Error = err.Error()
+		return
+	}
+	issue.Repaired = true
+}
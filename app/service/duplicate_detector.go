@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"uas-backend/app/model"
+	"uas-backend/app/repository"
+)
+
+const (
+	// skor minimal supaya masuk daftar peringatan
+	duplicateThreshold = 0.5
+	// jumlah kandidat maksimal yang dikembalikan
+	maxDuplicateCandidates = 10
+)
+
+// key details yang berisi nama kegiatan / tanggal kegiatan (semua tipe)
+var (
+	duplicateEventKeys = []string{"competitionName", "activityName", "organizationName", "certificationName", "publicationTitle"}
+	duplicateDateKeys  = []string{"eventDate", "date", "issuedDate", "publishedDate", "periodStart"}
+)
+
+// DuplicateDetector: cari prestasi lain yang kemungkinan sama
+// (milik sendiri, anggota tim, atau teman satu dosen wali, plus
+// prestasi siapa pun dengan file lampiran identik)
+type DuplicateDetector struct {
+	achievementRepo repository.AchievementRepository
+	studentRepo     repository.StudentRepository
+}
+
+func NewDuplicateDetector(
+	achievementRepo repository.AchievementRepository,
+	studentRepo repository.StudentRepository,
+) *DuplicateDetector {
+	return &DuplicateDetector{
+		achievementRepo: achievementRepo,
+		studentRepo:     studentRepo,
+	}
+}
+
+// Find: kandidat duplikat untuk a, skor tertinggi dulu
+func (d *DuplicateDetector) Find(ctx context.Context, a *model.Achievement) ([]model.DuplicateCandidate, error) {
+	// 1️⃣ prestasi pemilik, anggota tim, dan teman satu dosen wali
+	peers := achievementStudentIDs(a)
+	if st, err := d.studentRepo.GetStudentByID(ctx, a.StudentID); err == nil && st != nil && st.AdvisorID != "" {
+		classmates, err := d.studentRepo.GetStudentsByAdvisor(ctx, st.AdvisorID)
+		if err != nil {
+			return nil, fmt.Errorf("duplicates: fetch peers: %w", err)
+		}
+		for _, c := range classmates {
+			if !slices.Contains(peers, c.ID) {
+				peers = append(peers, c.ID)
+			}
+		}
+	}
+
+	pool, err := d.achievementRepo.FindByStudentIDs(ctx, peers)
+	if err != nil {
+		return nil, fmt.Errorf("duplicates: fetch peer achievements: %w", err)
+	}
+
+	// 2️⃣ lampiran identik dari mahasiswa mana pun
+	var hashes []string
+	for _, att := range a.Attachments {
+		if att.Hash != "" {
+			hashes = append(hashes, att.Hash)
+		}
+	}
+	sameFile, err := d.achievementRepo.FindByAttachmentHashes(ctx, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("duplicates: fetch by attachment: %w", err)
+	}
+	pool = append(pool, sameFile...)
+
+	// 3️⃣ skor tiap kandidat
+	seen := map[string]bool{a.ID.Hex(): true}
+	result := []model.DuplicateCandidate{}
+	for i := range pool {
+		other := &pool[i]
+		if seen[other.ID.Hex()] {
+			continue
+		}
+		seen[other.ID.Hex()] = true
+
+		score, reasons := DuplicateScore(a, other)
+		if score < duplicateThreshold {
+			continue
+		}
+		result = append(result, model.DuplicateCandidate{
+			AchievementID: other.ID.Hex(),
+			StudentID:     other.StudentID,
+			Title:         other.Title,
+			Score:         score,
+			Reasons:       reasons,
+			StudentIDs:    achievementStudentIDs(other),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	if len(result) > maxDuplicateCandidates {
+		result = result[:maxDuplicateCandidates]
+	}
+
+	return result, nil
+}
+
+// DuplicateScore: kemiripan dua prestasi (0..1) beserta alasannya.
+// File / nomor sertifikat identik langsung dianggap duplikat.
+func DuplicateScore(a, b *model.Achievement) (float64, []string) {
+	var reasons []string
+	score := 0.0
+
+	for _, x := range a.Attachments {
+		if x.Hash != "" && slices.ContainsFunc(b.Attachments, func(y model.Attachment) bool { return y.Hash == x.Hash }) {
+			score += 1
+			reasons = append(reasons, model.DuplicateAttachment)
+			break
+		}
+	}
+
+	if n := normalizeText(detailString(a.Details, "certificationNumber")); n != "" &&
+		n == normalizeText(detailString(b.Details, "certificationNumber")) {
+		score += 1
+		reasons = append(reasons, model.DuplicateCertNumber)
+	}
+
+	ta, tb := normalizeText(a.Title), normalizeText(b.Title)
+	switch {
+	case ta != "" && ta == tb:
+		score += 0.5
+		reasons = append(reasons, model.DuplicateTitle)
+	case tokenSimilarity(ta, tb) >= 0.7:
+		score += 0.35
+		reasons = append(reasons, model.DuplicateSimilarName)
+	}
+
+	if ea := normalizeText(firstDetail(a.Details, duplicateEventKeys)); ea != "" &&
+		ea == normalizeText(firstDetail(b.Details, duplicateEventKeys)) {
+		score += 0.3
+		reasons = append(reasons, model.DuplicateEvent)
+	}
+
+	if da := firstDetail(a.Details, duplicateDateKeys); da != "" && da == firstDetail(b.Details, duplicateDateKeys) {
+		score += 0.2
+		reasons = append(reasons, model.DuplicateEventDate)
+	}
+
+	return min(score, 1), reasons
+}
+
+// normalizeText: huruf kecil, tanda baca jadi spasi, spasi dirapikan
+func normalizeText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// tokenSimilarity: Jaccard kata (teks sudah dinormalisasi)
+func tokenSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+
+	set := map[string]int{}
+	for _, w := range strings.Fields(a) {
+		set[w] |= 1
+	}
+	for _, w := range strings.Fields(b) {
+		set[w] |= 2
+	}
+
+	both := 0
+	for _, v := range set {
+		if v == 3 {
+			both++
+		}
+	}
+	return float64(both) / float64(len(set))
+}
+
+func detailString(details map[string]any, key string) string {
+	if v, ok := details[key].(string); ok {
+		return strings.TrimSpace(v)
+	}
+	return ""
+}
+
+// firstDetail: nilai string pertama yang terisi dari daftar key
+func firstDetail(details map[string]any, keys []string) string {
+	for _, k := range keys {
+		if v := detailString(details, k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
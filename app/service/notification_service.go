@@ -0,0 +1,61 @@
+package service
+
+import (
+	"uas-backend/app/model"
+	"uas-backend/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository) *NotificationService {
+	return &NotificationService{notificationRepo: notificationRepo}
+}
+
+// GetNotifications godoc
+// @Summary Ambil notifikasi user login
+// @Tags Notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{} "List notifications"
+// @Failure 500 {object} map[string]interface{} "Failed to fetch notifications"
+// @Router /notifications [get]
+func (s *NotificationService) GetNotifications(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	data, err := s.notificationRepo.FindByUserID(c.Context(), claims.UserID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch notifications")
+	}
+
+	return c.JSON(fiber.Map{"data": data})
+}
+
+// MarkAsRead godoc
+// @Summary Tandai notifikasi sudah dibaca
+// @Tags Notifications
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} map[string]interface{} "Notification marked as read"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Notification not found"
+// @Router /notifications/{id}/read [put]
+func (s *NotificationService) MarkAsRead(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+
+	objID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid notification id")
+	}
+
+	if err := s.notificationRepo.MarkRead(c.Context(), objID, claims.UserID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "notification not found")
+	}
+
+	return c.JSON(fiber.Map{"message": "notification marked as read"})
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"uas-backend/app/model"
+)
+
+// PointsRules: tabel aturan poin prestasi. Version berubah setiap
+// isi tabel berubah, dipakai untuk menghitung ulang prestasi lama.
+type PointsRules struct {
+	rules   []model.PointRule
+	version string
+}
+
+// DefaultPointRules dipakai kalau tidak ada file konfigurasi
+func DefaultPointRules() []model.PointRule {
+	var rules []model.PointRule
+
+	// kompetisi: tingkat × peringkat, lalu partisipasi per tingkat
+	levels := []struct {
+		level string
+		ranks [3]int
+		base  int
+	}{
+		{"international", [3]int{100, 90, 80}, 60},
+		{"national", [3]int{70, 60, 50}, 40},
+		{"regional", [3]int{40, 35, 30}, 20},
+		{"local", [3]int{20, 15, 10}, 5},
+	}
+	for _, l := range levels {
+		for i, pts := range l.ranks {
+			rules = append(rules, model.PointRule{
+				AchievementType: "competition",
+				When:            map[string]string{"competitionLevel": l.level, "rank": fmt.Sprint(i + 1)},
+				Points:          pts,
+				Description:     fmt.Sprintf("Juara %d tingkat %s", i+1, l.level),
+			})
+		}
+		rules = append(rules, model.PointRule{
+			AchievementType: "competition",
+			When:            map[string]string{"competitionLevel": l.level},
+			Points:          l.base,
+			Description:     "Peserta / finalis tingkat " + l.level,
+		})
+	}
+
+	return append(rules,
+		// publikasi: indeksasi dulu, baru jenis publikasi
+		model.PointRule{AchievementType: "publication", When: map[string]string{"indexing": "scopus"}, Points: 80, Description: "Publikasi terindeks Scopus"},
+		model.PointRule{AchievementType: "publication", When: map[string]string{"indexing": "wos"}, Points: 80, Description: "Publikasi terindeks Web of Science"},
+		model.PointRule{AchievementType: "publication", When: map[string]string{"indexing": "sinta"}, Points: 50, Description: "Publikasi terindeks SINTA"},
+		model.PointRule{AchievementType: "publication", When: map[string]string{"publicationType": "book"}, Points: 60, Description: "Buku"},
+		model.PointRule{AchievementType: "publication", When: map[string]string{"publicationType": "conference"}, Points: 40, Description: "Prosiding konferensi"},
+		model.PointRule{AchievementType: "publication", Points: 20, Description: "Publikasi lain"},
+
+		// organisasi: berdasarkan jabatan
+		model.PointRule{AchievementType: "organization", When: map[string]string{"position": "ketua"}, Points: 40, Description: "Ketua organisasi"},
+		model.PointRule{AchievementType: "organization", When: map[string]string{"position": "wakil ketua"}, Points: 30, Description: "Wakil ketua organisasi"},
+		model.PointRule{AchievementType: "organization", When: map[string]string{"position": "sekretaris"}, Points: 25, Description: "Sekretaris organisasi"},
+		model.PointRule{AchievementType: "organization", When: map[string]string{"position": "bendahara"}, Points: 25, Description: "Bendahara organisasi"},
+		model.PointRule{AchievementType: "organization", Points: 15, Description: "Pengurus / anggota organisasi"},
+
+		model.PointRule{AchievementType: "certification", Points: 30, Description: "Sertifikasi"},
+		model.PointRule{AchievementType: "academic", Points: 20, Description: "Prestasi akademik"},
+	)
+}
+
+func NewPointsRules(rules []model.PointRule) (*PointsRules, error) {
+	if len(rules) == 0 {
+		return nil, errors.New("points rules: empty rule table")
+	}
+
+	for i, r := range rules {
+		if r.AchievementType == "" {
+			return nil, fmt.Errorf("points rules: rule %d has no achievement type", i)
+		}
+		if r.Points < 0 {
+			return nil, fmt.Errorf("points rules: rule %d has negative points", i)
+		}
+	}
+
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("points rules: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+
+	return &PointsRules{
+		rules:   rules,
+		version: hex.EncodeToString(sum[:])[:12],
+	}, nil
+}
+
+// LoadPointsRules membaca tabel poin dari file JSON.
+// path kosong → pakai DefaultPointRules.
+func LoadPointsRules(path string) (*PointsRules, error) {
+	if path == "" {
+		return NewPointsRules(DefaultPointRules())
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("points rules: read %s: %w", path, err)
+	}
+
+	var rules []model.PointRule
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil, fmt.Errorf("points rules: parse %s: %w", path, err)
+	}
+
+	return NewPointsRules(rules)
+}
+
+func (p *PointsRules) Rules() []model.PointRule {
+	return p.rules
+}
+
+func (p *PointsRules) Version() string {
+	return p.version
+}
+
+// Calculate: poin dari rule pertama yang cocok (0 kalau tidak ada)
+func (p *PointsRules) Calculate(achievement *model.Achievement) (int, *model.PointRule) {
+	for i, rule := range p.rules {
+		if rule.AchievementType != achievement.AchievementType {
+			continue
+		}
+
+		matched := true
+		for key, want := range rule.When {
+			got, ok := achievement.Details[key]
+			if !ok || !strings.EqualFold(fmt.Sprint(got), want) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return rule.Points, &p.rules[i]
+		}
+	}
+
+	return 0, nil
+}
+
+// Apply: hitung ulang poin ke dokumen. Override verifikator tetap dipakai,
+// hanya CalculatedPoints-nya yang diperbarui.
+func (p *PointsRules) Apply(achievement *model.Achievement) {
+	points, _ := p.Calculate(achievement)
+
+	achievement.PointsVersion = p.version
+	if achievement.PointsOverride != nil {
+		achievement.PointsOverride.CalculatedPoints = points
+		achievement.Points = achievement.PointsOverride.Points
+		return
+	}
+	achievement.Points = points
+}
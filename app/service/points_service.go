@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+
+	"uas-backend/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PointsService struct {
+	rules           *PointsRules
+	reportRepo      repository.ReportRepository
+	achievementRepo repository.AchievementRepository
+}
+
+func NewPointsService(
+	rules *PointsRules,
+	reportRepo repository.ReportRepository,
+	achievementRepo repository.AchievementRepository,
+) *PointsService {
+	return &PointsService{
+		rules:           rules,
+		reportRepo:      reportRepo,
+		achievementRepo: achievementRepo,
+	}
+}
+
+// RecalculateVerified: hitung ulang poin prestasi verified yang masih
+// memakai versi tabel lama. Mengembalikan jumlah dokumen yang diubah.
+func (s *PointsService) RecalculateVerified(ctx context.Context) (int, error) {
+	// 1️⃣ ambil ID verified (PostgreSQL)
+	ids, err := s.reportRepo.GetVerifiedAchievementIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var objIDs []primitive.ObjectID
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			objIDs = append(objIDs, oid)
+		}
+	}
+	if len(objIDs) == 0 {
+		return 0, nil
+	}
+
+	// 2️⃣ ambil dokumen (MongoDB)
+	achievements, err := s.achievementRepo.FindByIDs(ctx, objIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	// 3️⃣ hitung ulang yang versinya berbeda
+	updated := 0
+	for i := range achievements {
+		a := &achievements[i]
+		if a.PointsVersion == s.rules.Version() {
+			continue
+		}
+
+		// dokumen yang berubah sejak dibaca dilewati (versinya sudah bukan from)
+		from := a.PointsVersion
+		s.rules.Apply(a)
+		ok, err := s.achievementRepo.SetRecalculatedPoints(ctx, a, from)
+		if err != nil {
+			return updated, err
+		}
+		if ok {
+			updated++
+		}
+	}
+
+	return updated, nil
+}
+
+// GetPointRules godoc
+// @Summary Ambil tabel aturan poin prestasi
+// @Tags Points
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Point rules + version"
+// @Router /points/rules [get]
+func (s *PointsService) GetPointRules(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"version": s.rules.Version(),
+		"data":    s.rules.Rules(),
+	})
+}
+
+// RecalculatePoints godoc
+// @Summary Hitung ulang poin prestasi verified
+// @Description Dijalankan otomatis saat aplikasi start; endpoint ini untuk memicu manual.
+// @Tags Points
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Recalculated"
+// @Failure 500 {object} map[string]interface{} "Failed to recalculate points"
+// @Router /points/recalculate [post]
+func (s *PointsService) RecalculatePoints(c *fiber.Ctx) error {
+	updated, err := s.RecalculateVerified(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to recalculate points")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "points recalculated",
+		"version": s.rules.Version(),
+		"updated": updated,
+	})
+}
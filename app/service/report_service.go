@@ -1,8 +1,11 @@
 package service
 
 import (
+	"time"
+
 	"uas-backend/app/model"
 	"uas-backend/app/repository"
+	"uas-backend/config"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -42,6 +45,7 @@ func NewReportService(
 // @Tags Reports
 // @Security BearerAuth
 // @Produce json
+// @Param include_expired query bool false "Ikut hitung sertifikasi yang sudah kedaluwarsa"
 // @Success 200 {object} model.AchievementStatisticsResponse
 // @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -90,6 +94,7 @@ func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
 			"failed to fetch achievements",
 		)
 	}
+	achievements, expired := excludeExpired(c, achievements)
 
 	// 4️⃣ Hitung statistik
 	typeCount := map[string]int{}
@@ -110,8 +115,10 @@ func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
 		period := a.CreatedAt.Format("2006-01")
 		periodCount[period]++
 
-		// STUDENT
-		studentCount[a.StudentID]++
+		// STUDENT: prestasi tim dihitung sekali untuk tiap anggota
+		for _, id := range achievementStudentIDs(&a) {
+			studentCount[id]++
+		}
 
 		// COMPETITION LEVEL (khusus competition)
 		if lvl, ok := a.Details["competitionLevel"].(string); ok {
@@ -158,6 +165,7 @@ func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
 		TotalPerPeriod:    periodStats,
 		CompetitionLevels: levelStats,
 		TotalPerStudent:   studentStats,
+		ExpiredExcluded:   expired,
 	})
 }
 
@@ -172,6 +180,7 @@ func (s *ReportService) GetStatistics(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Produce json
 // @Param id path string true "Student ID"
+// @Param include_expired query bool false "Ikut hitung sertifikasi yang sudah kedaluwarsa"
 // @Success 200 {object} model.AchievementStatisticsResponse
 // @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -221,6 +230,7 @@ func (s *ReportService) GetStudentStatistics(c *fiber.Ctx) error {
 			"failed to fetch achievements",
 		)
 	}
+	achievements, expired := excludeExpired(c, achievements)
 
 	// 4️⃣ Hitung statistik
 	typeCount := map[string]int{}
@@ -276,5 +286,81 @@ func (s *ReportService) GetStudentStatistics(c *fiber.Ctx) error {
 		TotalPerType:      typeStats,
 		TotalPerPeriod:    periodStats,
 		CompetitionLevels: levelStats,
+		ExpiredExcluded:   expired,
+	})
+}
+
+// excludeExpired: buang sertifikasi kedaluwarsa kecuali ?include_expired=true
+func excludeExpired(c *fiber.Ctx, achievements []model.Achievement) ([]model.Achievement, int) {
+	if c.QueryBool("include_expired") {
+		return achievements, 0
+	}
+
+	now := time.Now()
+	kept := achievements[:0]
+	for _, a := range achievements {
+		if !a.IsExpired(now) {
+			kept = append(kept, a)
+		}
+	}
+	return kept, len(achievements) - len(kept)
+}
+
+// batas baris laporan penyimpanan
+const (
+	defaultStorageReportLimit = 10
+	maxStorageReportLimit     = 100
+)
+
+// GetStorageReport godoc
+// @Summary Laporan penyimpanan lampiran
+// @Description
+//
+//	Admin only. Total byte lampiran dan pemakai terbesar (mahasiswa & prestasi),
+//	beserta kuota masing-masing. Prestasi di tempat sampah ikut dihitung sampai dipurge.
+//
+// @Tags Reports
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Jumlah baris per daftar (default 10, maks 100)"
+// @Success 200 {object} model.StorageReport
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /reports/storage [get]
+func (s *ReportService) GetStorageReport(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", defaultStorageReportLimit)
+	if limit <= 0 || limit > maxStorageReportLimit {
+		limit = defaultStorageReportLimit
+	}
+
+	// 1️⃣ total seluruh lampiran
+	total, err := s.achievementRepo.StorageTotal(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to load storage usage")
+	}
+
+	// 2️⃣ pemakai terbesar
+	students, err := s.achievementRepo.StorageByStudent(c.Context(), nil, int64(limit))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to load storage usage")
+	}
+	achievements, err := s.achievementRepo.StorageByAchievement(c.Context(), int64(limit))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to load storage usage")
+	}
+
+	// 3️⃣ kuota untuk dibandingkan
+	for i := range students {
+		students[i].QuotaBytes = config.AttachmentStudentQuota()
+	}
+	for i := range achievements {
+		achievements[i].QuotaBytes = config.AttachmentAchievementQuota()
+	}
+
+	return c.JSON(model.StorageReport{
+		TotalBytes:       total.UsedBytes,
+		TotalAttachments: total.Attachments,
+		TopStudents:      students,
+		TopAchievements:  achievements,
 	})
 }
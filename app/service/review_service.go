@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewService: antrian review per reviewer + job SLA (pengingat & eskalasi)
+type ReviewService struct {
+	referenceRepo    repository.AchievementReferenceRepository
+	achievementRepo  repository.AchievementRepository
+	studentRepo      repository.StudentRepository
+	lecturerRepo     repository.LecturerRepository
+	userRepo         repository.UserRepository
+	notificationRepo repository.NotificationRepository
+	workflow         *AchievementWorkflow
+	approvals        *ApprovalChains
+	slas             *ReviewSLAs
+}
+
+func NewReviewService(
+	referenceRepo repository.AchievementReferenceRepository,
+	achievementRepo repository.AchievementRepository,
+	studentRepo repository.StudentRepository,
+	lecturerRepo repository.LecturerRepository,
+	userRepo repository.UserRepository,
+	notificationRepo repository.NotificationRepository,
+	workflow *AchievementWorkflow,
+	approvals *ApprovalChains,
+	slas *ReviewSLAs,
+) *ReviewService {
+	return &ReviewService{
+		referenceRepo:    referenceRepo,
+		achievementRepo:  achievementRepo,
+		studentRepo:      studentRepo,
+		lecturerRepo:     lecturerRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		workflow:         workflow,
+		approvals:        approvals,
+		slas:             slas,
+	}
+}
+
+// pendingReviews: reference yang menunggu verifikasi + dokumen MongoDB-nya,
+// urutan tetap dari yang paling lama disubmit
+func (s *ReviewService) pendingReviews(
+	ctx context.Context,
+) ([]*model.AchievementReference, map[string]model.Achievement, error) {
+
+	t, err := s.workflow.Lookup(model.ActionVerify)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refs, err := s.referenceRepo.GetPending(ctx, t.From)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var objectIDs []primitive.ObjectID
+	for _, r := range refs {
+		if oid, err := primitive.ObjectIDFromHex(r.MongoAchievementID); err == nil {
+			objectIDs = append(objectIDs, oid)
+		}
+	}
+
+	byID := map[string]model.Achievement{}
+	if len(objectIDs) == 0 {
+		return refs, byID, nil
+	}
+
+	achievements, err := s.achievementRepo.FindByIDs(ctx, objectIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, a := range achievements {
+		byID[a.ID.Hex()] = a
+	}
+
+	return refs, byID, nil
+}
+
+// GetPendingReviews godoc
+// @Summary Antrian review milik reviewer login
+// @Description
+// Semua prestasi yang menunggu persetujuan user login di tahap mana pun
+// (atau satu tahap lewat ?level=), dari yang paling lama. Dosen Wali hanya melihat anak walinya;
+// prestasi yang tahap sebelumnya sudah disetujui user ini di ronde yang sama tidak ikut.
+// Tiap item berisi batas SLA (due_at / escalate_at) dan status overdue.
+// @Tags Reviews
+// @Security BearerAuth
+// @Produce json
+// @Param level query string false "Filter tahap verifikasi (mis. advisor, faculty)"
+// @Param type query string false "Filter tipe prestasi"
+// @Param student_id query string false "Filter mahasiswa"
+// @Success 200 {object} map[string]interface{} "List review queue items"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 500 {object} map[string]interface{} "Failed to fetch review queue"
+// @Router /reviews/pending [get]
+func (s *ReviewService) GetPendingReviews(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*model.JWTClaims)
+	levelFilter := c.Query("level")
+	typeFilter := c.Query("type")
+	studentFilter := c.Query("student_id")
+
+	// 1️⃣ tahap yang bisa di-review user ini (relasi kosong = akses penuh)
+	levels := map[string]string{}
+	needAdvisees := false
+	for _, name := range s.approvals.LevelNames() {
+		if levelFilter != "" && name != levelFilter {
+			continue
+		}
+		for _, a := range s.approvals.LevelActors(name) {
+			if a.Role != claims.Role {
+				continue
+			}
+			if rel, ok := levels[name]; !ok || rel != "" {
+				levels[name] = a.Relation
+			}
+		}
+		if levels[name] == model.RelationAdvisor {
+			needAdvisees = true
+		}
+	}
+	if len(levels) == 0 {
+		return fiber.NewError(fiber.StatusForbidden, "access denied")
+	}
+
+	// 2️⃣ anak wali untuk tahap berelasi advisor
+	advisees := map[string]bool{}
+	if needAdvisees {
+		lecturer, err := s.lecturerRepo.GetLecturerProfile(c.Context(), claims.UserID)
+		if err != nil {
+			return fiber.NewError(fiber.StatusForbidden, "lecturer profile not found")
+		}
+		students, err := s.studentRepo.GetStudentsByAdvisor(c.Context(), lecturer.ID)
+		if err != nil {
+			return fiber.NewError(500, "failed to fetch advisees")
+		}
+		for _, st := range students {
+			advisees[st.ID] = true
+		}
+	}
+
+	// 3️⃣ antrian semua tahap
+	refs, byID, err := s.pendingReviews(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch review queue")
+	}
+
+	// 4️⃣ filter + hitung SLA
+	now := time.Now()
+	result := []model.ReviewQueueItem{}
+	for _, r := range refs {
+		if r.PendingLevel == nil || r.LevelStart() == nil {
+			continue
+		}
+		rel, ok := levels[*r.PendingLevel]
+		if !ok {
+			continue
+		}
+
+		a, ok := byID[r.MongoAchievementID]
+		if !ok || a.IsDeleted || (typeFilter != "" && a.AchievementType != typeFilter) {
+			continue
+		}
+
+		// prestasi tim: cukup salah satu anggota yang cocok
+		allowed := advisees
+		if rel != model.RelationAdvisor {
+			allowed = map[string]bool{claims.StudentID: true}
+		}
+		if !relationAllows(rel, &a, allowed) {
+			continue
+		}
+		if studentFilter != "" && !slices.Contains(achievementStudentIDs(&a), studentFilter) {
+			continue
+		}
+
+		// sudah menyetujui tahap sebelumnya di ronde ini → verify akan ditolak (409)
+		if r.ApprovalLevel > 0 {
+			approved, err := s.approvedThisRound(c.Context(), r, claims.UserID)
+			if err != nil {
+				return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch approvals")
+			}
+			if approved {
+				continue
+			}
+		}
+
+		dueAt, escalateAt := s.slas.Deadlines(a.AchievementType, *r.LevelStart())
+		result = append(result, model.ReviewQueueItem{
+			Achievement: a,
+			Reference:   r,
+			AgeHours:    int(now.Sub(*r.LevelStart()).Hours()),
+			DueAt:       dueAt,
+			EscalateAt:  escalateAt,
+			Overdue:     now.After(dueAt),
+			Escalated:   now.After(escalateAt),
+		})
+	}
+
+	return c.JSON(fiber.Map{"data": result})
+}
+
+// approvedThisRound: userID sudah menyetujui salah satu tahap ronde berjalan
+func (s *ReviewService) approvedThisRound(
+	ctx context.Context,
+	r *model.AchievementReference,
+	userID string,
+) (bool, error) {
+	approvals, err := s.referenceRepo.GetApprovals(ctx, r.MongoAchievementID)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range approvals {
+		if a.Round == r.ResubmissionCount && a.ApprovedBy == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+/* =======================
+   SLA JOB
+======================= */
+
+// RunSLACheck: tandai review yang lewat SLA, kirim pengingat ke reviewer
+// tahap berjalan, dan eskalasi ke admin setelah batas kedua.
+// Aman dijalankan berulang; penanda di DB mencegah notifikasi ganda.
+func (s *ReviewService) RunSLACheck(ctx context.Context, now time.Time) (model.ReviewSLAReport, error) {
+	var report model.ReviewSLAReport
+
+	refs, byID, err := s.pendingReviews(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	var admins []string
+	adminsLoaded := false
+	loadAdmins := func() []string {
+		if !adminsLoaded {
+			admins = s.adminUserIDs(ctx)
+			adminsLoaded = true
+		}
+		return admins
+	}
+
+	for _, r := range refs {
+		a, ok := byID[r.MongoAchievementID]
+		if !ok || r.LevelStart() == nil || r.PendingLevel == nil {
+			continue
+		}
+		report.Checked++
+
+		since := *r.LevelStart()
+		dueAt, escalateAt := s.slas.Deadlines(a.AchievementType, since)
+
+		// 1️⃣ lewat batas pertama → pengingat ke reviewer tahap ini
+		if now.After(dueAt) && r.OverdueAt == nil {
+			marked, err := s.referenceRepo.MarkOverdue(ctx, r.MongoAchievementID)
+			if err != nil {
+				return report, err
+			}
+			if marked {
+				report.Overdue++
+				s.notifyUsers(ctx, s.levelReviewers(ctx, *r.PendingLevel, achievementStudentIDs(&a), loadAdmins), model.Notification{
+					Type:          model.NotificationReviewReminder,
+					Title:         "Pengingat review prestasi",
+					Message:       fmt.Sprintf("\"%s\" menunggu review sejak %s", a.Title, since.Format("02 Jan 2006")),
+					AchievementID: r.MongoAchievementID,
+				})
+			}
+		}
+
+		// 2️⃣ lewat batas kedua → eskalasi ke admin
+		if now.After(escalateAt) && r.EscalatedAt == nil {
+			marked, err := s.referenceRepo.MarkEscalated(ctx, r.MongoAchievementID)
+			if err != nil {
+				return report, err
+			}
+			if marked {
+				report.Escalated++
+				s.notifyUsers(ctx, loadAdmins(), model.Notification{
+					Type:          model.NotificationReviewEscalated,
+					Title:         "Eskalasi review prestasi",
+					Message:       fmt.Sprintf("\"%s\" belum direview di tahap %s sejak %s", a.Title, *r.PendingLevel, since.Format("02 Jan 2006")),
+					AchievementID: r.MongoAchievementID,
+				})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// StartSLAJob: jalankan RunSLACheck berkala sampai ctx selesai
+func (s *ReviewService) StartSLAJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := s.RunSLACheck(ctx, time.Now())
+		if err != nil {
+			log.Printf("⚠️ Review SLA check failed: %v", err)
+		} else if report.Overdue > 0 || report.Escalated > 0 {
+			log.Printf("⏰ Review SLA: %d overdue, %d escalated", report.Overdue, report.Escalated)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// levelReviewers: tahap dengan actor advisor → dosen wali seluruh anggota
+// (prestasi tim bisa punya beberapa dosen wali); selain itu → semua admin
+func (s *ReviewService) levelReviewers(
+	ctx context.Context,
+	level string,
+	studentIDs []string,
+	admins func() []string,
+) []string {
+	for _, a := range s.approvals.LevelActors(level) {
+		if a.Relation != model.RelationAdvisor {
+			continue
+		}
+
+		var reviewers []string
+		for _, studentID := range studentIDs {
+			student, err := s.studentRepo.GetStudentByID(ctx, studentID)
+			if err != nil || student == nil || student.AdvisorID == "" {
+				continue
+			}
+			lecturer, err := s.lecturerRepo.GetLecturerByID(ctx, student.AdvisorID)
+			if err != nil || lecturer == nil || slices.Contains(reviewers, lecturer.UserID) {
+				continue
+			}
+			reviewers = append(reviewers, lecturer.UserID)
+		}
+		if len(reviewers) > 0 {
+			return reviewers
+		}
+		break // tanpa dosen wali → admin yang diingatkan
+	}
+
+	return admins()
+}
+
+func (s *ReviewService) adminUserIDs(ctx context.Context) []string {
+	users, err := s.userRepo.GetAllUsers(ctx)
+	if err != nil {
+		log.Printf("⚠️ Review SLA: failed to load admins: %v", err)
+		return nil
+	}
+
+	var ids []string
+	for _, u := range users {
+		if u.RoleName == "Admin" && u.IsActive {
+			ids = append(ids, u.ID)
+		}
+	}
+	return ids
+}
+
+func (s *ReviewService) notifyUsers(ctx context.Context, userIDs []string, n model.Notification) {
+	for _, id := range userIDs {
+		msg := n
+		msg.UserID = id
+		msg.CreatedAt = time.Now()
+		if err := s.notificationRepo.Create(ctx, &msg); err != nil {
+			log.Printf("⚠️ NOTIFY FAILED: %v", err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"uas-backend/app/model"
+)
+
+// ReviewSLAs: batas waktu review per tipe prestasi
+type ReviewSLAs struct {
+	byType   map[string]model.ReviewSLA
+	fallback model.ReviewSLA
+}
+
+// DefaultReviewSLAs dipakai kalau tidak ada file konfigurasi
+func DefaultReviewSLAs() []model.ReviewSLA {
+	return []model.ReviewSLA{
+		// kompetisi sering dipakai untuk beasiswa → lebih cepat
+		{AchievementType: "competition", DueHours: 48, EscalateHours: 120},
+		{DueHours: 72, EscalateHours: 168},
+	}
+}
+
+func NewReviewSLAs(slas []model.ReviewSLA) (*ReviewSLAs, error) {
+	r := &ReviewSLAs{byType: map[string]model.ReviewSLA{}}
+	hasFallback := false
+
+	for _, sla := range slas {
+		if sla.DueHours <= 0 || sla.EscalateHours <= sla.DueHours {
+			return nil, fmt.Errorf(
+				"review sla: %q needs 0 < dueHours < escalateHours", sla.AchievementType,
+			)
+		}
+
+		if sla.AchievementType == "" {
+			if hasFallback {
+				return nil, errors.New("review sla: duplicate default entry")
+			}
+			hasFallback = true
+			r.fallback = sla
+			continue
+		}
+
+		if _, dup := r.byType[sla.AchievementType]; dup {
+			return nil, fmt.Errorf("review sla: duplicate type %q", sla.AchievementType)
+		}
+		r.byType[sla.AchievementType] = sla
+	}
+
+	if !hasFallback {
+		return nil, errors.New("review sla: default entry (empty achievementType) is required")
+	}
+	return r, nil
+}
+
+// LoadReviewSLAs membaca SLA dari file JSON.
+// path kosong → pakai DefaultReviewSLAs.
+func LoadReviewSLAs(path string) (*ReviewSLAs, error) {
+	if path == "" {
+		return NewReviewSLAs(DefaultReviewSLAs())
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("review sla: read %s: %w", path, err)
+	}
+
+	var slas []model.ReviewSLA
+	if err := json.Unmarshal(raw, &slas); err != nil {
+		return nil, fmt.Errorf("review sla: parse %s: %w", path, err)
+	}
+
+	return NewReviewSLAs(slas)
+}
+
+func (r *ReviewSLAs) For(achievementType string) model.ReviewSLA {
+	if sla, ok := r.byType[achievementType]; ok {
+		return sla
+	}
+	return r.fallback
+}
+
+// Deadlines: batas pengingat & eskalasi dari awal tahap verifikasi yang ditunggu
+func (r *ReviewSLAs) Deadlines(achievementType string, levelStart time.Time) (time.Time, time.Time) {
+	sla := r.For(achievementType)
+	return levelStart.Add(time.Duration(sla.DueHours) * time.Hour),
+		levelStart.Add(time.Duration(sla.EscalateHours) * time.Hour)
+}
@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/crypto/bcrypt"
 
 	"uas-backend/app/model"
 	"uas-backend/app/repository"
+	"uas-backend/config"
 )
 
 type UserHttpHandler interface {
@@ -21,20 +23,23 @@ type UserHttpHandler interface {
 }
 
 type UserService struct {
-	repo         repository.UserRepository
-	studentRepo  repository.StudentRepository
-	lecturerRepo repository.LecturerRepository
+	repo            repository.UserRepository
+	studentRepo     repository.StudentRepository
+	lecturerRepo    repository.LecturerRepository
+	achievementRepo repository.AchievementRepository // pemakaian penyimpanan lampiran
 }
 
 func NewUserService(
 	repo repository.UserRepository,
 	studentRepo repository.StudentRepository,
 	lecturerRepo repository.LecturerRepository,
+	achievementRepo repository.AchievementRepository,
 ) UserHttpHandler {
 	return &UserService{
-		repo:         repo,
-		studentRepo:  studentRepo,
-		lecturerRepo: lecturerRepo,
+		repo:            repo,
+		studentRepo:     studentRepo,
+		lecturerRepo:    lecturerRepo,
+		achievementRepo: achievementRepo,
 	}
 }
 
@@ -145,6 +150,20 @@ func (s *UserService) GetUserByID(ctx context.Context, id string) (*model.UserWi
 	resp.Student, _ = s.studentRepo.GetStudentProfile(ctx, id)
 	resp.Lecturer, _ = s.lecturerRepo.GetLecturerProfile(ctx, id)
 
+	// pemakaian penyimpanan; gagal hitung tidak menggagalkan detail user
+	if resp.Student != nil {
+		usage, err := s.achievementRepo.StorageByStudent(ctx, []string{resp.Student.ID}, 1)
+		if err != nil {
+			fmt.Println("⚠️ STORAGE USAGE FAILED:", err)
+		} else {
+			resp.StorageUsage = &model.StorageUsage{StudentID: resp.Student.ID}
+			if len(usage) > 0 {
+				resp.StorageUsage = &usage[0]
+			}
+			resp.StorageUsage.QuotaBytes = config.AttachmentStudentQuota()
+		}
+	}
+
 	return resp, nil
 }
 
@@ -257,7 +276,7 @@ func (s *UserService) GetAll(c *fiber.Ctx) error {
 
 // GetByID godoc
 // @Summary Get user by ID
-// @Description Admin only. Get detail user with profile
+// @Description Admin only. Get detail user with profile (students also include attachment storage usage)
 // @Tags Users
 // @Security BearerAuth
 // @Produce json
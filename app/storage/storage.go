@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Storage: tempat menyimpan file lampiran prestasi.
+// Key berupa path relatif dipisah "/" (mis. sha256/ab/<hash>).
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// GetRange: length byte mulai offset (untuk HTTP Range)
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete: key yang tidak ada bukan error
+	Delete(ctx context.Context, key string) error
+}
+
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// driver storage
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Config: pilihan backend storage (dari env, lihat config.Storage*)
+type Config struct {
+	Driver   string // local (default) / s3
+	LocalDir string
+	S3       S3Config
+}
+
+// New: storage sesuai driver
+func New(cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocalStorage(cfg.LocalDir)
+	case DriverS3:
+		return NewS3Storage(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Driver)
+	}
+}
+
+// ValidKey: key relatif tanpa "..", "\" atau segmen kosong
+// supaya tidak bisa keluar dari root storage lokal.
+func ValidKey(key string) error {
+	if key == "" || strings.ContainsRune(key, '\\') {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Command reconcile: cek (dan perbaiki) data prestasi yang tidak sinkron
+// antara MongoDB dan PostgreSQL.
+//
+//	go run ./cmd/reconcile            # laporan saja
+//	go run ./cmd/reconcile -repair    # laporan + perbaikan
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"uas-backend/app/repository"
+	"uas-backend/app/service"
+	"uas-backend/config"
+	"uas-backend/database"
+)
+
+func main() {
+	repair := flag.Bool("repair", false, "perbaiki ketidakcocokan yang ditemukan")
+	grace := flag.Duration("grace", 15*time.Minute, "lewati data yang berubah dalam rentang ini (mungkin masih diproses)")
+	flag.Parse()
+
+	config.LoadEnv()
+	database.ConnectPostgres()
+	database.ConnectMongo()
+
+	svc := service.NewConsistencyService(
+		repository.NewAchievementRepository(database.MongoDB),
+		repository.NewAchievementReferenceRepository(database.PG),
+	)
+
+	report, err := svc.Reconcile(context.Background(), *repair, *grace)
+	if err != nil {
+		log.Fatalf("❌ Reconcile failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+
+	// exit 1 kalau masih ada yang belum beres (berguna untuk cron / CI)
+	for _, issue := range report.Issues {
+		if !issue.Repaired {
+			os.Exit(1)
+		}
+	}
+}
@@ -2,11 +2,21 @@ package config
 
 import "github.com/gofiber/fiber/v2"
 
+// ruang untuk field form & header multipart di luar isi file
+const multipartOverhead = 1 << 20
+
 func NewFiber() *fiber.App {
 	return fiber.New(fiber.Config{
 		AppName:       "UAS Backend",
 		CaseSensitive: true,
 		StrictRouting: true,
 		ServerHeader:  "Fiber-UAS",
+		BodyLimit:     bodyLimit(),
 	})
 }
+
+// bodyLimit: cukup untuk upload biasa (multipart) dan satu potongan upload bertahap;
+// file upload bertahap tidak pernah dikirim dalam satu request
+func bodyLimit() int {
+	return int(max(AttachmentMaxSize()+multipartOverhead, AttachmentChunkSize()))
+}
@@ -1,8 +1,13 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -33,3 +38,218 @@ func MongoDB() string {
 func JWTSecret() string {
 	return os.Getenv("JWT_SECRET")
 }
+
+// path file JSON definisi workflow prestasi (opsional)
+func AchievementWorkflowFile() string {
+	return os.Getenv("ACHIEVEMENT_WORKFLOW_FILE")
+}
+
+// batas submit ulang per prestasi setelah ditolak (default 3)
+func MaxResubmissions() int {
+	n, err := strconv.Atoi(os.Getenv("ACHIEVEMENT_MAX_RESUBMISSIONS"))
+	if err != nil || n < 0 {
+		return 3
+	}
+	return n
+}
+
+// path file JSON chain verifikasi bertingkat (opsional)
+func ApprovalChainsFile() string {
+	return os.Getenv("APPROVAL_CHAINS_FILE")
+}
+
+// path file JSON skema details per tipe prestasi (opsional)
+func AchievementSchemasFile() string {
+	return os.Getenv("ACHIEVEMENT_SCHEMAS_FILE")
+}
+
+// path file JSON tabel aturan poin prestasi (opsional)
+func PointRulesFile() string {
+	return os.Getenv("POINT_RULES_FILE")
+}
+
+// path file JSON SLA review per tipe prestasi (opsional)
+func ReviewSLAFile() string {
+	return os.Getenv("REVIEW_SLA_FILE")
+}
+
+// interval job pengecekan SLA review (default 60 menit)
+func ReviewSLAInterval() time.Duration {
+	n, err := strconv.Atoi(os.Getenv("REVIEW_SLA_INTERVAL_MINUTES"))
+	if err != nil || n <= 0 {
+		return 60 * time.Minute
+	}
+	return time.Duration(n) * time.Minute
+}
+
+// lama prestasi di tempat sampah sebelum dihapus permanen (default 30 hari)
+func TrashRetention() time.Duration {
+	n, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || n <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// interval job purge tempat sampah (default 24 jam)
+func TrashPurgeInterval() time.Duration {
+	n, err := strconv.Atoi(os.Getenv("TRASH_PURGE_INTERVAL_HOURS"))
+	if err != nil || n <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(n) * time.Hour
+}
+
+// berapa hari sebelum sertifikasi kedaluwarsa pengingat dikirim (default 30 hari)
+func CertExpiryReminderWindow() time.Duration {
+	n, err := strconv.Atoi(os.Getenv("CERT_EXPIRY_REMINDER_DAYS"))
+	if err != nil || n <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// interval job pengingat kedaluwarsa sertifikasi (default 24 jam)
+func CertExpiryCheckInterval() time.Duration {
+	n, err := strconv.Atoi(os.Getenv("CERT_EXPIRY_CHECK_INTERVAL_HOURS"))
+	if err != nil || n <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(n) * time.Hour
+}
+
+// backend penyimpanan lampiran: local (default) / s3
+func StorageDriver() string {
+	return os.Getenv("STORAGE_DRIVER")
+}
+
+// folder storage lokal (default ./uploads)
+func StorageLocalDir() string {
+	return os.Getenv("STORAGE_LOCAL_DIR")
+}
+
+// S3 / layanan kompatibel (MinIO dsb.)
+func S3Endpoint() string {
+	return os.Getenv("S3_ENDPOINT")
+}
+
+func S3Region() string {
+	return os.Getenv("S3_REGION")
+}
+
+func S3Bucket() string {
+	return os.Getenv("S3_BUCKET")
+}
+
+func S3AccessKey() string {
+	return os.Getenv("S3_ACCESS_KEY")
+}
+
+func S3SecretKey() string {
+	return os.Getenv("S3_SECRET_KEY")
+}
+
+// path-style URL (endpoint/bucket/key), default true untuk MinIO
+func S3PathStyle() bool {
+	v, err := strconv.ParseBool(os.Getenv("S3_PATH_STYLE"))
+	if err != nil {
+		return true
+	}
+	return v
+}
+
+// batas ukuran file lampiran upload biasa (default 10 MB), juga menentukan batas body request
+func AttachmentMaxSize() int64 {
+	n, err := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_SIZE_MB"), 10, 64)
+	if err != nil || n <= 0 {
+		return 10 << 20
+	}
+	return n << 20
+}
+
+// batas ukuran file lampiran upload bertahap (default 100 MB)
+func AttachmentResumableMaxSize() int64 {
+	n, err := strconv.ParseInt(os.Getenv("ATTACHMENT_RESUMABLE_MAX_SIZE_MB"), 10, 64)
+	if err != nil || n <= 0 {
+		return 100 << 20
+	}
+	return n << 20
+}
+
+// secret HMAC link download lampiran. Tanpa ATTACHMENT_URL_SECRET diturunkan dari
+// JWT_SECRET dengan label tujuan, jadi secret JWT tidak dipakai ulang apa adanya.
+// Kosong kalau keduanya tidak diisi (server menolak start).
+func AttachmentURLSecret() string {
+	if s := os.Getenv("ATTACHMENT_URL_SECRET"); s != "" {
+		return s
+	}
+	if JWTSecret() == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(JWTSecret()))
+	mac.Write([]byte("attachment-url"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// masa berlaku link download lampiran (default 15 menit)
+func AttachmentURLTTL() time.Duration {
+	n, err := strconv.Atoi(os.Getenv("ATTACHMENT_URL_TTL_MINUTES"))
+	if err != nil || n <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(n) * time.Minute
+}
+
+// interval sapuan lampiran yang masih menunggu pratinjau (default 5 menit)
+func AttachmentPreviewInterval() time.Duration {
+	n, err := strconv.Atoi(os.Getenv("ATTACHMENT_PREVIEW_INTERVAL_MINUTES"))
+	if err != nil || n <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(n) * time.Minute
+}
+
+// ukuran maksimal satu potongan upload bertahap (default 5 MB, tidak lebih dari batas upload bertahap)
+func AttachmentChunkSize() int64 {
+	n, err := strconv.ParseInt(os.Getenv("ATTACHMENT_CHUNK_SIZE_MB"), 10, 64)
+	if err != nil || n <= 0 {
+		n = 5
+	}
+	return min(n<<20, AttachmentResumableMaxSize())
+}
+
+// sesi upload bertahap dianggap ditinggalkan setelah tidak ada potongan masuk (default 24 jam)
+func AttachmentUploadTTL() time.Duration {
+	n, err := strconv.Atoi(os.Getenv("ATTACHMENT_UPLOAD_TTL_HOURS"))
+	if err != nil || n <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(n) * time.Hour
+}
+
+// interval job pembersihan sesi upload yang ditinggalkan (default 60 menit)
+func AttachmentUploadCleanupInterval() time.Duration {
+	n, err := strconv.Atoi(os.Getenv("ATTACHMENT_UPLOAD_CLEANUP_INTERVAL_MINUTES"))
+	if err != nil || n <= 0 {
+		return time.Hour
+	}
+	return time.Duration(n) * time.Minute
+}
+
+// kuota total lampiran per mahasiswa (default 500 MB)
+func AttachmentStudentQuota() int64 {
+	n, err := strconv.ParseInt(os.Getenv("ATTACHMENT_QUOTA_STUDENT_MB"), 10, 64)
+	if err != nil || n <= 0 {
+		return 500 << 20
+	}
+	return n << 20
+}
+
+// kuota total lampiran per prestasi (default 100 MB)
+func AttachmentAchievementQuota() int64 {
+	n, err := strconv.ParseInt(os.Getenv("ATTACHMENT_QUOTA_ACHIEVEMENT_MB"), 10, 64)
+	if err != nil || n <= 0 {
+		return 100 << 20
+	}
+	return n << 20
+}
@@ -0,0 +1,41 @@
+-- Riwayat perubahan status prestasi + penghitung submit ulang.
+-- Jalankan manual di PostgreSQL sebelum deploy.
+
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS resubmission_count INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS achievement_status_history (
+    id                   BIGSERIAL PRIMARY KEY,
+    mongo_achievement_id TEXT        NOT NULL,
+    status               TEXT        NOT NULL,
+    note                 TEXT,
+    changed_by           UUID,
+    changed_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_status_history_achievement
+    ON achievement_status_history (mongo_achievement_id, changed_at);
+
+-- backfill riwayat untuk data lama (diturunkan dari kolom reference)
+INSERT INTO achievement_status_history (mongo_achievement_id, status, note, changed_by, changed_at)
+SELECT mongo_achievement_id, status, note, changed_by, changed_at
+FROM (
+    SELECT mongo_achievement_id, 'draft' AS status, NULL AS note, NULL::uuid AS changed_by, created_at AS changed_at
+    FROM achievement_references
+    UNION ALL
+    SELECT mongo_achievement_id, 'submitted', NULL, NULL, submitted_at
+    FROM achievement_references WHERE submitted_at IS NOT NULL
+    UNION ALL
+    SELECT mongo_achievement_id, 'verified', NULL, verified_by::uuid, verified_at
+    FROM achievement_references WHERE verified_at IS NOT NULL
+    UNION ALL
+    SELECT mongo_achievement_id, 'rejected', rejection_note, NULL, updated_at
+    FROM achievement_references WHERE status::text = 'rejected'
+    UNION ALL
+    SELECT mongo_achievement_id, 'deleted', NULL, NULL, updated_at
+    FROM achievement_references WHERE status::text = 'deleted'
+) legacy
+WHERE NOT EXISTS (
+    SELECT 1 FROM achievement_status_history h
+    WHERE h.mongo_achievement_id = legacy.mongo_achievement_id
+);
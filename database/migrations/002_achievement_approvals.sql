@@ -0,0 +1,26 @@
+-- Verifikasi bertingkat (dosen wali → fakultas, dst).
+
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS approval_level INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS pending_level  TEXT;
+
+CREATE TABLE IF NOT EXISTS achievement_approvals (
+    id                   BIGSERIAL PRIMARY KEY,
+    mongo_achievement_id TEXT        NOT NULL,
+    round                INT         NOT NULL DEFAULT 0,
+    level                INT         NOT NULL,
+    level_name           TEXT        NOT NULL,
+    approved_by          UUID        NOT NULL,
+    note                 TEXT,
+    approved_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (mongo_achievement_id, round, level)
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_pending_level
+    ON achievement_references (pending_level, submitted_at)
+    WHERE pending_level IS NOT NULL;
+
+-- prestasi yang sedang menunggu verifikasi masuk ke antrian tahap pertama
+UPDATE achievement_references
+SET pending_level = 'advisor'
+WHERE status::text = 'submitted' AND pending_level IS NULL;
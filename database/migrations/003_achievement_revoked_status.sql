@@ -0,0 +1,18 @@
+-- Status "revoked" untuk prestasi yang verifikasinya dicabut admin.
+-- Kolom status bisa berupa ENUM; tambahkan nilainya kalau memang ENUM.
+-- State lain dari ACHIEVEMENT_WORKFLOW_FILE butuh 008 (kolom status jadi TEXT).
+
+DO $$
+DECLARE
+    enum_type TEXT;
+BEGIN
+    SELECT c.udt_name INTO enum_type
+    FROM information_schema.columns c
+    JOIN pg_type t ON t.typname = c.udt_name AND t.typtype = 'e'
+    WHERE c.table_name = 'achievement_references'
+      AND c.column_name = 'status';
+
+    IF enum_type IS NOT NULL THEN
+        EXECUTE format('ALTER TYPE %I ADD VALUE IF NOT EXISTS %L', enum_type, 'revoked');
+    END IF;
+END $$;
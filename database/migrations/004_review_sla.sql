@@ -0,0 +1,10 @@
+-- Penanda SLA review: pengingat (overdue) dan eskalasi ke admin.
+-- Di-reset setiap kali prestasi disubmit ulang / ditarik.
+
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS overdue_at   TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_pending
+    ON achievement_references (submitted_at)
+    WHERE pending_level IS NOT NULL;
@@ -0,0 +1,13 @@
+-- Anggota prestasi tim. Hanya prestasi tim yang punya baris di sini (pemiliknya
+-- ikut tercatat sebagai anggota); prestasi individu tidak punya baris, jadi query
+-- "prestasi mahasiswa X" tetap mencocokkan achievement_references.student_id.
+
+CREATE TABLE IF NOT EXISTS achievement_members (
+    mongo_achievement_id TEXT NOT NULL,
+    student_id           UUID NOT NULL,
+    role                 TEXT NOT NULL DEFAULT 'member',
+    PRIMARY KEY (mongo_achievement_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_members_student
+    ON achievement_members (student_id);
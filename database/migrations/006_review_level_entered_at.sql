@@ -0,0 +1,21 @@
+-- Waktu tahap verifikasi yang sedang ditunggu dimulai. SLA review dihitung
+-- per tahap dari kolom ini (bukan dari submitted_at), dan penanda
+-- overdue / escalated di-reset setiap kali naik tahap.
+
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS level_entered_at TIMESTAMPTZ;
+
+-- data lama: tahap berjalan dimulai saat persetujuan terakhir ronde ini, atau saat submit
+UPDATE achievement_references r
+SET level_entered_at = COALESCE(
+        (SELECT MAX(a.approved_at)
+         FROM achievement_approvals a
+         WHERE a.mongo_achievement_id = r.mongo_achievement_id
+           AND a.round = r.resubmission_count),
+        r.submitted_at)
+WHERE r.pending_level IS NOT NULL
+  AND r.level_entered_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_level_entered
+    ON achievement_references (level_entered_at)
+    WHERE pending_level IS NOT NULL;
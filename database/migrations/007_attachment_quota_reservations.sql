@@ -0,0 +1,15 @@
+-- Pesanan kuota penyimpanan lampiran. Cek kuota + pencatatan pesanan berjalan
+-- di satu transaksi pendek di bawah advisory lock per mahasiswa; kunci sudah
+-- lepas saat file dikirim ke storage, pesanan yang menahan kuotanya.
+
+CREATE TABLE IF NOT EXISTS attachment_quota_reservations (
+    id                   BIGSERIAL PRIMARY KEY,
+    student_id           UUID        NOT NULL,
+    mongo_achievement_id TEXT        NOT NULL,
+    bytes                BIGINT      NOT NULL,
+    expires_at           TIMESTAMPTZ NOT NULL,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachment_quota_reservations_student
+    ON attachment_quota_reservations (student_id, expires_at);
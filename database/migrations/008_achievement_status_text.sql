@@ -0,0 +1,32 @@
+-- Status prestasi mengikuti workflow yang bisa dikonfigurasi lewat
+-- ACHIEVEMENT_WORKFLOW_FILE, jadi kolom status tidak boleh dibatasi ENUM:
+-- state baru di config (selain yang ditambahkan 003) akan ditolak database.
+-- Kalau masih ENUM, ubah ke TEXT; nilai yang sudah ada tidak berubah.
+-- Tipe ENUM lama dibiarkan karena mungkin masih dipakai objek lain.
+
+DO $$
+DECLARE
+    enum_type TEXT;
+    col_default TEXT;
+BEGIN
+    SELECT c.udt_name, c.column_default INTO enum_type, col_default
+    FROM information_schema.columns c
+    JOIN pg_type t ON t.typname = c.udt_name AND t.typtype = 'e'
+    WHERE c.table_name = 'achievement_references'
+      AND c.column_name = 'status';
+
+    IF enum_type IS NULL THEN
+        RETURN;
+    END IF;
+
+    -- default ber-tipe ENUM tidak bisa di-cast otomatis
+    ALTER TABLE achievement_references ALTER COLUMN status DROP DEFAULT;
+    ALTER TABLE achievement_references ALTER COLUMN status TYPE TEXT USING status::text;
+    -- default lama (mis. 'draft'::achievement_status) dipasang lagi sebagai teks
+    IF col_default IS NOT NULL THEN
+        EXECUTE format(
+            'ALTER TABLE achievement_references ALTER COLUMN status SET DEFAULT %L',
+            substring(col_default FROM '^''([^'']*)''')
+        );
+    END IF;
+END $$;
@@ -15,74 +15,54 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/achievements": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Mahasiswa otomatis pakai student_id dari JWT, Admin wajib mengisi studentId",
-                "consumes": [
-                    "application/json"
-                ],
+        "/achievement-templates": {
+            "get": {
+                "description": "Mahasiswa hanya melihat template aktif. Admin / Dosen Wali bisa menambah include_inactive=true.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Achievements"
+                    "Achievement Templates"
                 ],
-                "summary": "Buat prestasi baru",
+                "summary": "Ambil daftar template prestasi",
                 "parameters": [
                     {
-                        "description": "Create Achievement Payload",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service.CreateAchievementRequest"
-                        }
+                        "type": "string",
+                        "description": "Filter achievementType",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sertakan template nonaktif (admin / dosen wali)",
+                        "name": "include_inactive",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Achievement created",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "403": {
-                        "description": "Access denied",
+                    "200": {
+                        "description": "List achievement templates",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Failed to create achievement",
+                        "description": "Failed to fetch templates",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/achievements/": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            },
+            "post": {
+                "description": "Hanya Admin / Dosen Wali. Details divalidasi sebagian sesuai skema tipe; placeholder {key} di title_pattern harus field skema; required_attachments berisi jenis bukti (certificate, appointment_letter, publication, photo, other) yang wajib saat submit.",
                 "consumes": [
                     "application/json"
                 ],
@@ -90,55 +70,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Achievements"
+                    "Achievement Templates"
+                ],
+                "summary": "Buat template prestasi",
+                "parameters": [
+                    {
+                        "description": "Template payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.AchievementTemplateRequest"
+                        }
+                    }
                 ],
-                "summary": "Ambil daftar prestasi",
                 "responses": {
-                    "200": {
-                        "description": "List achievements",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.AchievementTemplate"
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "400": {
+                        "description": "Validation failed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Failed to fetch achievements",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/achievements/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/achievement-templates/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Achievements"
+                    "Achievement Templates"
                 ],
-                "summary": "Ambil detail prestasi",
+                "summary": "Ambil detail template prestasi",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Achievement ID (Mongo ObjectID)",
+                        "description": "Template ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -146,41 +133,27 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Achievement detail",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid ID",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "403": {
-                        "description": "Access denied",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.AchievementTemplate"
                         }
                     },
                     "404": {
-                        "description": "Achievement not found",
+                        "description": "Template not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
-                }
-            },
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            },
+            "put": {
+                "description": "Admin semua template, Dosen Wali hanya template buatannya. Prestasi yang sudah dibuat tidak ikut berubah, kecuali required_attachments yang dicek saat submit.",
                 "consumes": [
                     "application/json"
                 ],
@@ -188,37 +161,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Achievements"
+                    "Achievement Templates"
                 ],
-                "summary": "Update prestasi",
+                "summary": "Ubah template prestasi",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Achievement ID",
+                        "description": "Template ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update Achievement Payload",
+                        "description": "Template payload",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service.UpdateAchievementRequest"
+                            "$ref": "#/definitions/service.AchievementTemplateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Achievement updated",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.AchievementTemplate"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Validation failed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -232,41 +204,32 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Achievement not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to update achievement",
+                        "description": "Template not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            },
+            "delete": {
+                "description": "Admin semua template, Dosen Wali hanya template buatannya.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Achievements"
+                    "Achievement Templates"
                 ],
-                "summary": "Hapus prestasi",
+                "summary": "Hapus template prestasi",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Achievement ID",
+                        "description": "Template ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -274,14 +237,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Achievement deleted",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request",
+                        "description": "Template deleted",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -295,31 +251,51 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Achievement not found",
+                        "description": "Template not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "500": {
-                        "description": "Failed to delete achievement",
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievement-types": {
+            "get": {
+                "description": "Dipakai frontend untuk membangun form details per tipe (field wajib, enum, format).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievement Types"
+                ],
+                "summary": "Ambil daftar tipe prestasi beserta skema details",
+                "responses": {
+                    "200": {
+                        "description": "List achievement type schemas",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/achievements/{id}/attachments": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/achievements": {
+            "post": {
+                "description": "Mahasiswa otomatis pakai student_id dari JWT, Admin wajib mengisi studentId.\nPrestasi tim: isi members (student_id + role leader/member), pemilik otomatis ikut.\nResponse berisi warnings: prestasi lain yang kemungkinan duplikat (untuk mahasiswa, milik mahasiswa lain hanya skor \u0026 alasan).\ntemplateId (opsional): tipe, details bawaan, description \u0026 tags dari template; judul dari title_pattern kalau title kosong.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
@@ -327,33 +303,28 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Upload lampiran prestasi",
+                "summary": "Buat prestasi baru",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Achievement ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Attachment file",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "description": "Create Achievement Payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.CreateAchievementRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Attachment uploaded",
+                    "201": {
+                        "description": "Achievement created",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid request / details validation failed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -366,31 +337,23 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Achievement not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "500": {
-                        "description": "Failed to upload attachment",
+                        "description": "Failed to create achievement",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/achievements/{id}/history": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Menampilkan timeline status: draft, submitted, verified, rejected",
+                ]
+            }
+        },
+        "/achievements/": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -400,19 +363,10 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Ambil riwayat status prestasi",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Achievement ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Ambil daftar prestasi",
                 "responses": {
                     "200": {
-                        "description": "Achievement history",
+                        "description": "List achievements",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -425,24 +379,23 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Achievement not found",
+                    "500": {
+                        "description": "Failed to fetch achievements",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/achievements/{id}/reject": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Hanya untuk Dosen Wali dan Admin, status harus submitted",
+                ]
+            }
+        },
+        "/achievements/bulk/reject": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -452,28 +405,21 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Tolak prestasi",
+                "summary": "Tolak banyak prestasi sekaligus",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Achievement ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Reject Payload",
+                        "description": "Bulk Reject Payload",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service.RejectAchievementRequest"
+                            "$ref": "#/definitions/service.BulkRejectRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Achievement rejected",
+                        "description": "Per-item result report",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -485,31 +431,17 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "403": {
-                        "description": "Access denied",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Achievement not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     }
-                }
-            }
-        },
-        "/achievements/{id}/submit": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/achievements/bulk/verify": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -519,30 +451,67 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Submit prestasi untuk verifikasi",
+                "summary": "Verifikasi banyak prestasi sekaligus",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Achievement ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Bulk Verify Payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.BulkVerifyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Achievement submitted",
+                        "description": "Per-item result report",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid status",
+                        "description": "Invalid request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/trash": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Tempat sampah prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter mahasiswa (admin)",
+                        "name": "student_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TrashItem"
+                            }
+                        }
                     },
                     "403": {
                         "description": "Access denied",
@@ -551,23 +520,55 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Achievement not found",
+                    "500": {
+                        "description": "Failed to fetch trash",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/achievements/{id}/verify": {
+        "/achievements/trash/purge": {
             "post": {
+                "description": "Hapus permanen prestasi yang sudah melewati masa simpan (beserta file lampiran). Job yang sama berjalan berkala.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Jalankan purge tempat sampah sekarang",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TrashPurgeReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to purge trash",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/achievements/{id}": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -577,11 +578,11 @@ const docTemplate = `{
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Verifikasi prestasi",
+                "summary": "Ambil detail prestasi",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Achievement ID",
+                        "description": "Achievement ID (Mongo ObjectID)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -589,14 +590,14 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Achievement verified",
+                        "description": "Achievement detail",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid status",
+                        "description": "Invalid ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -616,12 +617,14 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/auth/login": {
-            "post": {
-                "description": "Login menggunakan username/email dan password",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -629,67 +632,71 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Achievements"
                 ],
-                "summary": "Login user",
+                "summary": "Update prestasi",
                 "parameters": [
                     {
-                        "description": "Login payload",
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Achievement Payload",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.LoginRequest"
+                            "$ref": "#/definitions/service.UpdateAchievementRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful",
+                        "description": "Achievement updated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid request / details validation failed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Invalid credentials",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Account not active",
+                    "404": {
+                        "description": "Achievement not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to update achievement",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/auth/logout": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Logout user dan memblokir JWT sampai expired",
+                ]
+            },
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -697,70 +704,1722 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Achievements"
+                ],
+                "summary": "Hapus prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Logout user",
                 "responses": {
                     "200": {
-                        "description": "Logout successful",
+                        "description": "Achievement deleted",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid token",
+                        "description": "Invalid request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete achievement",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/auth/profile": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Mengambil profile user dari JWT",
+                ]
+            }
+        },
+        "/achievements/{id}/attachments": {
+            "post": {
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Achievements"
+                ],
+                "summary": "Upload lampiran prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Attachment file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Jenis bukti: certificate, appointment_letter, publication, photo, other (default other)",
+                        "name": "kind",
+                        "in": "formData"
+                    }
                 ],
-                "summary": "Get current user profile",
                 "responses": {
                     "200": {
-                        "description": "Profile fetched",
+                        "description": "Attachment uploaded",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "File too large / storage quota exceeded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "415": {
+                        "description": "File type not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to upload attachment",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/attachments/{attachmentId}": {
+            "get": {
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Download lampiran prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachmentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Paksa simpan sebagai file",
+                        "name": "download",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "thumbnail / preview (JPEG, kalau pratinjau sudah siap)",
+                        "name": "variant",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid variant",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment / preview not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "416": {
+                        "description": "Range not satisfiable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Ganti file lampiran prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachmentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File pengganti",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Jenis bukti: certificate, appointment_letter, publication, photo, other (default jenis lampiran lama)",
+                        "name": "kind",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachment replaced",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "File already attached",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "File too large / storage quota exceeded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "415": {
+                        "description": "File type not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to replace attachment",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Hapus lampiran prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachment deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Achievement is not editable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete attachment",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/attachments/{attachmentId}/link": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Link download lampiran sementara",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachmentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "thumbnail / preview",
+                        "name": "variant",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "url \u0026 expires_at",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid variant",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment / preview not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/comments": {
+            "get": {
+                "description": "Komentar dari mahasiswa anggota, dosen wali, dan admin. Thread tetap utuh walau prestasi direvisi.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Ambil thread komentar prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List comments",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Tambah komentar pada prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.CreateCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Comment created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/history": {
+            "get": {
+                "description": "Menampilkan timeline status dari riwayat: draft, submitted, verified, rejected, revisi, dst.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Ambil riwayat status prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Achievement history",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/reject": {
+            "post": {
+                "description": "Hanya untuk Dosen Wali dan Admin, status harus submitted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Tolak prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reject Payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.RejectAchievementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Achievement rejected",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/renew": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Perpanjang sertifikasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID (sertifikasi lama)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data sertifikasi baru",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.RenewAchievementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Achievement created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request / details validation failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Not a verified certification / already renewed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/restore": {
+            "post": {
+                "description": "Status kembali ke draft. Hanya pemilik / admin, sebelum dihapus permanen.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Pulihkan prestasi dari tempat sampah",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Achievement restored",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Achievement is not deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/revise": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Revisi prestasi yang ditolak",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Revise Payload",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/service.ReviseAchievementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Achievement reopened for revision",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid status / resubmission limit reached",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/revoke": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Cabut verifikasi prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Revoke reason",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.RevokeAchievementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Achievement revoked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Reason is required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/submit": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Submit prestasi untuk verifikasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Achievement submitted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Details do not match achievement type schema / evidence requirements not met",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/uploads": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Mulai upload lampiran bertahap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "File yang akan diupload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.CreateUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Upload session created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Achievement is not editable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "File too large / storage quota exceeded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/uploads/{uploadId}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Status upload lampiran bertahap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload session ID",
+                        "name": "uploadId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Upload session",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Upload not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "410": {
+                        "description": "Upload session expired",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Batalkan upload lampiran bertahap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload session ID",
+                        "name": "uploadId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Upload cancelled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Upload not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Kirim satu potongan upload lampiran bertahap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload session ID",
+                        "name": "uploadId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset potongan",
+                        "name": "Upload-Offset",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sha256 \u003cbase64\u003e isi potongan",
+                        "name": "Upload-Checksum",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Chunk received",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "201": {
+                        "description": "Attachment uploaded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid chunk",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Upload not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Offset mismatch / upload is already being finished",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "410": {
+                        "description": "Upload session expired",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Chunk too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "415": {
+                        "description": "File type not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "460": {
+                        "description": "Checksum mismatch",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/verify": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Verifikasi prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Verify Payload",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/service.VerifyAchievementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Achievement verified / level approved",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid points override",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/versions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Riwayat versi dokumen prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List versions",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/versions/diff": {
+            "get": {
+                "description": "Perubahan per field dari versi from ke versi to. Default: versi terakhir dibanding versi sebelumnya.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Perbedaan dua versi prestasi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Versi awal",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Versi akhir",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AchievementDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid version",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/withdraw": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Tarik kembali prestasi yang sudah disubmit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Achievement withdrawn",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Achievement not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Already reviewed / invalid status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Login menggunakan username/email dan password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Login payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Account not active",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Logout user dan memblokir JWT sampai expired",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Logout user",
+                "responses": {
+                    "200": {
+                        "description": "Logout successful",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/profile": {
+            "get": {
+                "description": "Mengambil profile user dari JWT",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get current user profile",
+                "responses": {
+                    "200": {
+                        "description": "Profile fetched",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
         "/auth/refresh": {
             "post": {
                 "description": "Generate access token baru menggunakan refresh token",
@@ -776,39 +2435,115 @@ const docTemplate = `{
                 "summary": "Refresh access token",
                 "parameters": [
                     {
-                        "description": "Refresh token payload",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.RefreshTokenRequest"
-                        }
+                        "description": "Refresh token payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token refreshed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid refresh token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/achievements/{id}/attachments/{attachmentId}": {
+            "get": {
+                "description": "Tanpa JWT; link dari GET /achievements/{id}/attachments/{attachmentId}/link.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Download lampiran lewat link bertanda tangan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Achievement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachmentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix time kedaluwarsa",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "thumbnail / preview",
+                        "name": "variant",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Token refreshed",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "file"
                         }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "206": {
+                        "description": "Partial content",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "file"
                         }
                     },
-                    "401": {
-                        "description": "Invalid refresh token",
+                    "403": {
+                        "description": "Invalid or expired link",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Attachment not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -819,11 +2554,6 @@ const docTemplate = `{
         },
         "/lecturers": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
                 "description": "Admin only. Get list of all lecturers",
                 "produces": [
                     "application/json"
@@ -860,16 +2590,16 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/lecturers/{id}/advisees": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/lecturers/{id}/advisees": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
@@ -923,23 +2653,276 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/notifications": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Ambil notifikasi user login",
+                "responses": {
+                    "200": {
+                        "description": "List notifications",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch notifications",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/notifications/{id}/read": {
+            "put": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Tandai notifikasi sudah dibaca",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification marked as read",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/points/recalculate": {
+            "post": {
+                "description": "Dijalankan otomatis saat aplikasi start; endpoint ini untuk memicu manual.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Points"
+                ],
+                "summary": "Hitung ulang poin prestasi verified",
+                "responses": {
+                    "200": {
+                        "description": "Recalculated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to recalculate points",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/points/rules": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Points"
+                ],
+                "summary": "Ambil tabel aturan poin prestasi",
+                "responses": {
+                    "200": {
+                        "description": "Point rules + version",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
         "/reports/statistics": {
             "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get achievement statistics",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Ikut hitung sertifikasi yang sudah kedaluwarsa",
+                        "name": "include_expired",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AchievementStatisticsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
+                ]
+            }
+        },
+        "/reports/storage": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Laporan penyimpanan lampiran",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Jumlah baris per daftar (default 10, maks 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StorageReport"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reports/student/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Reports"
                 ],
-                "summary": "Get achievement statistics",
+                "summary": "Get student achievement statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Student ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Ikut hitung sertifikasi yang sudah kedaluwarsa",
+                        "name": "include_expired",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -965,67 +2948,75 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/reports/student/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/reviews/pending": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reports"
+                    "Reviews"
                 ],
-                "summary": "Get student achievement statistics",
+                "summary": "Antrian review milik reviewer login",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Student ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter tahap verifikasi (mis. advisor, faculty)",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter tipe prestasi",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter mahasiswa",
+                        "name": "student_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "List review queue items",
                         "schema": {
-                            "$ref": "#/definitions/model.AchievementStatisticsResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "403": {
-                        "description": "Forbidden",
+                        "description": "Access denied",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Failed to fetch review queue",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/students": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/students": {
+            "get": {
                 "description": "Admin only. Get list of all students",
                 "produces": [
                     "application/json"
@@ -1062,16 +3053,16 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/students/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/students/{id}": {
+            "get": {
                 "description": "Admin only. Get detail of a student",
                 "produces": [
                     "application/json"
@@ -1114,16 +3105,16 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/students/{id}/achievements": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/students/{id}/achievements": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
@@ -1177,16 +3168,16 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/students/{id}/advisor": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/students/{id}/advisor": {
+            "put": {
                 "description": "Admin only. Assign or update advisor (lecturer) for a student",
                 "consumes": [
                     "application/json"
@@ -1262,16 +3253,16 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/users": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/users": {
+            "get": {
                 "description": "Admin only. Retrieve list of all users with profiles",
                 "produces": [
                     "application/json"
@@ -1311,14 +3302,14 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            },
+            "post": {
                 "description": "Admin only. Create new system user (student / lecturer / admin)",
                 "consumes": [
                     "application/json"
@@ -1367,17 +3358,17 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/users/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Admin only. Get detail user with profile",
+                ]
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Admin only. Get detail user with profile (students also include attachment storage usage)",
                 "produces": [
                     "application/json"
                 ],
@@ -1422,14 +3413,14 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            },
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            },
+            "put": {
                 "description": "Admin only. Update user data and profile",
                 "consumes": [
                     "application/json"
@@ -1487,14 +3478,14 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            },
+            "delete": {
                 "description": "Admin only. Soft delete user",
                 "produces": [
                     "application/json"
@@ -1540,16 +3531,16 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/users/{id}/role": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
                 "description": "Admin only. Assign or change user role",
                 "consumes": [
                     "application/json"
@@ -1607,7 +3598,12 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         }
     },
@@ -1627,6 +3623,9 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "deleted_at": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -1640,13 +3639,34 @@ const docTemplate = `{
                 "is_deleted": {
                     "type": "boolean"
                 },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AchievementMember"
+                    }
+                },
                 "points": {
+                    "description": "dihitung server, lihat PointRule",
                     "type": "integer"
                 },
+                "points_override": {
+                    "$ref": "#/definitions/model.PointsOverride"
+                },
+                "points_rule_version": {
+                    "type": "string"
+                },
+                "renewed_by_id": {
+                    "type": "string"
+                },
+                "renews_id": {
+                    "description": "sertifikasi lama yang diperpanjang",
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
                 },
                 "student_id": {
+                    "description": "pemilik / pembuat",
                     "type": "string"
                 },
                 "tags": {
@@ -1655,29 +3675,92 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "template_id": {
+                    "description": "dibuat dari AchievementTemplate",
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "valid_from": {
+                    "description": "sertifikasi: details.issuedDate",
+                    "type": "string"
+                },
+                "valid_until": {
+                    "description": "sertifikasi: details.validUntil",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AchievementDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FieldChange"
+                    }
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AchievementMember": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
                 }
             }
         },
         "model.AchievementReference": {
             "type": "object",
             "properties": {
+                "approvalLevel": {
+                    "description": "jumlah tahap yang sudah disetujui",
+                    "type": "integer"
+                },
                 "createdAt": {
                     "type": "string"
                 },
+                "escalatedAt": {
+                    "description": "sudah dieskalasi ke admin",
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "levelEnteredAt": {
+                    "description": "tahap yang ditunggu mulai (dasar SLA)",
+                    "type": "string"
+                },
                 "mongoAchievementID": {
                     "type": "string"
                 },
+                "overdueAt": {
+                    "description": "pengingat SLA sudah dikirim",
+                    "type": "string"
+                },
+                "pendingLevel": {
+                    "description": "nama tahap yang sedang ditunggu",
+                    "type": "string"
+                },
                 "rejectionNote": {
                     "type": "string"
                 },
+                "resubmissionCount": {
+                    "type": "integer"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -1707,6 +3790,10 @@ const docTemplate = `{
                         "$ref": "#/definitions/model.CompetitionLevelStat"
                     }
                 },
+                "expired_excluded": {
+                    "description": "sertifikasi kedaluwarsa yang tidak dihitung",
+                    "type": "integer"
+                },
                 "total_per_period": {
                     "type": "array",
                     "items": {
@@ -1719,11 +3806,84 @@ const docTemplate = `{
                         "$ref": "#/definitions/model.StudentAchievementStat"
                     }
                 },
-                "total_per_type": {
+                "total_per_type": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AchievementTypeStat"
+                    }
+                }
+            }
+        },
+        "model.AchievementStorageUsage": {
+            "type": "object",
+            "properties": {
+                "achievement_id": {
+                    "type": "string"
+                },
+                "attachments": {
+                    "type": "integer"
+                },
+                "quota_bytes": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "used_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AchievementTemplate": {
+            "type": "object",
+            "properties": {
+                "achievementType": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "users.id",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required_attachments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.AchievementTypeStat"
+                        "type": "string"
                     }
+                },
+                "title_pattern": {
+                    "description": "placeholder {key} diambil dari details",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
@@ -1745,16 +3905,84 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "file_type": {
+                    "description": "MIME hasil sniffing isi file",
                     "type": "string"
                 },
                 "file_url": {
+                    "description": "lama: path di ./uploads",
+                    "type": "string"
+                },
+                "hash": {
+                    "description": "sha256 isi file",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "dipakai URL download",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "object key di storage",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "jenis bukti, lihat EvidenceKinds",
                     "type": "string"
                 },
+                "preview": {
+                    "description": "thumbnail \u0026 pratinjau",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AttachmentPreview"
+                        }
+                    ]
+                },
+                "size": {
+                    "description": "byte",
+                    "type": "integer"
+                },
                 "uploaded_at": {
                     "type": "string"
                 }
             }
         },
+        "model.AttachmentPreview": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer"
+                },
+                "preview_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "width": {
+                    "description": "ukuran pratinjau (px)",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ChangeRequest": {
+            "type": "object",
+            "properties": {
+                "attachment_id": {
+                    "description": "attachments[].id",
+                    "type": "string"
+                },
+                "attachment_name": {
+                    "description": "diisi server: nama file saat diminta",
+                    "type": "string"
+                },
+                "field": {
+                    "description": "title, description, details.\u003ckey\u003e, ...",
+                    "type": "string"
+                }
+            }
+        },
         "model.CompetitionLevelStat": {
             "type": "object",
             "properties": {
@@ -1789,6 +4017,16 @@ const docTemplate = `{
                 }
             }
         },
+        "model.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {},
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
         "model.Lecturer": {
             "type": "object",
             "properties": {
@@ -1848,6 +4086,27 @@ const docTemplate = `{
                 }
             }
         },
+        "model.PointsOverride": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "by": {
+                    "description": "users.id verifikator",
+                    "type": "string"
+                },
+                "calculated_points": {
+                    "type": "integer"
+                },
+                "justification": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.RefreshTokenRequest": {
             "type": "object",
             "properties": {
@@ -1867,6 +4126,50 @@ const docTemplate = `{
                 }
             }
         },
+        "model.StorageReport": {
+            "type": "object",
+            "properties": {
+                "top_achievements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AchievementStorageUsage"
+                    }
+                },
+                "top_students": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StorageUsage"
+                    }
+                },
+                "total_attachments": {
+                    "type": "integer"
+                },
+                "total_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.StorageUsage": {
+            "type": "object",
+            "properties": {
+                "achievements": {
+                    "type": "integer"
+                },
+                "attachments": {
+                    "type": "integer"
+                },
+                "quota_bytes": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "description": "students.id",
+                    "type": "string"
+                },
+                "used_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.Student": {
             "type": "object",
             "properties": {
@@ -1929,6 +4232,34 @@ const docTemplate = `{
                 }
             }
         },
+        "model.TrashItem": {
+            "type": "object",
+            "properties": {
+                "achievement": {
+                    "$ref": "#/definitions/model.Achievement"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "purge_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TrashPurgeReport": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "type": "integer"
+                },
+                "files_removed": {
+                    "type": "integer"
+                },
+                "purged": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.UpdateUserRequest": {
             "type": "object",
             "properties": {
@@ -1984,6 +4315,14 @@ const docTemplate = `{
                 "role_name": {
                     "type": "string"
                 },
+                "storage_usage": {
+                    "description": "pemakaian penyimpanan lampiran (hanya mahasiswa, di detail user)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.StorageUsage"
+                        }
+                    ]
+                },
                 "student_profile": {
                     "$ref": "#/definitions/model.Student"
                 },
@@ -1992,6 +4331,71 @@ const docTemplate = `{
                 }
             }
         },
+        "service.AchievementTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "achievementType": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "is_active": {
+                    "description": "default true",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required_attachments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title_pattern": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.BulkRejectRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rejection_note": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.BulkVerifyRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
         "service.CreateAchievementRequest": {
             "type": "object",
             "properties": {
@@ -2005,8 +4409,12 @@ const docTemplate = `{
                     "type": "object",
                     "additionalProperties": {}
                 },
-                "points": {
-                    "type": "integer"
+                "members": {
+                    "description": "anggota tim (opsional); pemilik otomatis ikut sebagai leader",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AchievementMember"
+                    }
                 },
                 "studentId": {
                     "type": "string"
@@ -2017,11 +4425,47 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "templateId": {
+                    "description": "template (opsional): tipe, details, description \u0026 tags diambil dari template",
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
                 }
             }
         },
+        "service.CreateCommentRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "change_request": {
+                    "$ref": "#/definitions/model.ChangeRequest"
+                },
+                "parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CreateUploadRequest": {
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "description": "sha256 (hex) seluruh file, opsional; dicek saat upload selesai",
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
         "service.RejectAchievementRequest": {
             "type": "object",
             "properties": {
@@ -2030,6 +4474,43 @@ const docTemplate = `{
                 }
             }
         },
+        "service.RenewAchievementRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.ReviseAchievementRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.RevokeAchievementRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
         "service.UpdateAchievementRequest": {
             "type": "object",
             "properties": {
@@ -2040,8 +4521,12 @@ const docTemplate = `{
                     "type": "object",
                     "additionalProperties": {}
                 },
-                "points": {
-                    "type": "integer"
+                "members": {
+                    "description": "nil = tidak diubah, [] = jadi prestasi individu",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AchievementMember"
+                    }
                 },
                 "tags": {
                     "type": "array",
@@ -2053,6 +4538,20 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "service.VerifyAchievementRequest": {
+            "type": "object",
+            "properties": {
+                "justification": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -20,8 +20,21 @@ func AchievementRoutes(
 	)
 
 	api.Get("/", achievementSvc.GetAchievements)
+
+	// trash didaftarkan sebelum /:id agar "trash" tidak dianggap id
+	api.Get("/trash", achievementSvc.GetTrash)
+	api.Post(
+		"/trash/purge",
+		middleware.RequirePermission("user:manage"),
+		achievementSvc.PurgeTrashNow,
+	)
+
 	api.Get("/:id", achievementSvc.GetAchievementByID)
 
+	// bulk didaftarkan sebelum /:id/... agar "bulk" tidak dianggap id
+	api.Post("/bulk/verify", achievementSvc.BulkVerifyAchievements)
+	api.Post("/bulk/reject", achievementSvc.BulkRejectAchievements)
+
 	api.Post(
 		"/",
 		middleware.RequirePermission("achievement:create"),
@@ -34,6 +47,54 @@ func AchievementRoutes(
 		achievementSvc.UploadAttachment,
 	)
 
+	api.Get(
+		"/:id/attachments/:attachmentId",
+		// akses sama dengan detail prestasi, dicek di service
+		achievementSvc.DownloadAttachment,
+	)
+
+	api.Put(
+		"/:id/attachments/:attachmentId",
+		middleware.RequirePermission("achievement:update"),
+		achievementSvc.ReplaceAttachment,
+	)
+
+	api.Delete(
+		"/:id/attachments/:attachmentId",
+		middleware.RequirePermission("achievement:update"),
+		achievementSvc.DeleteAttachment,
+	)
+
+	api.Get(
+		"/:id/attachments/:attachmentId/link",
+		achievementSvc.GetAttachmentLink,
+	)
+
+	// upload bertahap (resumable) untuk file besar; GET juga melayani HEAD
+	api.Post(
+		"/:id/uploads",
+		middleware.RequirePermission("achievement:update"),
+		achievementSvc.CreateUpload,
+	)
+
+	api.Get(
+		"/:id/uploads/:uploadId",
+		middleware.RequirePermission("achievement:update"),
+		achievementSvc.GetUpload,
+	)
+
+	api.Patch(
+		"/:id/uploads/:uploadId",
+		middleware.RequirePermission("achievement:update"),
+		achievementSvc.PatchUpload,
+	)
+
+	api.Delete(
+		"/:id/uploads/:uploadId",
+		middleware.RequirePermission("achievement:update"),
+		achievementSvc.CancelUpload,
+	)
+
 	api.Put(
 		"/:id",
 		middleware.RequirePermission("achievement:update"),
@@ -46,6 +107,18 @@ func AchievementRoutes(
 		achievementSvc.DeleteAchievement,
 	)
 
+	api.Post(
+		"/:id/restore",
+		// pemilik & admin, dicek oleh workflow
+		achievementSvc.RestoreAchievement,
+	)
+
+	api.Post(
+		"/:id/renew",
+		// pemilik & admin, dicek di service
+		achievementSvc.RenewAchievement,
+	)
+
 	api.Post(
 		"/:id/submit",
 		achievementSvc.SubmitAchievement,
@@ -61,9 +134,45 @@ func AchievementRoutes(
 		achievementSvc.RejectAchievement,
 	)
 
+	api.Post(
+		"/:id/revise",
+		achievementSvc.ReviseAchievement,
+	)
+
+	api.Post(
+		"/:id/withdraw",
+		achievementSvc.WithdrawAchievement,
+	)
+
+	api.Post(
+		"/:id/revoke",
+		// admin saja, dicek oleh workflow
+		achievementSvc.RevokeAchievement,
+	)
+
 	api.Get(
 		"/:id/history",
 		achievementSvc.GetAchievementHistory,
 	)
 
+	api.Get(
+		"/:id/versions",
+		achievementSvc.GetAchievementVersions,
+	)
+
+	api.Get(
+		"/:id/versions/diff",
+		achievementSvc.GetAchievementVersionDiff,
+	)
+
+	api.Get(
+		"/:id/comments",
+		achievementSvc.GetAchievementComments,
+	)
+
+	api.Post(
+		"/:id/comments",
+		achievementSvc.CreateAchievementComment,
+	)
+
 }
@@ -0,0 +1,26 @@
+package route
+
+import (
+	"uas-backend/app/repository"
+	"uas-backend/app/service"
+	"uas-backend/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AchievementTemplateRoutes(
+	r fiber.Router,
+	achievementTemplateSvc *service.AchievementTemplateService,
+	userRepo repository.UserRepository,
+) {
+
+	api := r.Group("/achievement-templates", middleware.JWTAuth(userRepo))
+
+	api.Get("/", achievementTemplateSvc.GetAchievementTemplates)
+	api.Get("/:id", achievementTemplateSvc.GetAchievementTemplateByID)
+
+	// admin & dosen wali, dicek di service
+	api.Post("/", achievementTemplateSvc.CreateAchievementTemplate)
+	api.Put("/:id", achievementTemplateSvc.UpdateAchievementTemplate)
+	api.Delete("/:id", achievementTemplateSvc.DeleteAchievementTemplate)
+}
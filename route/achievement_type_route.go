@@ -0,0 +1,20 @@
+package route
+
+import (
+	"uas-backend/app/repository"
+	"uas-backend/app/service"
+	"uas-backend/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AchievementTypeRoutes(
+	r fiber.Router,
+	achievementTypeSvc *service.AchievementTypeService,
+	userRepo repository.UserRepository,
+) {
+
+	api := r.Group("/achievement-types", middleware.JWTAuth(userRepo))
+
+	api.Get("/", achievementTypeSvc.GetAchievementTypes)
+}
@@ -0,0 +1,19 @@
+package route
+
+import (
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FileRoutes: download lewat link bertanda tangan, tanpa JWT
+// (link dibuat oleh GET /achievements/:id/attachments/:attachmentId/link)
+func FileRoutes(
+	r fiber.Router,
+	achievementSvc *service.AchievementService,
+) {
+
+	api := r.Group("/files")
+
+	api.Get("/achievements/:id/attachments/:attachmentId", achievementSvc.DownloadSignedAttachment)
+}
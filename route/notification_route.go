@@ -0,0 +1,21 @@
+package route
+
+import (
+	"uas-backend/app/repository"
+	"uas-backend/app/service"
+	"uas-backend/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NotificationRoutes(
+	r fiber.Router,
+	notificationSvc *service.NotificationService,
+	userRepo repository.UserRepository,
+) {
+
+	api := r.Group("/notifications", middleware.JWTAuth(userRepo))
+
+	api.Get("/", notificationSvc.GetNotifications)
+	api.Put("/:id/read", notificationSvc.MarkAsRead)
+}
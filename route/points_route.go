@@ -0,0 +1,26 @@
+package route
+
+import (
+	"uas-backend/app/repository"
+	"uas-backend/app/service"
+	"uas-backend/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func PointsRoutes(
+	r fiber.Router,
+	pointsSvc *service.PointsService,
+	userRepo repository.UserRepository,
+) {
+
+	api := r.Group("/points", middleware.JWTAuth(userRepo))
+
+	api.Get("/rules", pointsSvc.GetPointRules)
+
+	api.Post(
+		"/recalculate",
+		middleware.RequirePermission("user:manage"),
+		pointsSvc.RecalculatePoints,
+	)
+}
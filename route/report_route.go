@@ -21,4 +21,9 @@ func ReportRoutes(
 
 	report.Get("/statistics", reportService.GetStatistics)
 	report.Get("/student/:id", reportService.GetStudentStatistics)
+	report.Get(
+		"/storage",
+		middleware.RequirePermission("user:manage"),
+		reportService.GetStorageReport,
+	)
 }
@@ -0,0 +1,20 @@
+package route
+
+import (
+	"uas-backend/app/repository"
+	"uas-backend/app/service"
+	"uas-backend/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ReviewRoutes(
+	r fiber.Router,
+	reviewSvc *service.ReviewService,
+	userRepo repository.UserRepository,
+) {
+
+	api := r.Group("/reviews", middleware.JWTAuth(userRepo))
+
+	api.Get("/pending", reviewSvc.GetPendingReviews)
+}
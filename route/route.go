@@ -1,10 +1,15 @@
 package route
 
 import (
+	"context"
+	"log"
+
 	"github.com/gofiber/fiber/v2"
 
 	"uas-backend/app/repository"
 	"uas-backend/app/service"
+	"uas-backend/app/storage"
+	"uas-backend/config"
 	"uas-backend/database"
 )
 
@@ -19,18 +24,99 @@ func SetupRoutes(app *fiber.App) {
 	achievementRepo := repository.NewAchievementRepository(database.MongoDB)
 	achievementRefRepo := repository.NewAchievementReferenceRepository(database.PG)
 	reportRepo := repository.NewReportRepository(achievementRefRepo)
+	notificationRepo := repository.NewNotificationRepository(database.MongoDB)
+	commentRepo := repository.NewAchievementCommentRepository(database.MongoDB)
+	versionRepo := repository.NewAchievementVersionRepository(database.MongoDB)
+	templateRepo := repository.NewAchievementTemplateRepository(database.MongoDB)
+	uploadSessionRepo := repository.NewUploadSessionRepository(database.MongoDB)
+
+	// nomor versi unik per prestasi (Create mencoba ulang kalau bentrok)
+	if err := versionRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("❌ Failed to create achievement version index: %v", err)
+	}
+
+	// === INIT WORKFLOW ===
+	workflow, err := service.LoadAchievementWorkflow(config.AchievementWorkflowFile())
+	if err != nil {
+		log.Fatalf("❌ Failed to load achievement workflow: %v", err)
+	}
+
+	approvalChains, err := service.LoadApprovalChains(config.ApprovalChainsFile())
+	if err != nil {
+		log.Fatalf("❌ Failed to load approval chains: %v", err)
+	}
+
+	achievementSchemas, err := service.LoadAchievementSchemas(config.AchievementSchemasFile())
+	if err != nil {
+		log.Fatalf("❌ Failed to load achievement schemas: %v", err)
+	}
+
+	pointRules, err := service.LoadPointsRules(config.PointRulesFile())
+	if err != nil {
+		log.Fatalf("❌ Failed to load point rules: %v", err)
+	}
+
+	reviewSLAs, err := service.LoadReviewSLAs(config.ReviewSLAFile())
+	if err != nil {
+		log.Fatalf("❌ Failed to load review SLAs: %v", err)
+	}
+
+	// === INIT STORAGE LAMPIRAN ===
+	attachmentStore, err := storage.New(storage.Config{
+		Driver:   config.StorageDriver(),
+		LocalDir: config.StorageLocalDir(),
+		S3: storage.S3Config{
+			Endpoint:  config.S3Endpoint(),
+			Region:    config.S3Region(),
+			Bucket:    config.S3Bucket(),
+			AccessKey: config.S3AccessKey(),
+			SecretKey: config.S3SecretKey(),
+			PathStyle: config.S3PathStyle(),
+		},
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to init attachment storage: %v", err)
+	}
+	if config.AttachmentURLSecret() == "" {
+		log.Fatalf("❌ ATTACHMENT_URL_SECRET or JWT_SECRET must be set for attachment links")
+	}
 
 	// === INIT SERVICE ===
 	authService := service.NewAuthService(userRepo, studentRepo)
-	userService := service.NewUserService(userRepo, studentRepo, lecturerRepo)
+	userService := service.NewUserService(userRepo, studentRepo, lecturerRepo, achievementRepo)
 	studentSvc := service.NewStudentService(studentRepo, lecturerRepo, achievementRepo, achievementRefRepo)
 	lecturerSvc := service.NewLecturerService(lecturerRepo, studentRepo)
-	achievementSvc := service.NewAchievementService(
-		achievementRepo,
+	achievementSvc := service.NewAchievementService(service.AchievementDeps{
+		AchievementRepo:  achievementRepo,
+		ReferenceRepo:    achievementRefRepo,
+		StudentRepo:      studentRepo,
+		LecturerRepo:     lecturerRepo,
+		Workflow:         workflow,
+		Approvals:        approvalChains,
+		NotificationRepo: notificationRepo,
+		CommentRepo:      commentRepo,
+		Schemas:          achievementSchemas,
+		Points:           pointRules,
+		VersionRepo:      versionRepo,
+		TemplateRepo:     templateRepo,
+		Store:            attachmentStore,
+		UploadRepo:       uploadSessionRepo,
+	})
+	achievementTypeSvc := service.NewAchievementTypeService(achievementSchemas)
+	achievementTemplateSvc := service.NewAchievementTemplateService(templateRepo, achievementSchemas)
+	notificationSvc := service.NewNotificationService(notificationRepo)
+	reviewSvc := service.NewReviewService(
 		achievementRefRepo,
+		achievementRepo,
 		studentRepo,
 		lecturerRepo,
+		userRepo,
+		notificationRepo,
+		workflow,
+		approvalChains,
+		reviewSLAs,
 	)
+	pointsSvc := service.NewPointsService(pointRules, reportRepo, achievementRepo)
 	reportService := service.NewReportService(
 		reportRepo,
 		achievementRepo,
@@ -42,6 +128,51 @@ func SetupRoutes(app *fiber.App) {
 	StudentRoutes(api, studentSvc, userRepo)
 	LecturerRoutes(api, lecturerSvc, userRepo)
 	AchievementRoutes(api, achievementSvc, userRepo)
+	FileRoutes(api, achievementSvc)
+	AchievementTypeRoutes(api, achievementTypeSvc, userRepo)
+	AchievementTemplateRoutes(api, achievementTemplateSvc, userRepo)
 	ReportRoutes(api, reportService, userRepo)
+	NotificationRoutes(api, notificationSvc, userRepo)
+	PointsRoutes(api, pointsSvc, userRepo)
+	ReviewRoutes(api, reviewSvc, userRepo)
+
+	// tabel poin bisa berubah antar deploy → sinkronkan prestasi verified
+	go func() {
+		updated, err := pointsSvc.RecalculateVerified(context.Background())
+		if err != nil {
+			log.Printf("⚠️ Points recalculation failed: %v", err)
+			return
+		}
+		if updated > 0 {
+			log.Printf("✅ Recalculated points for %d achievements (rules %s)", updated, pointRules.Version())
+		}
+	}()
+
+	// lampiran lama (hanya FileURL) diberi ID & hash supaya bisa diakses lewat endpoint lampiran
+	go func() {
+		n, err := achievementSvc.BackfillLegacyAttachments(context.Background())
+		if err != nil {
+			log.Printf("⚠️ Legacy attachment backfill failed: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("✅ Backfilled %d legacy attachments", n)
+		}
+	}()
+
+	// pengingat & eskalasi review yang lewat SLA
+	go reviewSvc.StartSLAJob(context.Background(), config.ReviewSLAInterval())
+
+	// hapus permanen isi tempat sampah yang lewat masa simpan
+	go achievementSvc.StartTrashPurgeJob(context.Background(), config.TrashPurgeInterval(), config.TrashRetention())
+
+	// hapus sesi upload bertahap yang ditinggalkan beserta potongannya
+	go achievementSvc.StartUploadCleanupJob(context.Background(), config.AttachmentUploadCleanupInterval())
+
+	// thumbnail & pratinjau lampiran
+	go achievementSvc.StartPreviewWorker(context.Background(), config.AttachmentPreviewInterval())
+
+	// pengingat sertifikasi yang akan kedaluwarsa
+	go achievementSvc.StartExpiryReminderJob(context.Background(), config.CertExpiryCheckInterval(), config.CertExpiryReminderWindow())
 
 }
@@ -0,0 +1,186 @@
+package preview_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"uas-backend/app/preview"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildPDF: PDF minimal (tanpa xref; parser membaca objek langsung)
+func buildPDF(objects ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.5\n")
+	for i, obj := range objects {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	b.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return b.Bytes()
+}
+
+func stream(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func deflate(data []byte) []byte {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write(data)
+	w.Close()
+	return b.Bytes()
+}
+
+func encodeJPEG(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 200
+	}
+	var b bytes.Buffer
+	require.NoError(t, jpeg.Encode(&b, img, nil))
+	return b.Bytes()
+}
+
+func TestDecode_Image(t *testing.T) {
+	// PNG transparan 2000x1000 → thumbnail 256x128 berlatar putih
+	src := image.NewNRGBA(image.Rect(0, 0, 2000, 1000))
+	var b bytes.Buffer
+	require.NoError(t, png.Encode(&b, src))
+
+	img, err := preview.Decode(b.Bytes(), "image/png")
+	require.NoError(t, err)
+
+	thumb := preview.Fit(img, preview.ThumbnailSize)
+	assert.Equal(t, image.Rect(0, 0, 256, 128), thumb.Bounds())
+	assert.Equal(t, color.RGBAModel.Convert(color.White), thumb.At(10, 10))
+
+	// gambar kecil tidak diperbesar
+	img, err = preview.Decode(encodeJPEG(t, 100, 50), "image/jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), preview.Fit(img, preview.PreviewSize).Bounds())
+
+	out, err := preview.EncodeJPEG(thumb)
+	require.NoError(t, err)
+	_, format, err := image.DecodeConfig(bytes.NewReader(out))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+}
+
+func TestDecode_ImageTooLarge(t *testing.T) {
+	// header PNG 20000x20000 tanpa data piksel: ditolak sebelum decode penuh
+	var b bytes.Buffer
+	b.WriteString("\x89PNG\r\n\x1a\n")
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], 20000)
+	binary.BigEndian.PutUint32(ihdr[4:], 20000)
+	ihdr[8], ihdr[9] = 8, 6 // 8 bit RGBA
+	binary.Write(&b, binary.BigEndian, uint32(len(ihdr)))
+	chunk := append([]byte("IHDR"), ihdr...)
+	b.Write(chunk)
+	binary.Write(&b, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+
+	_, err := preview.Decode(b.Bytes(), "image/png")
+	assert.ErrorIs(t, err, preview.ErrTooLarge)
+}
+
+func TestDecode_PDF(t *testing.T) {
+	t.Run("JPEG on first page, resources inherited", func(t *testing.T) {
+		pdf := buildPDF(
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [3 0 R 6 0 R] /Count 2 /Resources << /XObject << /Im1 4 0 R /Im2 5 0 R >> >> >>",
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] >>",
+			stream("/Type /XObject /Subtype /Image /Width 300 /Height 200 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode", encodeJPEG(t, 300, 200)),
+			stream("/Type /XObject /Subtype /Image /Width 40 /Height 40 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode", encodeJPEG(t, 40, 40)),
+			"<< /Type /Page /Parent 2 0 R >>",
+		)
+
+		img, err := preview.Decode(pdf, "application/pdf")
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 300, 200), img.Bounds(), "gambar terbesar")
+	})
+
+	t.Run("Flate RGB image, page in object stream", func(t *testing.T) {
+		pixels := bytes.Repeat([]byte{255, 0, 0}, 4*2)
+
+		// objek 5 (page) & 6 (pages) terkompresi di object stream objek 2
+		page := "<< /Type /Page /Parent 6 0 R /Resources << /XObject << /Im0 3 0 R >> >> >>"
+		pages := "<< /Type /Pages /Kids [5 0 R] /Count 1 >>"
+		header := "5 0 6 80 "
+		body := fmt.Sprintf("%s%-80s%s", header, page, pages)
+		objStm := stream(fmt.Sprintf("/Type /ObjStm /N 2 /First %d /Filter /FlateDecode", len(header)), deflate([]byte(body)))
+
+		pdf := buildPDF(
+			"<< /Type /Catalog /Pages 6 0 R >>",
+			objStm,
+			stream("/Subtype /Image /Width 4 /Height 2 /ColorSpace [/ICCBased 4 0 R] /BitsPerComponent 8 /Filter /FlateDecode", deflate(pixels)),
+			stream("/N 3", []byte("icc")),
+		)
+
+		img, err := preview.Decode(pdf, "application/pdf")
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 4, 2), img.Bounds())
+		r, g, _, _ := img.At(1, 1).RGBA()
+		assert.Equal(t, uint32(0xffff), r)
+		assert.Equal(t, uint32(0), g)
+	})
+
+	t.Run("text only PDF", func(t *testing.T) {
+		pdf := buildPDF(
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+			"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+			stream("", []byte("BT /F1 12 Tf (Sertifikat (Juara) 1) Tj ET")),
+			"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		)
+
+		_, err := preview.Decode(pdf, "application/pdf")
+		assert.ErrorIs(t, err, preview.ErrUnsupported)
+	})
+
+	t.Run("object stream with negative offset", func(t *testing.T) {
+		header := "5 -100 "
+		objStm := stream(fmt.Sprintf("/Type /ObjStm /N 1 /First %d", len(header)), []byte(header+"<< /Type /Page >>"))
+		pdf := buildPDF("<< /Type /Catalog /Pages 5 0 R >>", objStm)
+
+		_, err := preview.Decode(pdf, "application/pdf")
+		assert.ErrorIs(t, err, preview.ErrUnsupported)
+	})
+
+	t.Run("not a PDF", func(t *testing.T) {
+		_, err := preview.Decode([]byte("%PDF-1.4 garbage"), "application/pdf")
+		assert.ErrorIs(t, err, preview.ErrUnsupported)
+	})
+}
+
+// FuzzDecode_PDF: PDF rusak dari upload mahasiswa tidak boleh membuat parser panic
+func FuzzDecode_PDF(f *testing.F) {
+	f.Add(buildPDF("<< /Type /Catalog /Pages 2 0 R >>", stream("/Type /ObjStm /N 1 /First 7", []byte("5 -100 << >>"))))
+	f.Add(buildPDF("<< /Type /Catalog /Pages 2 0 R >>", stream("/Type /ObjStm /N 2 /First 9", []byte("2 0 3 999 [1 2"))))
+	f.Add(buildPDF("<< /Type /Catalog /Pages 2 0 R >>", "<< /Type /Pages /Kids [3 0 R] >>", "<< /Type /Page /Resources << /XObject << /I 4 0 R >> >> >>",
+		stream("/Subtype /Image /Width 2 /Height 2 /ColorSpace /DeviceGray /BitsPerComponent 8", []byte("ab"))))
+	f.Add([]byte("%PDF-1.5\n1 0 obj << /Length 99999 >> stream\n"))
+	f.Add([]byte("1 0 obj (unterminated \\"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		preview.Decode(data, "application/pdf")
+	})
+}
+
+func TestSupported(t *testing.T) {
+	assert.True(t, preview.Supported("image/webp"))
+	assert.True(t, preview.Supported("application/pdf"))
+	assert.False(t, preview.Supported("application/zip"))
+
+	_, err := preview.Decode([]byte("PK"), "application/zip")
+	assert.ErrorIs(t, err, preview.ErrUnsupported)
+}
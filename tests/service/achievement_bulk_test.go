@@ -0,0 +1,154 @@
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type bulkResponseBody struct {
+	Summary map[string]int           `json:"summary"`
+	Data    []service.BulkItemResult `json:"data"`
+}
+
+/*
+=================================================
+TEST: BULK VERIFY (per-item authorization)
+=================================================
+*/
+
+func TestAchievementService_BulkVerify(t *testing.T) {
+	lecturerUserID := "lecturer-user"
+	advisee := "stu-1"
+	stranger := "stu-2"
+
+	okID := primitive.NewObjectID()
+	foreignID := primitive.NewObjectID()
+	draftID := primitive.NewObjectID()
+	missingID := primitive.NewObjectID()
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	stuRepo := new(MockStudentRepo)
+	lecRepo := new(MockLecturerRepo)
+	svc := newAchievementService(achievementDeps{
+		achRepo: achRepo,
+		refRepo: refRepo,
+		stuRepo: stuRepo,
+		lecRepo: lecRepo,
+	})
+
+	lecRepo.On("GetLecturerProfile", mock.Anything, lecturerUserID).Return(&model.Lecturer{ID: "lec-1"}, nil)
+	stuRepo.On("GetStudentsByAdvisor", mock.Anything, "lec-1").Return([]*model.Student{{ID: advisee}}, nil)
+
+	for id, ref := range map[primitive.ObjectID]*model.AchievementReference{
+		okID:      {StudentID: advisee, Status: model.StatusSubmitted},
+		foreignID: {StudentID: stranger, Status: model.StatusSubmitted},
+		draftID:   {StudentID: advisee, Status: model.StatusDraft},
+	} {
+		refRepo.On("GetByAchievementID", mock.Anything, id.Hex()).Return(ref, nil)
+		achRepo.On("GetByID", mock.Anything, id).Return(&model.Achievement{
+			ID: id, StudentID: ref.StudentID, AchievementType: "certification",
+		}, nil)
+	}
+	refRepo.On("GetByAchievementID", mock.Anything, missingID.Hex()).
+		Return((*model.AchievementReference)(nil), errors.New("no rows"))
+	refRepo.On("GetMemberIDs", mock.Anything, foreignID.Hex()).Return([]string{}, nil)
+	refRepo.On("GetApprovals", mock.Anything, okID.Hex()).Return([]model.AchievementApproval{}, nil)
+	refRepo.On("Approve", mock.Anything, okID.Hex(), mock.Anything, mock.Anything, "").
+		Return(&model.AchievementReference{StudentID: advisee, Status: model.StatusVerified}, nil)
+
+	app := fiber.New()
+	app.Post("/bulk/verify", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: lecturerUserID, Role: "Dosen Wali"})
+		return svc.BulkVerifyAchievements(c)
+	})
+
+	body := `{"ids":["` + okID.Hex() + `","` + foreignID.Hex() + `","` + draftID.Hex() + `","` +
+		missingID.Hex() + `","` + okID.Hex() + `"]}`
+	req := httptest.NewRequest("POST", "/bulk/verify", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var out bulkResponseBody
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+	results := map[string]string{}
+	for _, r := range out.Data {
+		results[r.ID] = r.Result
+	}
+	assert.Len(t, out.Data, 4) // ID ganda diproses sekali
+	assert.Equal(t, service.BulkResultOK, results[okID.Hex()])
+	assert.Equal(t, service.BulkResultForbidden, results[foreignID.Hex()])
+	assert.Equal(t, service.BulkResultWrongStatus, results[draftID.Hex()])
+	assert.Equal(t, service.BulkResultNotFound, results[missingID.Hex()])
+	assert.Equal(t, 1, out.Summary[service.BulkResultOK])
+	refRepo.AssertNumberOfCalls(t, "Approve", 1)
+
+	// anak wali diambil sekali per bulk request, bukan per item
+	lecRepo.AssertNumberOfCalls(t, "GetLecturerProfile", 1)
+	stuRepo.AssertNumberOfCalls(t, "GetStudentsByAdvisor", 1)
+}
+
+/*
+=================================================
+TEST: BULK REJECT
+=================================================
+*/
+
+func TestAchievementService_BulkReject(t *testing.T) {
+	achievementID := primitive.NewObjectID().Hex()
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedResult string
+	}{
+		{"Success", `{"ids":["` + achievementID + `"],"rejection_note":"bukti kurang"}`, fiber.StatusOK, service.BulkResultOK},
+		{"Bad request - no note", `{"ids":["` + achievementID + `"]}`, fiber.StatusBadRequest, ""},
+		{"Bad request - no ids", `{"ids":[],"rejection_note":"bukti kurang"}`, fiber.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo})
+
+			ref := &model.AchievementReference{StudentID: "stu-1", Status: model.StatusSubmitted}
+			refRepo.On("GetByAchievementID", mock.Anything, achievementID).Return(ref, nil)
+			achRepo.On("GetByID", mock.Anything, mock.Anything).Return(&model.Achievement{StudentID: "stu-1"}, nil)
+			refRepo.On("Reject", mock.Anything, achievementID, mock.Anything).
+				Return(&model.AchievementReference{StudentID: "stu-1", Status: model.StatusRejected}, nil)
+
+			app := fiber.New()
+			app.Post("/bulk/reject", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "admin-1", Role: "Admin"})
+				return svc.BulkRejectAchievements(c)
+			})
+
+			req := httptest.NewRequest("POST", "/bulk/reject", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedResult != "" {
+				var out bulkResponseBody
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+				assert.Equal(t, tt.expectedResult, out.Data[0].Result)
+			}
+		})
+	}
+}
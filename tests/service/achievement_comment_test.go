@@ -0,0 +1,186 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ====================
+// MOCK COMMENT REPO
+// ====================
+type MockCommentRepo struct{ mock.Mock }
+
+func (m *MockCommentRepo) Create(ctx context.Context, comment *model.AchievementComment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*model.AchievementComment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AchievementComment), args.Error(1)
+}
+
+func (m *MockCommentRepo) FindByAchievementID(ctx context.Context, achievementID string) ([]model.AchievementComment, error) {
+	args := m.Called(ctx, achievementID)
+	return args.Get(0).([]model.AchievementComment), args.Error(1)
+}
+
+func (m *MockCommentRepo) DeleteByAchievementID(ctx context.Context, achievementID string) error {
+	return m.Called(ctx, achievementID).Error(0)
+}
+
+/*
+=================================================
+TEST: CREATE COMMENT
+=================================================
+*/
+
+func TestAchievementService_CreateComment(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+	otherParent := primitive.NewObjectID()
+	studentID := "stu-123"
+
+	achievement := &model.Achievement{
+		ID:          achievementID,
+		StudentID:   studentID,
+		Title:       "Juara 1",
+		Attachments: []model.Attachment{{ID: "att-1", FileName: "sertifikat.pdf"}, {ID: "att-2", FileName: "sertifikat.pdf"}},
+	}
+
+	student := &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: studentID}
+	admin := &model.JWTClaims{UserID: "admin-1", Role: "Admin"}
+
+	tests := []struct {
+		name           string
+		claims         *model.JWTClaims
+		body           string
+		expectedStatus int
+	}{
+		{"Success - student comment", student, `{"body":"sudah saya perbaiki"}`, fiber.StatusCreated},
+		{"Success - field change request", admin, `{"body":"judul kurang lengkap","change_request":{"field":"title"}}`, fiber.StatusCreated},
+		{"Success - details change request", admin, `{"body":"cek peringkat","change_request":{"field":"details.rank"}}`, fiber.StatusCreated},
+		{"Success - attachment change request", admin, `{"body":"scan buram","change_request":{"attachment_id":"att-2"}}`, fiber.StatusCreated},
+		{"Bad request - empty body", student, `{"body":"  "}`, fiber.StatusBadRequest},
+		{"Bad request - unknown field", admin, `{"body":"x","change_request":{"field":"points"}}`, fiber.StatusBadRequest},
+		{"Bad request - unknown attachment", admin, `{"body":"x","change_request":{"attachment_id":"att-9"}}`, fiber.StatusBadRequest},
+		{"Bad request - attachment by file name", admin, `{"body":"x","change_request":{"attachment_id":"sertifikat.pdf"}}`, fiber.StatusBadRequest},
+		{"Bad request - both targets", admin, `{"body":"x","change_request":{"field":"title","attachment_id":"att-1"}}`, fiber.StatusBadRequest},
+		{"Bad request - parent from other achievement", admin, `{"body":"x","parent_id":"` + otherParent.Hex() + `"}`, fiber.StatusBadRequest},
+		{"Forbidden - student change request", student, `{"body":"x","change_request":{"field":"title"}}`, fiber.StatusForbidden},
+		{"Forbidden - other student", &model.JWTClaims{UserID: "user-2", Role: "Mahasiswa", StudentID: "stu-999"}, `{"body":"halo"}`, fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			notifRepo := new(MockNotificationRepo)
+			commentRepo := new(MockCommentRepo)
+			svc := newAchievementService(achievementDeps{
+				achRepo:     achRepo,
+				refRepo:     refRepo,
+				stuRepo:     stuRepo,
+				notifRepo:   notifRepo,
+				commentRepo: commentRepo,
+			})
+
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(achievement, nil)
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).
+				Return(&model.AchievementReference{StudentID: studentID, Status: model.StatusRejected, ResubmissionCount: 1}, nil)
+			refRepo.On("GetMemberIDs", mock.Anything, achievementIDHex).Return([]string{}, nil)
+			commentRepo.On("GetByID", mock.Anything, otherParent).
+				Return(&model.AchievementComment{AchievementID: primitive.NewObjectID().Hex()}, nil)
+			commentRepo.On("Create", mock.Anything, mock.MatchedBy(func(c *model.AchievementComment) bool {
+				cr := c.ChangeRequest
+				return c.Round == 1 && c.AchievementID == achievementIDHex &&
+					(cr == nil || cr.AttachmentID == "" || (cr.AttachmentID == "att-2" && cr.AttachmentName == "sertifikat.pdf"))
+			})).Return(nil)
+			stuRepo.On("GetStudentByID", mock.Anything, studentID).Return(&model.Student{ID: studentID, UserID: "user-1"}, nil)
+			notifRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			app := fiber.New()
+			app.Post("/:id/comments", func(c *fiber.Ctx) error {
+				c.Locals("user", tt.claims)
+				return svc.CreateAchievementComment(c)
+			})
+
+			req := httptest.NewRequest("POST", "/"+achievementIDHex+"/comments", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == fiber.StatusCreated {
+				commentRepo.AssertNumberOfCalls(t, "Create", 1)
+			} else {
+				commentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+/*
+=================================================
+TEST: GET COMMENTS
+=================================================
+*/
+
+func TestAchievementService_GetComments(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+	studentID := "stu-123"
+
+	tests := []struct {
+		name           string
+		claims         *model.JWTClaims
+		repoErr        error
+		expectedStatus int
+	}{
+		{"Success - owner", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: studentID}, nil, fiber.StatusOK},
+		{"Success - team member", &model.JWTClaims{UserID: "user-3", Role: "Mahasiswa", StudentID: "stu-777"}, nil, fiber.StatusOK},
+		{"Success - admin", &model.JWTClaims{UserID: "admin-1", Role: "Admin"}, nil, fiber.StatusOK},
+		{"Forbidden - other student", &model.JWTClaims{UserID: "user-2", Role: "Mahasiswa", StudentID: "stu-999"}, nil, fiber.StatusForbidden},
+		{"Error - repo failure", &model.JWTClaims{UserID: "admin-1", Role: "Admin"}, errors.New("db down"), fiber.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			commentRepo := new(MockCommentRepo)
+			svc := newAchievementService(achievementDeps{
+				achRepo:     achRepo,
+				refRepo:     refRepo,
+				commentRepo: commentRepo,
+			})
+
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{ID: achievementID, StudentID: studentID}, nil)
+			refRepo.On("GetMemberIDs", mock.Anything, achievementIDHex).Return([]string{studentID, "stu-777"}, nil)
+			commentRepo.On("FindByAchievementID", mock.Anything, achievementIDHex).
+				Return([]model.AchievementComment{{Body: "tolong lengkapi"}}, tt.repoErr)
+
+			app := fiber.New()
+			app.Get("/:id/comments", func(c *fiber.Ctx) error {
+				c.Locals("user", tt.claims)
+				return svc.GetAchievementComments(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/"+achievementIDHex+"/comments", nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package service_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+=================================================
+TEST: EVIDENCE RULES
+=================================================
+*/
+
+func TestAchievementSchemas_CheckEvidence(t *testing.T) {
+	schemas, err := service.NewAchievementSchemas(service.DefaultAchievementSchemas())
+	assert.NoError(t, err)
+
+	pdf := func(kind string) model.Attachment {
+		return model.Attachment{Kind: kind, FileType: "application/pdf"}
+	}
+
+	tests := []struct {
+		name  string
+		a     *model.Achievement
+		unmet []string
+	}{
+		{"Competition - no attachments", &model.Achievement{AchievementType: "competition"}, []string{"certificate"}},
+		{"Competition - certificate as image", &model.Achievement{AchievementType: "competition",
+			Attachments: []model.Attachment{{Kind: model.EvidenceCertificate, FileType: "image/png"}}}, []string{"certificate"}},
+		{"Competition - unlabeled pdf", &model.Achievement{AchievementType: "competition",
+			Attachments: []model.Attachment{pdf(model.EvidenceOther)}}, []string{"certificate"}},
+		{"Competition - certificate pdf", &model.Achievement{AchievementType: "competition",
+			Attachments: []model.Attachment{{Kind: model.EvidenceCertificate, FileType: "application/pdf; charset=binary"}}}, nil},
+		{"Publication - DOI only", &model.Achievement{AchievementType: "publication",
+			Details: map[string]any{"url": "https://doi.org/10.1/x"}}, nil},
+		{"Publication - PDF only", &model.Achievement{AchievementType: "publication",
+			Attachments: []model.Attachment{pdf(model.EvidencePublication)}}, nil},
+		{"Publication - nothing", &model.Achievement{AchievementType: "publication"}, []string{"publication"}},
+		{"Organization - appointment letter any format", &model.Achievement{AchievementType: "organization",
+			Attachments: []model.Attachment{{Kind: model.EvidenceAppointmentLetter, FileType: "image/jpeg"}}}, nil},
+		{"Certification - image certificate", &model.Achievement{AchievementType: "certification",
+			Attachments: []model.Attachment{{Kind: model.EvidenceCertificate, FileType: "image/jpeg"}}}, nil},
+		{"Academic - no requirements", &model.Achievement{AchievementType: "academic"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, u := range schemas.CheckEvidence(tt.a) {
+				names = append(names, u.Requirement)
+			}
+			assert.Equal(t, tt.unmet, names)
+		})
+	}
+}
+
+func TestNewAchievementSchemas_InvalidEvidence(t *testing.T) {
+	base := func(ev ...model.EvidenceRequirement) []model.AchievementTypeSchema {
+		return []model.AchievementTypeSchema{{
+			Type:     "competition",
+			Fields:   []model.DetailField{{Name: "url", Type: model.FieldURL}},
+			Evidence: ev,
+		}}
+	}
+
+	for name, schemas := range map[string][]model.AchievementTypeSchema{
+		"unknown kind":         base(model.EvidenceRequirement{Name: "x", AnyOf: []model.EvidenceOption{{Kind: "selfie"}}}),
+		"unknown detail field": base(model.EvidenceRequirement{Name: "x", AnyOf: []model.EvidenceOption{{DetailField: "doi"}}}),
+		"no options":           base(model.EvidenceRequirement{Name: "x"}),
+		"duplicate name": base(
+			model.EvidenceRequirement{Name: "x", AnyOf: []model.EvidenceOption{{DetailField: "url"}}},
+			model.EvidenceRequirement{Name: "x", AnyOf: []model.EvidenceOption{{DetailField: "url"}}},
+		),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.NewAchievementSchemas(schemas)
+			assert.Error(t, err)
+		})
+	}
+}
+
+/*
+=================================================
+TEST: SUBMIT WITHOUT EVIDENCE
+=================================================
+*/
+
+func TestAchievementService_SubmitRequiresEvidence(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo})
+
+	refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).
+		Return(&model.AchievementReference{StudentID: "stu-1", Status: model.StatusDraft}, nil)
+	achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+		ID: achievementID, StudentID: "stu-1", AchievementType: "competition",
+		Details: map[string]any{
+			"competitionName": "GEMASTIK", "competitionLevel": "national", "rank": int32(1), "eventDate": "2025-10-01",
+		},
+		Attachments: []model.Attachment{{FileName: "foto.jpg", Kind: model.EvidencePhoto, FileType: "image/jpeg"}},
+	}, nil)
+
+	app := fiber.New()
+	app.Post("/:id/submit", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+		return svc.SubmitAchievement(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/"+achievementIDHex+"/submit", nil))
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var out struct {
+		Unmet []model.UnmetEvidence `json:"unmet_requirements"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Len(t, out.Unmet, 1)
+	assert.Equal(t, "certificate", out.Unmet[0].Requirement)
+	assert.Equal(t, model.EvidenceCertificate, out.Unmet[0].AnyOf[0].Kind)
+
+	refRepo.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	achRepo.AssertNotCalled(t, "SetSubmission", mock.Anything, mock.Anything)
+}
+
+func TestAchievementService_SubmitRequiresTemplateAttachments(t *testing.T) {
+	templateID := primitive.NewObjectID()
+	certificate := model.Attachment{Kind: model.EvidenceCertificate, FileType: "application/pdf"}
+	photo := model.Attachment{Kind: model.EvidencePhoto, FileType: "image/jpeg"}
+
+	tests := []struct {
+		name           string
+		attachments    []model.Attachment
+		templateErr    error
+		expectedStatus int
+		unmet          []string
+	}{
+		{"Missing template photo", []model.Attachment{certificate}, nil, fiber.StatusBadRequest, []string{"template.photo"}},
+		{"All attachments present", []model.Attachment{certificate, photo}, nil, fiber.StatusOK, nil},
+		{"Template deleted", []model.Attachment{certificate}, mongo.ErrNoDocuments, fiber.StatusOK, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achievementID := primitive.NewObjectID()
+			achievementIDHex := achievementID.Hex()
+
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			templateRepo := new(MockTemplateRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, stuRepo: stuRepo, templateRepo: templateRepo})
+
+			ref := &model.AchievementReference{StudentID: "stu-1", Status: model.StatusDraft}
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
+			refRepo.On("Submit", mock.Anything, achievementIDHex, mock.Anything, mock.Anything).Return(ref, nil)
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: "stu-1", AchievementType: "competition", TemplateID: templateID.Hex(),
+				Details: map[string]any{
+					"competitionName": "OSN", "competitionLevel": "national", "rank": int32(1), "eventDate": "2025-10-01",
+				},
+				Attachments: tt.attachments,
+			}, nil)
+			achRepo.On("SetSubmission", mock.Anything, mock.Anything).Return(nil)
+			if tt.templateErr != nil {
+				templateRepo.On("GetByID", mock.Anything, templateID).Return(nil, tt.templateErr)
+			} else {
+				templateRepo.On("GetByID", mock.Anything, templateID).Return(&model.AchievementTemplate{
+					ID: templateID, Name: "OSN", RequiredAttachments: []string{model.EvidenceCertificate, model.EvidencePhoto},
+				}, nil)
+			}
+			allowDuplicateCheck(achRepo, stuRepo)
+
+			app := fiber.New()
+			app.Post("/:id/submit", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+				return svc.SubmitAchievement(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("POST", "/"+achievementIDHex+"/submit", nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var out struct {
+				Unmet []model.UnmetEvidence `json:"unmet_requirements"`
+			}
+			_ = json.NewDecoder(resp.Body).Decode(&out)
+			var names []string
+			for _, u := range out.Unmet {
+				names = append(names, u.Requirement)
+			}
+			assert.Equal(t, tt.unmet, names)
+			assertCalled(t, &refRepo.Mock, "Submit", tt.expectedStatus == fiber.StatusOK)
+		})
+	}
+}
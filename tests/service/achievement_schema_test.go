@@ -0,0 +1,191 @@
+package service_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/*
+=================================================
+TEST: VALIDATE DETAILS PER TYPE
+=================================================
+*/
+
+func TestAchievementSchemas_Validate(t *testing.T) {
+	schemas, err := service.LoadAchievementSchemas("")
+	assert.NoError(t, err)
+
+	validCompetition := func() map[string]any {
+		return map[string]any{
+			"competitionName":  "GEMASTIK",
+			"competitionLevel": "national",
+			"rank":             float64(1),
+			"eventDate":        "2025-10-01",
+		}
+	}
+
+	tests := []struct {
+		name           string
+		achType        string
+		details        map[string]any
+		expectedFields []string
+	}{
+		{"valid competition", "competition", validCompetition(), nil},
+		{"unknown type", "hobby", map[string]any{}, []string{"achievementType"}},
+		{"missing required", "competition", map[string]any{"competitionName": "GEMASTIK"},
+			[]string{"details.competitionLevel", "details.rank", "details.eventDate"}},
+		{"bad enum", "competition", func() map[string]any {
+			d := validCompetition()
+			d["competitionLevel"] = "galactic"
+			return d
+		}(), []string{"details.competitionLevel"}},
+		{"rank not integer", "competition", func() map[string]any {
+			d := validCompetition()
+			d["rank"] = 1.5
+			return d
+		}(), []string{"details.rank"}},
+		{"bad date", "competition", func() map[string]any {
+			d := validCompetition()
+			d["eventDate"] = "01/10/2025"
+			return d
+		}(), []string{"details.eventDate"}},
+		{"bad url", "publication", map[string]any{
+			"publicationType":  "journal",
+			"publicationTitle": "Deep Learning",
+			"authors":          []any{"A", "B"},
+			"publisher":        "IEEE",
+			"publishedDate":    "2025-01-01",
+			"url":              "not a url",
+		}, []string{"details.url"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := schemas.Validate(tt.achType, tt.details)
+
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			assert.ElementsMatch(t, tt.expectedFields, fields)
+		})
+	}
+}
+
+func TestAchievementSchemas_NormalizesAliases(t *testing.T) {
+	schemas, _ := service.LoadAchievementSchemas("")
+
+	details := map[string]any{
+		"competition_name":  "GEMASTIK",
+		"competition_level": "National",
+		"rank":              float64(2),
+		"event_date":        "2025-10-01",
+	}
+
+	errs := schemas.Validate("competition", details)
+	assert.Empty(t, errs)
+	assert.Equal(t, "national", details["competitionLevel"])
+	assert.Equal(t, "GEMASTIK", details["competitionName"])
+	assert.NotContains(t, details, "competition_level")
+}
+
+func TestAchievementSchemas_LoadFromFile(t *testing.T) {
+	schemas := []model.AchievementTypeSchema{{
+		Type:   "community",
+		Label:  "Pengabdian",
+		Fields: []model.DetailField{{Name: "location", Type: model.FieldString, Required: true}},
+	}}
+
+	raw, _ := json.Marshal(schemas)
+	path := filepath.Join(t.TempDir(), "schemas.json")
+	assert.NoError(t, os.WriteFile(path, raw, 0644))
+
+	r, err := service.LoadAchievementSchemas(path)
+	assert.NoError(t, err)
+	assert.Empty(t, r.Validate("community", map[string]any{"location": "Desa A"}))
+	assert.Len(t, r.Validate("competition", map[string]any{}), 1)
+
+	// tipe field tidak dikenal
+	schemas[0].Fields[0].Type = "color"
+	_, err = service.NewAchievementSchemas(schemas)
+	assert.Error(t, err)
+}
+
+/*
+=================================================
+TEST: SUBMIT BLOCKED BY INVALID DETAILS
+=================================================
+*/
+
+func TestAchievementService_SubmitValidatesDetails(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+	studentID := "stu-123"
+
+	tests := []struct {
+		name           string
+		details        map[string]any
+		expectedStatus int
+		normalized     bool
+	}{
+		{"Bad request - incomplete details", map[string]any{"competitionName": "GEMASTIK"}, fiber.StatusBadRequest, false},
+		{"Success - legacy spelling normalized", map[string]any{
+			"competitionName":   "GEMASTIK",
+			"competition_level": "national",
+			"rank":              int32(1),
+			"eventDate":         "2025-10-01",
+		}, fiber.StatusOK, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, stuRepo: stuRepo})
+
+			ref := &model.AchievementReference{StudentID: studentID, Status: model.StatusDraft}
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
+			refRepo.On("Submit", mock.Anything, achievementIDHex, mock.Anything, mock.Anything).Return(ref, nil)
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: studentID, AchievementType: "competition", Details: tt.details,
+				Attachments: []model.Attachment{{Kind: model.EvidenceCertificate, FileType: "application/pdf"}},
+			}, nil)
+			achRepo.On("SetSubmission", mock.Anything, mock.MatchedBy(func(a *model.Achievement) bool {
+				return a.Details["competitionLevel"] == "national"
+			})).Return(nil)
+			allowDuplicateCheck(achRepo, stuRepo)
+
+			app := fiber.New()
+			app.Post("/:id/submit", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: studentID})
+				return svc.SubmitAchievement(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("POST", "/"+achievementIDHex+"/submit", nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == fiber.StatusBadRequest {
+				body, _ := io.ReadAll(resp.Body)
+				assert.True(t, strings.Contains(string(body), "details.rank"))
+				refRepo.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.normalized {
+				achRepo.AssertNumberOfCalls(t, "SetSubmission", 1)
+			}
+		})
+	}
+}
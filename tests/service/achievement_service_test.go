@@ -4,11 +4,14 @@ package service_test
 import (
 	"context"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"uas-backend/app/model"
+	"uas-backend/app/repository"
 	"uas-backend/app/service"
 
 	"github.com/gofiber/fiber/v2"
@@ -63,12 +66,61 @@ func (m *MockAchievementRepo) Create(ctx context.Context, a *model.Achievement)
 	return args.Get(0).(primitive.ObjectID), args.Error(1)
 }
 
-func (m *MockAchievementRepo) AddAttachment(ctx context.Context, id primitive.ObjectID, att model.Attachment) error {
-	return m.Called(ctx, id, att).Error(0)
+func (m *MockAchievementRepo) AddAttachment(ctx context.Context, id primitive.ObjectID, att model.Attachment) (bool, error) {
+	args := m.Called(ctx, id, att)
+	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockAchievementRepo) RemoveAttachment(ctx context.Context, id primitive.ObjectID, attachmentID string) (bool, error) {
+	args := m.Called(ctx, id, attachmentID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAchievementRepo) ReplaceAttachment(ctx context.Context, id primitive.ObjectID, attachmentID string, att model.Attachment) (bool, error) {
+	args := m.Called(ctx, id, attachmentID, att)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAchievementRepo) SetAttachmentPreview(ctx context.Context, id primitive.ObjectID, attachmentID string, p model.AttachmentPreview) error {
+	return m.Called(ctx, id, attachmentID, p).Error(0)
+}
+
+func (m *MockAchievementRepo) FindLegacyAttachments(ctx context.Context) ([]model.Achievement, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.Achievement), args.Error(1)
+}
+
+func (m *MockAchievementRepo) BackfillAttachment(ctx context.Context, id primitive.ObjectID, legacy, att model.Attachment) (bool, error) {
+	args := m.Called(ctx, id, legacy, att)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAchievementRepo) FindPendingPreviews(ctx context.Context) ([]model.Achievement, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.Achievement), args.Error(1)
+}
+
+func (m *MockAchievementRepo) StorageByStudent(ctx context.Context, studentIDs []string, limit int64) ([]model.StorageUsage, error) {
+	args := m.Called(ctx, studentIDs, limit)
+	return args.Get(0).([]model.StorageUsage), args.Error(1)
+}
+
+func (m *MockAchievementRepo) StorageByAchievement(ctx context.Context, limit int64) ([]model.AchievementStorageUsage, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]model.AchievementStorageUsage), args.Error(1)
+}
+
+func (m *MockAchievementRepo) StorageTotal(ctx context.Context) (model.StorageUsage, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(model.StorageUsage), args.Error(1)
+}
+
+// GetByID: Return(func() *model.Achievement) untuk dokumen yang berubah selama test
 func (m *MockAchievementRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*model.Achievement, error) {
 	args := m.Called(ctx, id)
+	if fn, ok := args.Get(0).(func() *model.Achievement); ok {
+		return fn(), args.Error(1)
+	}
 	return args.Get(0).(*model.Achievement), args.Error(1)
 }
 
@@ -76,6 +128,19 @@ func (m *MockAchievementRepo) Update(ctx context.Context, a *model.Achievement)
 	return m.Called(ctx, a).Error(0)
 }
 
+func (m *MockAchievementRepo) SetSubmission(ctx context.Context, a *model.Achievement) error {
+	return m.Called(ctx, a).Error(0)
+}
+
+func (m *MockAchievementRepo) SetPoints(ctx context.Context, a *model.Achievement) error {
+	return m.Called(ctx, a).Error(0)
+}
+
+func (m *MockAchievementRepo) SetRecalculatedPoints(ctx context.Context, a *model.Achievement, fromVersion string) (bool, error) {
+	args := m.Called(ctx, a, fromVersion)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockAchievementRepo) SoftDelete(ctx context.Context, id primitive.ObjectID) error {
 	return m.Called(ctx, id).Error(0)
 }
@@ -88,10 +153,90 @@ func (m *MockAchievementRepo) FindByStudentIDs(ctx context.Context, ids []string
 	return args.Get(0).([]model.Achievement), args.Error(1)
 }
 
+func (m *MockAchievementRepo) FindByAttachmentHashes(ctx context.Context, hashes []string) ([]model.Achievement, error) {
+	args := m.Called(ctx, hashes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Achievement), args.Error(1)
+}
+
+func (m *MockAchievementRepo) FindDeleted(ctx context.Context, studentIDs []string) ([]model.Achievement, error) {
+	args := m.Called(ctx, studentIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Achievement), args.Error(1)
+}
+
+func (m *MockAchievementRepo) FindDeletedBefore(ctx context.Context, cutoff time.Time) ([]model.Achievement, error) {
+	args := m.Called(ctx, cutoff)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Achievement), args.Error(1)
+}
+
+func (m *MockAchievementRepo) Restore(ctx context.Context, id primitive.ObjectID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockAchievementRepo) HardDelete(ctx context.Context, id primitive.ObjectID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockAchievementRepo) AttachmentInUse(ctx context.Context, key string, excludeID primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, key, excludeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAchievementRepo) Remove(ctx context.Context, id primitive.ObjectID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockAchievementRepo) FindSyncStates(ctx context.Context) ([]model.Achievement, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Achievement), args.Error(1)
+}
+
+func (m *MockAchievementRepo) SyncStatus(ctx context.Context, id primitive.ObjectID, status string) error {
+	return m.Called(ctx, id, status).Error(0)
+}
+
+func (m *MockAchievementRepo) SyncMembers(ctx context.Context, id primitive.ObjectID, members []model.AchievementMember) error {
+	return m.Called(ctx, id, members).Error(0)
+}
+
+func (m *MockAchievementRepo) FindExpiring(ctx context.Context, from, to time.Time) ([]model.Achievement, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).([]model.Achievement), args.Error(1)
+}
+
+func (m *MockAchievementRepo) MarkExpiryReminded(ctx context.Context, id primitive.ObjectID, validUntil time.Time) (bool, error) {
+	args := m.Called(ctx, id, validUntil)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAchievementRepo) ClaimRenewal(ctx context.Context, id primitive.ObjectID, renewalID string) (bool, error) {
+	args := m.Called(ctx, id, renewalID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAchievementRepo) ReleaseRenewal(ctx context.Context, id primitive.ObjectID, renewalID string) error {
+	return m.Called(ctx, id, renewalID).Error(0)
+}
+
+func (m *MockAchievementRepo) ReleaseRenewalBy(ctx context.Context, renewalID string) error {
+	return m.Called(ctx, renewalID).Error(0)
+}
+
 type MockReferenceRepo struct{ mock.Mock }
 
-func (m *MockReferenceRepo) CreateDraft(ctx context.Context, studentID, achievementID string) error {
-	return m.Called(ctx, studentID, achievementID).Error(0)
+func (m *MockReferenceRepo) CreateDraft(ctx context.Context, studentID, achievementID, createdBy string) error {
+	return m.Called(ctx, studentID, achievementID, createdBy).Error(0)
 }
 
 func (m *MockReferenceRepo) GetByAchievementID(ctx context.Context, achievementID string) (*model.AchievementReference, error) {
@@ -105,25 +250,155 @@ func (m *MockReferenceRepo) GetByStudentID(ctx context.Context, studentID string
 	return args.Get(0).([]*model.AchievementReference), args.Error(1)
 }
 
-func (m *MockReferenceRepo) Submit(ctx context.Context, achievementID string) (*model.AchievementReference, error) {
-	args := m.Called(ctx, achievementID)
+func (m *MockReferenceRepo) Submit(ctx context.Context, achievementID string, change model.StatusChange, firstLevel string) (*model.AchievementReference, error) {
+	args := m.Called(ctx, achievementID, change, firstLevel)
 	return args.Get(0).(*model.AchievementReference), args.Error(1)
 }
 
-func (m *MockReferenceRepo) Verify(ctx context.Context, achievementID, userID string) (*model.AchievementReference, error) {
-	args := m.Called(ctx, achievementID, userID)
+func (m *MockReferenceRepo) Approve(ctx context.Context, achievementID string, change model.StatusChange, approval model.AchievementApproval, nextLevel string) (*model.AchievementReference, error) {
+	args := m.Called(ctx, achievementID, change, approval, nextLevel)
 	return args.Get(0).(*model.AchievementReference), args.Error(1)
 }
 
-func (m *MockReferenceRepo) Reject(ctx context.Context, achievementID, note string) (*model.AchievementReference, error) {
-	args := m.Called(ctx, achievementID, note)
+func (m *MockReferenceRepo) Reject(ctx context.Context, achievementID string, change model.StatusChange) (*model.AchievementReference, error) {
+	args := m.Called(ctx, achievementID, change)
 	return args.Get(0).(*model.AchievementReference), args.Error(1)
 }
 
-func (m *MockReferenceRepo) MarkDeleted(ctx context.Context, achievementID string) error {
+func (m *MockReferenceRepo) Revise(ctx context.Context, achievementID string, change model.StatusChange, maxResubmissions int) (*model.AchievementReference, error) {
+	args := m.Called(ctx, achievementID, change, maxResubmissions)
+	return args.Get(0).(*model.AchievementReference), args.Error(1)
+}
+
+func (m *MockReferenceRepo) Withdraw(ctx context.Context, achievementID string, change model.StatusChange) (*model.AchievementReference, error) {
+	args := m.Called(ctx, achievementID, change)
+	return args.Get(0).(*model.AchievementReference), args.Error(1)
+}
+
+func (m *MockReferenceRepo) GetApprovals(ctx context.Context, achievementID string) ([]model.AchievementApproval, error) {
+	args := m.Called(ctx, achievementID)
+	return args.Get(0).([]model.AchievementApproval), args.Error(1)
+}
+
+func (m *MockReferenceRepo) GetPending(ctx context.Context, statuses []string) ([]*model.AchievementReference, error) {
+	args := m.Called(ctx, statuses)
+	return args.Get(0).([]*model.AchievementReference), args.Error(1)
+}
+
+func (m *MockReferenceRepo) MarkOverdue(ctx context.Context, achievementID string) (bool, error) {
+	args := m.Called(ctx, achievementID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReferenceRepo) MarkEscalated(ctx context.Context, achievementID string) (bool, error) {
+	args := m.Called(ctx, achievementID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReferenceRepo) SetMembers(ctx context.Context, achievementID string, members []model.AchievementMember) error {
+	args := m.Called(ctx, achievementID, members)
+	return args.Error(0)
+}
+
+func (m *MockReferenceRepo) GetMemberIDs(ctx context.Context, achievementID string) ([]string, error) {
+	args := m.Called(ctx, achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockReferenceRepo) Purge(ctx context.Context, achievementID string) (bool, error) {
+	args := m.Called(ctx, achievementID)
+	return args.Bool(0), args.Error(1)
+}
+
+// ReserveStudentQuota: check dijalankan tanpa pesanan lain; Return(*quotaLedger)
+// untuk kunci + pesanan sungguhan di test konkurensi
+func (m *MockReferenceRepo) ReserveStudentQuota(
+	ctx context.Context,
+	res *model.QuotaReservation,
+	check func(reserved []model.QuotaReservation) (bool, error),
+) (bool, error) {
+	args := m.Called(ctx, res)
+	if ledger, ok := args.Get(0).(*quotaLedger); ok {
+		return ledger.reserve(res, check)
+	}
+	if args.Error(1) != nil {
+		return false, args.Error(1)
+	}
+	return check(nil)
+}
+
+func (m *MockReferenceRepo) ReleaseQuotaReservation(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	if ledger, ok := args.Get(0).(*quotaLedger); ok {
+		ledger.release(id)
+		return nil
+	}
+	return args.Error(0)
+}
+
+// quotaLedger: tabel pesanan kuota di memori, dicek & dicatat di bawah satu kunci
+type quotaLedger struct {
+	mu       sync.Mutex
+	nextID   int64
+	reserved []model.QuotaReservation
+}
+
+func (l *quotaLedger) reserve(res *model.QuotaReservation, check func([]model.QuotaReservation) (bool, error)) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ok, err := check(append([]model.QuotaReservation{}, l.reserved...))
+	if err != nil || !ok {
+		return false, err
+	}
+	l.nextID++
+	res.ID = l.nextID
+	l.reserved = append(l.reserved, *res)
+	return true, nil
+}
+
+func (l *quotaLedger) release(id int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reserved = slices.DeleteFunc(l.reserved, func(r model.QuotaReservation) bool { return r.ID == id })
+}
+
+func (m *MockReferenceRepo) GetAll(ctx context.Context) ([]*model.AchievementReference, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.AchievementReference), args.Error(1)
+}
+
+func (m *MockReferenceRepo) Discard(ctx context.Context, achievementID string) error {
 	return m.Called(ctx, achievementID).Error(0)
 }
 
+func (m *MockReferenceRepo) GetAllMembers(ctx context.Context) (map[string][]model.AchievementMember, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]model.AchievementMember), args.Error(1)
+}
+
+func (m *MockReferenceRepo) RecreateDeleted(ctx context.Context, studentID, mongoID, note string) error {
+	return m.Called(ctx, studentID, mongoID, note).Error(0)
+}
+
+func (m *MockReferenceRepo) GetHistory(ctx context.Context, achievementID string) ([]model.AchievementStatusHistory, error) {
+	args := m.Called(ctx, achievementID)
+	return args.Get(0).([]model.AchievementStatusHistory), args.Error(1)
+}
+
+func (m *MockReferenceRepo) UpdateStatus(ctx context.Context, achievementID string, change model.StatusChange) (*model.AchievementReference, error) {
+	args := m.Called(ctx, achievementID, change)
+	return args.Get(0).(*model.AchievementReference), args.Error(1)
+}
+
 type MockStudentRepo struct{ mock.Mock }
 
 func (m *MockStudentRepo) GetStudentProfile(ctx context.Context, userID string) (*model.Student, error) {
@@ -186,6 +461,115 @@ func (m *MockAchievementRepo) FindByIDs(ctx context.Context, ids []primitive.Obj
 */
 func ptrString(s string) *string { return &s }
 
+// ====================
+// MOCK NOTIFICATION REPO
+// ====================
+type MockNotificationRepo struct{ mock.Mock }
+
+func (m *MockNotificationRepo) Create(ctx context.Context, n *model.Notification) error {
+	args := m.Called(ctx, n)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) FindByUserID(ctx context.Context, userID string) ([]model.Notification, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]model.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepo) MarkRead(ctx context.Context, id primitive.ObjectID, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+// achievementDeps: mock untuk newAchievementService. Field nil diganti mock baru;
+// notifRepo / versionRepo / kunci kuota refRepo default selalu sukses, uploadRepo default tanpa upload
+// terbuka (dan achRepo tanpa pemakaian kuota) kalau test tidak mengisinya.
+type achievementDeps struct {
+	achRepo      *MockAchievementRepo
+	refRepo      *MockReferenceRepo
+	stuRepo      *MockStudentRepo
+	lecRepo      *MockLecturerRepo
+	notifRepo    *MockNotificationRepo
+	commentRepo  *MockCommentRepo
+	versionRepo  *MockVersionRepo
+	templateRepo *MockTemplateRepo
+	store        *MockStorage
+	uploadRepo   *MockUploadSessionRepo
+}
+
+// newAchievementService: service dengan workflow, chain verifikasi, schema & poin default
+func newAchievementService(d achievementDeps) *service.AchievementService {
+	if d.achRepo == nil {
+		d.achRepo = new(MockAchievementRepo)
+	}
+	// klaim perpanjangan dilepas saat draft dihapus: default tidak memegang klaim
+	d.achRepo.On("ReleaseRenewalBy", mock.Anything, mock.Anything).Return(nil).Maybe()
+	if d.refRepo == nil {
+		d.refRepo = new(MockReferenceRepo)
+	}
+	// pesanan kuota per mahasiswa: default tanpa pesanan lain
+	d.refRepo.On("ReserveStudentQuota", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	d.refRepo.On("ReleaseQuotaReservation", mock.Anything, mock.Anything).Return(nil).Maybe()
+	if d.stuRepo == nil {
+		d.stuRepo = new(MockStudentRepo)
+	}
+	if d.lecRepo == nil {
+		d.lecRepo = new(MockLecturerRepo)
+	}
+	if d.notifRepo == nil {
+		d.notifRepo = new(MockNotificationRepo)
+		d.notifRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	}
+	if d.commentRepo == nil {
+		d.commentRepo = new(MockCommentRepo)
+	}
+	if d.versionRepo == nil {
+		d.versionRepo = new(MockVersionRepo)
+		d.versionRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	}
+	if d.templateRepo == nil {
+		d.templateRepo = new(MockTemplateRepo)
+	}
+	if d.store == nil {
+		d.store = new(MockStorage)
+	}
+	if d.uploadRepo == nil {
+		// kuota: belum ada pemakaian & upload bertahap
+		d.achRepo.On("StorageByStudent", mock.Anything, mock.Anything, mock.Anything).Return([]model.StorageUsage{}, nil)
+		d.uploadRepo = new(MockUploadSessionRepo)
+		d.uploadRepo.On("FindOpenByStudent", mock.Anything, mock.Anything, mock.Anything).Return([]model.UploadSession{}, nil)
+	}
+
+	workflow, _ := service.NewAchievementWorkflow(service.DefaultWorkflowDefinition())
+	approvals, _ := service.NewApprovalChains(service.DefaultApprovalChains())
+	schemas, _ := service.NewAchievementSchemas(service.DefaultAchievementSchemas())
+	points, _ := service.NewPointsRules(service.DefaultPointRules())
+	return service.NewAchievementService(service.AchievementDeps{
+		AchievementRepo:  d.achRepo,
+		ReferenceRepo:    d.refRepo,
+		StudentRepo:      d.stuRepo,
+		LecturerRepo:     d.lecRepo,
+		Workflow:         workflow,
+		Approvals:        approvals,
+		NotificationRepo: d.notifRepo,
+		CommentRepo:      d.commentRepo,
+		Schemas:          schemas,
+		Points:           points,
+		VersionRepo:      d.versionRepo,
+		TemplateRepo:     d.templateRepo,
+		Store:            d.store,
+		UploadRepo:       d.uploadRepo,
+	})
+}
+
+// allowDuplicateCheck: cek duplikat tanpa kandidat. Dipanggil SETELAH
+// expectation spesifik supaya tidak menutupi expectation tersebut.
+func allowDuplicateCheck(achRepo *MockAchievementRepo, stuRepo *MockStudentRepo) {
+	stuRepo.On("GetStudentByID", mock.Anything, mock.Anything).Return(&model.Student{}, nil)
+	achRepo.On("FindByStudentIDs", mock.Anything, mock.Anything).Return([]model.Achievement{}, nil)
+	achRepo.On("FindByAttachmentHashes", mock.Anything, mock.Anything).Return([]model.Achievement{}, nil)
+}
+
 /* ====================
    UNIT TESTS
 ==================== */
@@ -207,7 +591,12 @@ func TestAchievementService_All(t *testing.T) {
 	lecRepo.On("GetLecturerByID", mock.Anything, mock.Anything).Return(&model.Lecturer{}, nil)
 	refRepo.On("GetByStudentID", mock.Anything, mock.Anything).Return([]*model.AchievementReference{}, nil)
 
-	service := service.NewAchievementService(achRepo, refRepo, stuRepo, lecRepo)
+	service := newAchievementService(achievementDeps{
+		achRepo: achRepo,
+		refRepo: refRepo,
+		stuRepo: stuRepo,
+		lecRepo: lecRepo,
+	})
 
 	// Common IDs
 	achievementID := primitive.NewObjectID()
@@ -225,7 +614,7 @@ func TestAchievementService_All(t *testing.T) {
 
 		stuRepo.On("GetStudentProfile", mock.Anything, userID).Return(student, nil)
 		achRepo.On("Create", mock.Anything, mock.Anything).Return(achievementID, nil)
-		refRepo.On("CreateDraft", mock.Anything, studentID, achievementID.Hex()).Return(nil)
+		refRepo.On("CreateDraft", mock.Anything, studentID, achievementID.Hex(), userID).Return(nil)
 
 		app := fiber.New()
 		app.Post("/achievements", func(c *fiber.Ctx) error {
@@ -247,7 +636,7 @@ func TestAchievementService_All(t *testing.T) {
 		ref := &model.AchievementReference{StudentID: studentID, Status: "draft"}
 
 		refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
-		achRepo.On("AddAttachment", mock.Anything, achievementID, mock.Anything).Return(nil)
+		achRepo.On("AddAttachment", mock.Anything, achievementID, mock.Anything).Return(true, nil)
 
 		app := fiber.New()
 		app.Post("/:id/attachment", func(c *fiber.Ctx) error {
@@ -316,7 +705,7 @@ func TestAchievementService_All(t *testing.T) {
 		ref := &model.AchievementReference{StudentID: studentID, Status: "draft"}
 
 		refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
-		refRepo.On("Submit", mock.Anything, achievementIDHex).Return(ref, nil)
+		refRepo.On("Submit", mock.Anything, achievementIDHex, mock.Anything, mock.Anything).Return(ref, nil)
 
 		app := fiber.New()
 		app.Post("/:id/submit", func(c *fiber.Ctx) error {
@@ -338,7 +727,8 @@ func TestAchievementService_All(t *testing.T) {
 		ref := &model.AchievementReference{Status: "submitted"}
 
 		refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
-		refRepo.On("Verify", mock.Anything, achievementIDHex, adminID).Return(ref, nil)
+		refRepo.On("GetApprovals", mock.Anything, achievementIDHex).Return([]model.AchievementApproval{}, nil)
+		refRepo.On("Approve", mock.Anything, achievementIDHex, mock.Anything, mock.Anything, mock.Anything).Return(ref, nil)
 
 		app := fiber.New()
 		app.Post("/:id/verify", func(c *fiber.Ctx) error {
@@ -391,6 +781,10 @@ func TestAchievementService_All(t *testing.T) {
 
 		refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
 		achRepo.On("GetByID", mock.Anything, achievementID).Return(ach, nil)
+		refRepo.On("GetHistory", mock.Anything, achievementIDHex).Return([]model.AchievementStatusHistory{
+			{Status: model.StatusDraft, UpdatedAt: now},
+			{Status: model.StatusSubmitted, UpdatedAt: now},
+		}, nil)
 
 		app := fiber.New()
 		app.Get("/:id/history", func(c *fiber.Ctx) error {
@@ -413,7 +807,7 @@ func TestAchievementService_All(t *testing.T) {
 
 		refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
 		achRepo.On("SoftDelete", mock.Anything, achievementID).Return(nil)
-		refRepo.On("MarkDeleted", mock.Anything, achievementIDHex).Return(nil)
+		refRepo.On("UpdateStatus", mock.Anything, achievementIDHex, mock.Anything).Return(ref, nil)
 
 		app := fiber.New()
 		app.Delete("/:id", func(c *fiber.Ctx) error {
@@ -427,3 +821,183 @@ func TestAchievementService_All(t *testing.T) {
 		assert.NotNil(t, resp)
 	})
 }
+
+/* ====================
+   REVISE (rejected → draft)
+==================== */
+
+func TestAchievementService_Revise(t *testing.T) {
+	achievementIDHex := primitive.NewObjectID().Hex()
+	studentID := "stu-123"
+	note := "sertifikat buram"
+
+	tests := []struct {
+		name           string
+		ref            *model.AchievementReference
+		expectedStatus int
+	}{
+		{
+			name:           "Success - rejected",
+			ref:            &model.AchievementReference{StudentID: studentID, Status: model.StatusRejected, RejectionNote: &note},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "Conflict - still submitted",
+			ref:            &model.AchievementReference{StudentID: studentID, Status: model.StatusSubmitted},
+			expectedStatus: fiber.StatusConflict,
+		},
+		{
+			name:           "Conflict - resubmission limit reached",
+			ref:            &model.AchievementReference{StudentID: studentID, Status: model.StatusRejected, ResubmissionCount: 3},
+			expectedStatus: fiber.StatusConflict,
+		},
+		{
+			name:           "Forbidden - other student",
+			ref:            &model.AchievementReference{StudentID: "stu-other", Status: model.StatusRejected},
+			expectedStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refRepo := new(MockReferenceRepo)
+			svc := newAchievementService(achievementDeps{refRepo: refRepo})
+
+			revised := *tt.ref
+			revised.Status = model.StatusDraft
+			revised.ResubmissionCount++
+
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(tt.ref, nil)
+			refRepo.On("Revise", mock.Anything, achievementIDHex, mock.Anything, 3).Return(&revised, nil)
+
+			app := fiber.New()
+			app.Post("/:id/revise", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: studentID})
+				return svc.ReviseAchievement(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("POST", "/"+achievementIDHex+"/revise", nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == fiber.StatusOK {
+				refRepo.AssertCalled(t, "Revise", mock.Anything, achievementIDHex, mock.Anything, 3)
+			} else {
+				refRepo.AssertNotCalled(t, "Revise", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+/* ====================
+   WITHDRAW (submitted → draft)
+==================== */
+
+func TestAchievementService_Withdraw(t *testing.T) {
+	achievementIDHex := primitive.NewObjectID().Hex()
+	studentID := "stu-123"
+
+	tests := []struct {
+		name           string
+		status         string
+		repoErr        error
+		expectedStatus int
+	}{
+		{"Success - submitted", model.StatusSubmitted, nil, fiber.StatusOK},
+		{"Conflict - reviewer acted first", model.StatusSubmitted, repository.ErrStatusConflict, fiber.StatusConflict},
+		{"Conflict - still draft", model.StatusDraft, nil, fiber.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refRepo := new(MockReferenceRepo)
+			svc := newAchievementService(achievementDeps{refRepo: refRepo})
+
+			ref := &model.AchievementReference{StudentID: studentID, Status: tt.status}
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
+
+			var withdrawn *model.AchievementReference
+			if tt.repoErr == nil {
+				withdrawn = &model.AchievementReference{StudentID: studentID, Status: model.StatusDraft}
+			}
+			refRepo.On("Withdraw", mock.Anything, achievementIDHex, mock.Anything).Return(withdrawn, tt.repoErr)
+
+			app := fiber.New()
+			app.Post("/:id/withdraw", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: studentID})
+				return svc.WithdrawAchievement(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("POST", "/"+achievementIDHex+"/withdraw", nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+/*
+=================================================
+TEST: REVOKE VERIFIED ACHIEVEMENT
+=================================================
+*/
+
+func TestAchievementService_Revoke(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+	studentID := "stu-123"
+
+	tests := []struct {
+		name           string
+		role           string
+		status         string
+		body           string
+		expectedStatus int
+		notified       bool
+	}{
+		{"Success - admin revokes verified", "Admin", model.StatusVerified, `{"reason":"sertifikat palsu"}`, fiber.StatusOK, true},
+		{"Bad request - empty reason", "Admin", model.StatusVerified, `{"reason":"  "}`, fiber.StatusBadRequest, false},
+		{"Forbidden - dosen wali", "Dosen Wali", model.StatusVerified, `{"reason":"sertifikat palsu"}`, fiber.StatusForbidden, false},
+		{"Conflict - not verified", "Admin", model.StatusSubmitted, `{"reason":"sertifikat palsu"}`, fiber.StatusConflict, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			notifRepo := new(MockNotificationRepo)
+			svc := newAchievementService(achievementDeps{
+				achRepo:   achRepo,
+				refRepo:   refRepo,
+				stuRepo:   stuRepo,
+				notifRepo: notifRepo,
+			})
+
+			ref := &model.AchievementReference{StudentID: studentID, Status: tt.status}
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
+			refRepo.On("UpdateStatus", mock.Anything, achievementIDHex, mock.MatchedBy(func(ch model.StatusChange) bool {
+				return ch.To == model.StatusRevoked && ch.Note != nil && *ch.Note == "sertifikat palsu"
+			})).Return(&model.AchievementReference{StudentID: studentID, Status: model.StatusRevoked}, nil)
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{StudentID: studentID, Title: "Juara 1"}, nil)
+			stuRepo.On("GetStudentByID", mock.Anything, studentID).Return(&model.Student{ID: studentID, UserID: "user-stu"}, nil)
+			notifRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *model.Notification) bool {
+				return n.UserID == "user-stu" && n.Type == model.NotificationAchievementRevoked
+			})).Return(nil)
+
+			app := fiber.New()
+			app.Post("/:id/revoke", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "admin-1", Role: tt.role})
+				return svc.RevokeAchievement(c)
+			})
+
+			req := httptest.NewRequest("POST", "/"+achievementIDHex+"/revoke", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.notified {
+				notifRepo.AssertNumberOfCalls(t, "Create", 1)
+			} else {
+				notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
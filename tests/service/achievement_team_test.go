@@ -0,0 +1,246 @@
+package service_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/*
+=================================================
+TEST: CREATE TEAM ACHIEVEMENT
+=================================================
+*/
+
+func TestAchievementService_CreateTeamAchievement(t *testing.T) {
+	owner := "stu-1"
+	claims := &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: owner}
+
+	tests := []struct {
+		name            string
+		members         string
+		expectedStatus  int
+		expectedMembers []model.AchievementMember
+	}{
+		{"Success - owner becomes leader", `[{"student_id":"stu-2"}]`, fiber.StatusCreated, []model.AchievementMember{
+			{StudentID: owner, Role: model.MemberRoleLeader},
+			{StudentID: "stu-2", Role: model.MemberRoleMember},
+		}},
+		{"Success - other member leads", `[{"student_id":"stu-2","role":"leader"}]`, fiber.StatusCreated, []model.AchievementMember{
+			{StudentID: owner, Role: model.MemberRoleMember},
+			{StudentID: "stu-2", Role: model.MemberRoleLeader},
+		}},
+		{"Success - only owner is an individual achievement", `[{"student_id":"stu-1"}]`, fiber.StatusCreated, nil},
+		{"Bad request - two leaders", `[{"student_id":"stu-1","role":"leader"},{"student_id":"stu-2","role":"leader"}]`, fiber.StatusBadRequest, nil},
+		{"Bad request - duplicate member", `[{"student_id":"stu-2"},{"student_id":"stu-2"}]`, fiber.StatusBadRequest, nil},
+		{"Bad request - invalid role", `[{"student_id":"stu-2","role":"coach"}]`, fiber.StatusBadRequest, nil},
+		{"Bad request - unknown student", `[{"student_id":"stu-404"}]`, fiber.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, stuRepo: stuRepo})
+
+			oid := primitive.NewObjectID()
+			stuRepo.On("GetStudentProfile", mock.Anything, "user-1").Return(&model.Student{ID: owner}, nil)
+			stuRepo.On("GetStudentByID", mock.Anything, "stu-2").Return(&model.Student{ID: "stu-2"}, nil)
+			stuRepo.On("GetStudentByID", mock.Anything, "stu-404").Return((*model.Student)(nil), errors.New("no rows"))
+			achRepo.On("Create", mock.Anything, mock.Anything).Return(oid, nil)
+			refRepo.On("CreateDraft", mock.Anything, owner, oid.Hex(), "user-1").Return(nil)
+			refRepo.On("SetMembers", mock.Anything, oid.Hex(), mock.Anything).Return(nil)
+			allowDuplicateCheck(achRepo, stuRepo)
+
+			app := fiber.New()
+			app.Post("/achievements", func(c *fiber.Ctx) error {
+				c.Locals("user", claims)
+				return svc.CreateAchievement(c)
+			})
+
+			body := `{"achievementType":"academic","title":"Tim","details":{"activityName":"Hackathon","date":"2026-05-01"},"members":` + tt.members + `}`
+			req := httptest.NewRequest("POST", "/achievements", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != fiber.StatusCreated {
+				achRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+
+			achRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(a *model.Achievement) bool {
+				return a.StudentID == owner && assert.ObjectsAreEqual(tt.expectedMembers, a.Members)
+			}))
+			if tt.expectedMembers == nil {
+				refRepo.AssertNotCalled(t, "SetMembers", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				refRepo.AssertCalled(t, "SetMembers", mock.Anything, oid.Hex(), tt.expectedMembers)
+			}
+		})
+	}
+}
+
+/*
+=================================================
+TEST: TEAM ACHIEVEMENT ACCESS
+=================================================
+*/
+
+func TestAchievementService_TeamAchievementAccess(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+	owner := "stu-1"
+
+	tests := []struct {
+		name           string
+		claims         *model.JWTClaims
+		advisees       []*model.Student
+		expectedStatus int
+	}{
+		{"Success - owner", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: owner}, nil, fiber.StatusOK},
+		{"Success - member", &model.JWTClaims{UserID: "user-2", Role: "Mahasiswa", StudentID: "stu-2"}, nil, fiber.StatusOK},
+		{"Success - advisor of owner", &model.JWTClaims{UserID: "lec-user", Role: "Dosen Wali"}, []*model.Student{{ID: owner}}, fiber.StatusOK},
+		{"Success - advisor of member", &model.JWTClaims{UserID: "lec-user", Role: "Dosen Wali"}, []*model.Student{{ID: "stu-2"}}, fiber.StatusOK},
+		{"Forbidden - other student", &model.JWTClaims{UserID: "user-9", Role: "Mahasiswa", StudentID: "stu-9"}, nil, fiber.StatusForbidden},
+		{"Forbidden - advisor of neither", &model.JWTClaims{UserID: "lec-user", Role: "Dosen Wali"}, []*model.Student{{ID: "stu-9"}}, fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			lecRepo := new(MockLecturerRepo)
+			svc := newAchievementService(achievementDeps{
+				achRepo: achRepo,
+				refRepo: refRepo,
+				stuRepo: stuRepo,
+				lecRepo: lecRepo,
+			})
+
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID:        achievementID,
+				StudentID: owner,
+				Members: []model.AchievementMember{
+					{StudentID: owner, Role: model.MemberRoleLeader},
+					{StudentID: "stu-2", Role: model.MemberRoleMember},
+				},
+			}, nil)
+			refRepo.On("GetMemberIDs", mock.Anything, achievementIDHex).Return([]string{owner, "stu-2"}, nil)
+			lecRepo.On("GetLecturerProfile", mock.Anything, "lec-user").Return(&model.Lecturer{ID: "lec-1"}, nil)
+			stuRepo.On("GetStudentsByAdvisor", mock.Anything, "lec-1").Return(tt.advisees, nil)
+			allowDuplicateCheck(achRepo, stuRepo)
+
+			app := fiber.New()
+			app.Get("/:id", func(c *fiber.Ctx) error {
+				c.Locals("user", tt.claims)
+				return svc.GetAchievementByID(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/"+achievementIDHex, nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestAchievementService_UpdateTeamToIndividual(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo})
+
+	refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).
+		Return(&model.AchievementReference{MongoAchievementID: achievementIDHex, StudentID: "stu-1", Status: model.StatusDraft}, nil)
+	achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+		ID: achievementID, StudentID: "stu-1", AchievementType: "academic",
+		Members: []model.AchievementMember{
+			{StudentID: "stu-1", Role: model.MemberRoleLeader},
+			{StudentID: "stu-2", Role: model.MemberRoleMember},
+		},
+	}, nil)
+	refRepo.On("SetMembers", mock.Anything, achievementIDHex, mock.Anything).Return(nil)
+	achRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Put("/:id", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+		c.Locals("permissions", []string{"achievement:update"})
+		return svc.UpdateAchievement(c)
+	})
+
+	// hanya pemilik tersisa → prestasi individu
+	req := httptest.NewRequest("PUT", "/"+achievementIDHex, strings.NewReader(`{"members":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	// anggota lama dihapus di kedua store, bukan dibiarkan
+	refRepo.AssertCalled(t, "SetMembers", mock.Anything, achievementIDHex, mock.MatchedBy(func(m []model.AchievementMember) bool {
+		return len(m) == 0
+	}))
+	achRepo.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(a *model.Achievement) bool {
+		return len(a.Members) == 0
+	}))
+}
+
+/*
+=================================================
+TEST: TEAM NOTIFICATIONS
+=================================================
+*/
+
+func TestAchievementService_RevokeNotifiesTeam(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	stuRepo := new(MockStudentRepo)
+	notifRepo := new(MockNotificationRepo)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, stuRepo: stuRepo, notifRepo: notifRepo})
+
+	refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).
+		Return(&model.AchievementReference{StudentID: "stu-1", Status: model.StatusVerified}, nil)
+	refRepo.On("UpdateStatus", mock.Anything, achievementIDHex, mock.Anything).
+		Return(&model.AchievementReference{StudentID: "stu-1", Status: model.StatusRevoked}, nil)
+	achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+		ID: achievementID, StudentID: "stu-1", Title: "Juara 1 Hackathon",
+		Members: []model.AchievementMember{
+			{StudentID: "stu-1", Role: model.MemberRoleLeader},
+			{StudentID: "stu-2", Role: model.MemberRoleMember},
+		},
+	}, nil)
+	stuRepo.On("GetStudentByID", mock.Anything, "stu-1").Return(&model.Student{ID: "stu-1", UserID: "user-1"}, nil)
+	stuRepo.On("GetStudentByID", mock.Anything, "stu-2").Return(&model.Student{ID: "stu-2", UserID: "user-2"}, nil)
+	notifRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/:id/revoke", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "admin-1", Role: "Admin"})
+		return svc.RevokeAchievement(c)
+	})
+
+	req := httptest.NewRequest("POST", "/"+achievementIDHex+"/revoke", strings.NewReader(`{"reason":"sertifikat palsu"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	// pemilik + anggota tim sama-sama dikabari
+	for _, userID := range []string{"user-1", "user-2"} {
+		notifRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(n *model.Notification) bool {
+			return n.UserID == userID && n.Type == model.NotificationAchievementRevoked
+		}))
+	}
+}
@@ -0,0 +1,282 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockTemplateRepo struct{ mock.Mock }
+
+func (m *MockTemplateRepo) Create(ctx context.Context, t *model.AchievementTemplate) error {
+	return m.Called(ctx, t).Error(0)
+}
+
+func (m *MockTemplateRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*model.AchievementTemplate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AchievementTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepo) FindAll(ctx context.Context, achievementType string, activeOnly bool) ([]model.AchievementTemplate, error) {
+	args := m.Called(ctx, achievementType, activeOnly)
+	return args.Get(0).([]model.AchievementTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepo) Update(ctx context.Context, t *model.AchievementTemplate) error {
+	return m.Called(ctx, t).Error(0)
+}
+
+func (m *MockTemplateRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func newTemplateService(templateRepo *MockTemplateRepo) *service.AchievementTemplateService {
+	schemas, _ := service.NewAchievementSchemas(service.DefaultAchievementSchemas())
+	return service.NewAchievementTemplateService(templateRepo, schemas)
+}
+
+/*
+=================================================
+TEST: MANAGE TEMPLATES
+=================================================
+*/
+
+func TestAchievementTemplateService_CreateTemplate(t *testing.T) {
+	admin := &model.JWTClaims{UserID: "admin-1", Role: "Admin"}
+
+	tests := []struct {
+		name           string
+		claims         *model.JWTClaims
+		body           string
+		expectedStatus int
+	}{
+		{"Success - partial details", admin,
+			`{"name":"OSN","achievementType":"competition","title_pattern":"Juara {rank} {competitionName}","details":{"competitionName":"OSN","level":"National"}}`,
+			fiber.StatusCreated},
+		{"Success - lecturer", &model.JWTClaims{UserID: "lec-1", Role: "Dosen Wali"},
+			`{"name":"OSN","achievementType":"competition","title_pattern":"OSN"}`, fiber.StatusCreated},
+		{"Forbidden - student", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa"},
+			`{"name":"OSN","achievementType":"competition","title_pattern":"OSN"}`, fiber.StatusForbidden},
+		{"Bad request - unknown type", admin,
+			`{"name":"OSN","achievementType":"sports","title_pattern":"OSN"}`, fiber.StatusBadRequest},
+		{"Bad request - unknown placeholder", admin,
+			`{"name":"OSN","achievementType":"competition","title_pattern":"Juara {juara}"}`, fiber.StatusBadRequest},
+		{"Success - required attachments", admin,
+			`{"name":"OSN","achievementType":"competition","title_pattern":"OSN","required_attachments":["certificate","photo"]}`, fiber.StatusCreated},
+		{"Bad request - unknown required attachment kind", admin,
+			`{"name":"OSN","achievementType":"competition","title_pattern":"OSN","required_attachments":["letter"]}`, fiber.StatusBadRequest},
+		{"Bad request - invalid detail value", admin,
+			`{"name":"OSN","achievementType":"competition","title_pattern":"OSN","details":{"competitionLevel":"galactic"}}`, fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateRepo := new(MockTemplateRepo)
+			svc := newTemplateService(templateRepo)
+			templateRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			app := fiber.New()
+			app.Post("/", func(c *fiber.Ctx) error {
+				c.Locals("user", tt.claims)
+				return svc.CreateAchievementTemplate(c)
+			})
+
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != fiber.StatusCreated {
+				templateRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			templateRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(tpl *model.AchievementTemplate) bool {
+				return tpl.IsActive && tpl.CreatedBy == tt.claims.UserID && tpl.Tags != nil
+			}))
+		})
+	}
+
+	// alias & enum dinormalisasi sebelum disimpan
+	templateRepo := new(MockTemplateRepo)
+	templateRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		c.Locals("user", admin)
+		return newTemplateService(templateRepo).CreateAchievementTemplate(c)
+	})
+	req := httptest.NewRequest("POST", "/", strings.NewReader(tests[0].body))
+	req.Header.Set("Content-Type", "application/json")
+	_, _ = app.Test(req)
+	templateRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(tpl *model.AchievementTemplate) bool {
+		return tpl.Details["competitionLevel"] == "national" && tpl.Details["level"] == nil
+	}))
+}
+
+func TestAchievementTemplateService_UpdateTemplate_OwnerOnly(t *testing.T) {
+	id := primitive.NewObjectID()
+	body := `{"name":"OSN 2027","achievementType":"competition","title_pattern":"OSN"}`
+
+	tests := []struct {
+		name           string
+		claims         *model.JWTClaims
+		expectedStatus int
+	}{
+		{"Success - creator", &model.JWTClaims{UserID: "lec-1", Role: "Dosen Wali"}, fiber.StatusOK},
+		{"Success - admin", &model.JWTClaims{UserID: "admin-1", Role: "Admin"}, fiber.StatusOK},
+		{"Forbidden - other lecturer", &model.JWTClaims{UserID: "lec-2", Role: "Dosen Wali"}, fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateRepo := new(MockTemplateRepo)
+			svc := newTemplateService(templateRepo)
+			templateRepo.On("GetByID", mock.Anything, id).Return(&model.AchievementTemplate{ID: id, CreatedBy: "lec-1"}, nil)
+			templateRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+			app := fiber.New()
+			app.Put("/:id", func(c *fiber.Ctx) error {
+				c.Locals("user", tt.claims)
+				return svc.UpdateAchievementTemplate(c)
+			})
+
+			req := httptest.NewRequest("PUT", "/"+id.Hex(), strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+/*
+=================================================
+TEST: CREATE ACHIEVEMENT FROM TEMPLATE
+=================================================
+*/
+
+func TestAchievementService_CreateFromTemplate(t *testing.T) {
+	templateID := primitive.NewObjectID()
+	template := &model.AchievementTemplate{
+		ID:              templateID,
+		AchievementType: "competition",
+		TitlePattern:    "Juara {rank} {competitionName}",
+		Description:     "Olimpiade Sains Nasional",
+		Details: map[string]any{
+			"competitionName":  "OSN",
+			"competitionLevel": "national",
+			"organizer":        "Puspresnas",
+		},
+		Tags:                []string{"osn"},
+		RequiredAttachments: []string{"Sertifikat"},
+		IsActive:            true,
+	}
+
+	tests := []struct {
+		name           string
+		templateID     string
+		body           string
+		inactive       bool
+		expectedStatus int
+		expectedTitle  string
+	}{
+		{"Success - title from pattern", templateID.Hex(),
+			`{"details":{"rank":1,"eventDate":"2026-05-01"},"tags":["sains"]}`, false, fiber.StatusCreated, "Juara 1 OSN"},
+		{"Success - own title", templateID.Hex(),
+			`{"title":"Medali Emas OSN","details":{"rank":1,"eventDate":"2026-05-01"}}`, false, fiber.StatusCreated, "Medali Emas OSN"},
+		{"Bad request - overriding template details", templateID.Hex(),
+			`{"details":{"competitionName":"KSN","rank":1,"eventDate":"2026-05-01"}}`, false, fiber.StatusBadRequest, ""},
+		{"Bad request - different type", templateID.Hex(),
+			`{"achievementType":"academic","details":{"rank":1,"eventDate":"2026-05-01"}}`, false, fiber.StatusBadRequest, ""},
+		{"Bad request - personal fields missing", templateID.Hex(),
+			`{"details":{}}`, false, fiber.StatusBadRequest, ""},
+		{"Not found - inactive template", templateID.Hex(),
+			`{"details":{"rank":1,"eventDate":"2026-05-01"}}`, true, fiber.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			templateRepo := new(MockTemplateRepo)
+			versionRepo := new(MockVersionRepo)
+			versionRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			svc := newAchievementService(achievementDeps{
+				achRepo:      achRepo,
+				refRepo:      refRepo,
+				stuRepo:      stuRepo,
+				versionRepo:  versionRepo,
+				templateRepo: templateRepo,
+			})
+
+			tpl := *template
+			tpl.Details = map[string]any{}
+			for k, v := range template.Details {
+				tpl.Details[k] = v
+			}
+			tpl.IsActive = !tt.inactive
+
+			oid := primitive.NewObjectID()
+			stuRepo.On("GetStudentProfile", mock.Anything, "user-1").Return(&model.Student{ID: "stu-1"}, nil)
+			templateRepo.On("GetByID", mock.Anything, templateID).Return(&tpl, nil)
+			achRepo.On("Create", mock.Anything, mock.Anything).Return(oid, nil)
+			refRepo.On("CreateDraft", mock.Anything, "stu-1", oid.Hex(), "user-1").Return(nil)
+			allowDuplicateCheck(achRepo, stuRepo)
+
+			app := fiber.New()
+			app.Post("/achievements", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+				return svc.CreateAchievement(c)
+			})
+
+			body := `{"templateId":"` + tt.templateID + `",` + strings.TrimPrefix(tt.body, "{")
+			req := httptest.NewRequest("POST", "/achievements", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != fiber.StatusCreated {
+				achRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+
+			achRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(a *model.Achievement) bool {
+				return a.Title == tt.expectedTitle &&
+					a.AchievementType == "competition" &&
+					a.TemplateID == templateID.Hex() &&
+					a.Description == "Olimpiade Sains Nasional" &&
+					a.Details["organizer"] == "Puspresnas" &&
+					a.Tags[0] == "osn"
+			}))
+		})
+	}
+
+	// template tidak ada
+	templateRepo := new(MockTemplateRepo)
+	stuRepo := new(MockStudentRepo)
+	stuRepo.On("GetStudentProfile", mock.Anything, "user-1").Return(&model.Student{ID: "stu-1"}, nil)
+	templateRepo.On("GetByID", mock.Anything, mock.Anything).Return(nil, errors.New("no documents"))
+	svc := newAchievementService(achievementDeps{stuRepo: stuRepo, templateRepo: templateRepo})
+	app := fiber.New()
+	app.Post("/achievements", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+		return svc.CreateAchievement(c)
+	})
+	req := httptest.NewRequest("POST", "/achievements", strings.NewReader(`{"templateId":"`+primitive.NewObjectID().Hex()+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uas-backend/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/*
+=================================================
+TEST: RESTORE
+=================================================
+*/
+
+func TestAchievementService_RestoreAchievement(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+	studentID := "stu-1"
+
+	tests := []struct {
+		name           string
+		claims         *model.JWTClaims
+		status         string
+		expectedStatus int
+	}{
+		{"Success - owner", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: studentID}, model.StatusDeleted, fiber.StatusOK},
+		{"Success - admin", &model.JWTClaims{UserID: "admin-1", Role: "Admin"}, model.StatusDeleted, fiber.StatusOK},
+		{"Forbidden - other student", &model.JWTClaims{UserID: "user-9", Role: "Mahasiswa", StudentID: "stu-9"}, model.StatusDeleted, fiber.StatusForbidden},
+		{"Conflict - not deleted", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: studentID}, model.StatusDraft, fiber.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo})
+
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).
+				Return(&model.AchievementReference{MongoAchievementID: achievementIDHex, StudentID: studentID, Status: tt.status}, nil)
+			refRepo.On("GetMemberIDs", mock.Anything, achievementIDHex).Return([]string{}, nil)
+			refRepo.On("UpdateStatus", mock.Anything, achievementIDHex, mock.MatchedBy(func(ch model.StatusChange) bool {
+				return ch.To == model.StatusDraft && ch.By == tt.claims.UserID
+			})).Return(&model.AchievementReference{Status: model.StatusDraft}, nil)
+			achRepo.On("Restore", mock.Anything, achievementID).Return(nil)
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{ID: achievementID, StudentID: studentID}, nil)
+
+			app := fiber.New()
+			app.Post("/:id/restore", func(c *fiber.Ctx) error {
+				c.Locals("user", tt.claims)
+				return svc.RestoreAchievement(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("POST", "/"+achievementIDHex+"/restore", nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == fiber.StatusOK {
+				achRepo.AssertCalled(t, "Restore", mock.Anything, achievementID)
+			} else {
+				refRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+				achRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+/*
+=================================================
+TEST: TRASH LIST
+=================================================
+*/
+
+func TestAchievementService_GetTrash(t *testing.T) {
+	deletedAt := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		claims         *model.JWTClaims
+		query          string
+		studentIDs     []string
+		expectedStatus int
+	}{
+		{"Student - own trash", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"}, "", []string{"stu-1"}, fiber.StatusOK},
+		{"Admin - filtered", &model.JWTClaims{UserID: "admin-1", Role: "Admin"}, "?student_id=stu-2", []string{"stu-2"}, fiber.StatusOK},
+		{"Admin - all", &model.JWTClaims{UserID: "admin-1", Role: "Admin"}, "", nil, fiber.StatusOK},
+		{"Forbidden - lecturer", &model.JWTClaims{UserID: "lec-1", Role: "Dosen Wali"}, "", nil, fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo})
+
+			achRepo.On("FindDeleted", mock.Anything, tt.studentIDs).Return([]model.Achievement{
+				{ID: primitive.NewObjectID(), Title: "Lomba", DeletedAt: &deletedAt},
+			}, nil)
+
+			app := fiber.New()
+			app.Get("/trash", func(c *fiber.Ctx) error {
+				c.Locals("user", tt.claims)
+				return svc.GetTrash(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/trash"+tt.query, nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != fiber.StatusOK {
+				return
+			}
+
+			var out struct {
+				Data []model.TrashItem `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+			assert.Len(t, out.Data, 1)
+			// default retensi 30 hari
+			assert.Equal(t, deletedAt.AddDate(0, 0, 30), out.Data[0].PurgeAt.UTC())
+		})
+	}
+}
+
+/*
+=================================================
+TEST: PURGE
+=================================================
+*/
+
+func TestAchievementService_PurgeTrash(t *testing.T) {
+	purged := primitive.NewObjectID()
+	restored := primitive.NewObjectID()
+	cutoff := time.Now()
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	commentRepo := new(MockCommentRepo)
+	versionRepo := new(MockVersionRepo)
+	store := new(MockStorage)
+	svc := newAchievementService(achievementDeps{
+		achRepo:     achRepo,
+		refRepo:     refRepo,
+		commentRepo: commentRepo,
+		versionRepo: versionRepo,
+		store:       store,
+	})
+
+	achRepo.On("FindDeletedBefore", mock.Anything, cutoff).Return([]model.Achievement{
+		{ID: purged, Attachments: []model.Attachment{
+			{FileURL: "/uploads/shared.pdf"},
+			{FileURL: "/uploads/../config/app.go"}, // di luar uploads → tidak disentuh
+			{Key: "achievements/" + purged.Hex() + "/1-own.pdf"},
+		}},
+		{ID: restored}, // dipulihkan setelah di-scan
+	}, nil)
+	refRepo.On("Purge", mock.Anything, purged.Hex()).Return(true, nil)
+	refRepo.On("Purge", mock.Anything, restored.Hex()).Return(false, nil)
+	achRepo.On("AttachmentInUse", mock.Anything, "shared.pdf", purged).Return(true, nil)
+	achRepo.On("AttachmentInUse", mock.Anything, "achievements/"+purged.Hex()+"/1-own.pdf", purged).Return(false, nil)
+	store.On("Delete", mock.Anything, "achievements/"+purged.Hex()+"/1-own.pdf").Return(nil)
+	commentRepo.On("DeleteByAchievementID", mock.Anything, purged.Hex()).Return(nil)
+	versionRepo.On("DeleteByAchievementID", mock.Anything, purged.Hex()).Return(nil)
+	achRepo.On("HardDelete", mock.Anything, purged).Return(nil)
+
+	report, err := svc.PurgeTrash(context.Background(), cutoff)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.TrashPurgeReport{Checked: 2, Purged: 1, FilesRemoved: 1}, report)
+	achRepo.AssertNotCalled(t, "HardDelete", mock.Anything, restored)
+	achRepo.AssertNumberOfCalls(t, "AttachmentInUse", 2)
+	store.AssertNumberOfCalls(t, "Delete", 1)
+	// klaim perpanjangan yang dipegang dokumen purge ikut dilepas
+	achRepo.AssertCalled(t, "ReleaseRenewalBy", mock.Anything, purged.Hex())
+	achRepo.AssertNotCalled(t, "ReleaseRenewalBy", mock.Anything, restored.Hex())
+}
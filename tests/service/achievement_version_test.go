@@ -0,0 +1,281 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockVersionRepo struct{ mock.Mock }
+
+func (m *MockVersionRepo) EnsureIndexes(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockVersionRepo) Create(ctx context.Context, v *model.AchievementVersion) error {
+	args := m.Called(ctx, v)
+	return args.Error(0)
+}
+
+func (m *MockVersionRepo) FindByAchievementID(ctx context.Context, achievementID string) ([]model.AchievementVersion, error) {
+	args := m.Called(ctx, achievementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AchievementVersion), args.Error(1)
+}
+
+func (m *MockVersionRepo) DeleteByAchievementID(ctx context.Context, achievementID string) error {
+	return m.Called(ctx, achievementID).Error(0)
+}
+
+/*
+=================================================
+TEST: DIFF
+=================================================
+*/
+
+func TestDiffAchievements(t *testing.T) {
+	from := &model.Achievement{
+		Title:       "Juara 2",
+		Description: "Lomba",
+		Details:     map[string]any{"rank": int32(2), "organizer": "Kemdikbud"},
+		Attachments: []model.Attachment{{FileName: "a.pdf"}},
+	}
+	to := &model.Achievement{
+		Title:       "Juara 1",
+		Description: "Lomba",
+		Tags:        []string{},
+		Details:     map[string]any{"rank": int32(1), "medalType": "gold"},
+		Attachments: []model.Attachment{{FileName: "a.pdf"}, {FileName: "b.pdf"}},
+	}
+
+	assert.Equal(t, []model.FieldChange{
+		{Field: "title", Before: "Juara 2", After: "Juara 1"},
+		{Field: "attachments", Before: []string{"a.pdf"}, After: []string{"a.pdf", "b.pdf"}},
+		{Field: "details.medalType", Before: nil, After: "gold"},
+		{Field: "details.organizer", Before: "Kemdikbud", After: nil},
+		{Field: "details.rank", Before: int32(2), After: int32(1)},
+	}, service.DiffAchievements(from, to))
+
+	assert.Empty(t, service.DiffAchievements(to, to))
+}
+
+/*
+=================================================
+TEST: VERSION HISTORY
+=================================================
+*/
+
+func versionFixture(achievementID string) []model.AchievementVersion {
+	return []model.AchievementVersion{
+		{AchievementID: achievementID, Version: 1, Reason: model.VersionCreate, Round: 0, Snapshot: model.Achievement{Title: "Draft"}},
+		{AchievementID: achievementID, Version: 2, Reason: model.VersionSubmit, Round: 0, Snapshot: model.Achievement{Title: "Juara 2"}},
+		{AchievementID: achievementID, Version: 3, Reason: model.VersionUpdate, Round: 1, Snapshot: model.Achievement{Title: "Juara 1"}},
+	}
+}
+
+func TestAchievementService_GetAchievementVersions(t *testing.T) {
+	achievementIDHex := primitive.NewObjectID().Hex()
+	studentID := "stu-1"
+
+	tests := []struct {
+		name           string
+		claims         *model.JWTClaims
+		round          int
+		expectedStatus int
+		expectSince    *model.AchievementDiff
+	}{
+		{"Success - changes since rejected submit", &model.JWTClaims{UserID: "admin-1", Role: "Admin"}, 1, fiber.StatusOK,
+			&model.AchievementDiff{From: 2, To: 3, Changes: []model.FieldChange{{Field: "title", Before: "Juara 2", After: "Juara 1"}}}},
+		{"Success - never rejected", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: studentID}, 0, fiber.StatusOK, nil},
+		{"Forbidden - other student", &model.JWTClaims{UserID: "user-9", Role: "Mahasiswa", StudentID: "stu-9"}, 0, fiber.StatusForbidden, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refRepo := new(MockReferenceRepo)
+			versionRepo := new(MockVersionRepo)
+			svc := newAchievementService(achievementDeps{refRepo: refRepo, versionRepo: versionRepo})
+
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).
+				Return(&model.AchievementReference{StudentID: studentID, ResubmissionCount: tt.round}, nil)
+			refRepo.On("GetMemberIDs", mock.Anything, achievementIDHex).Return([]string{}, nil)
+			versionRepo.On("FindByAchievementID", mock.Anything, achievementIDHex).Return(versionFixture(achievementIDHex), nil)
+
+			app := fiber.New()
+			app.Get("/:id/versions", func(c *fiber.Ctx) error {
+				c.Locals("user", tt.claims)
+				return svc.GetAchievementVersions(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/"+achievementIDHex+"/versions", nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != fiber.StatusOK {
+				return
+			}
+
+			var out struct {
+				Data  []model.AchievementVersion `json:"data"`
+				Since *model.AchievementDiff     `json:"changes_since_rejection"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+			assert.Len(t, out.Data, 3)
+			assert.Equal(t, tt.expectSince, out.Since)
+		})
+	}
+}
+
+func TestAchievementService_GetAchievementVersionDiff(t *testing.T) {
+	achievementIDHex := primitive.NewObjectID().Hex()
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		from, to       int
+	}{
+		{"Default - latest vs previous", "", fiber.StatusOK, 2, 3},
+		{"Explicit range", "?from=1&to=3", fiber.StatusOK, 1, 3},
+		{"Bad request - same version", "?from=2&to=2", fiber.StatusBadRequest, 0, 0},
+		{"Not found - unknown version", "?from=1&to=9", fiber.StatusNotFound, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refRepo := new(MockReferenceRepo)
+			versionRepo := new(MockVersionRepo)
+			svc := newAchievementService(achievementDeps{refRepo: refRepo, versionRepo: versionRepo})
+
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(&model.AchievementReference{StudentID: "stu-1"}, nil)
+			versionRepo.On("FindByAchievementID", mock.Anything, achievementIDHex).Return(versionFixture(achievementIDHex), nil)
+
+			app := fiber.New()
+			app.Get("/:id/versions/diff", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "admin-1", Role: "Admin"})
+				return svc.GetAchievementVersionDiff(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/"+achievementIDHex+"/versions/diff"+tt.query, nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != fiber.StatusOK {
+				return
+			}
+
+			var diff model.AchievementDiff
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&diff))
+			assert.Equal(t, tt.from, diff.From)
+			assert.Equal(t, tt.to, diff.To)
+			assert.Len(t, diff.Changes, 1)
+		})
+	}
+}
+
+/*
+=================================================
+TEST: SNAPSHOT ON UPDATE
+=================================================
+*/
+
+func TestAchievementService_UpdateSavesVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		createErr   error
+		wantWarning bool
+	}{
+		{"Success", nil, false},
+		// perubahan sudah tersimpan → tetap 200, client tidak perlu mengulang
+		{"Version not saved", errors.New("mongo down"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achievementID := primitive.NewObjectID()
+			achievementIDHex := achievementID.Hex()
+			studentID := "stu-1"
+
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			versionRepo := new(MockVersionRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, versionRepo: versionRepo})
+
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).
+				Return(&model.AchievementReference{StudentID: studentID, Status: model.StatusDraft, ResubmissionCount: 2}, nil)
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{ID: achievementID, StudentID: studentID, Title: "Lama"}, nil)
+			achRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+			versionRepo.On("Create", mock.Anything, mock.Anything).Return(tt.createErr)
+
+			app := fiber.New()
+			app.Put("/:id", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: studentID})
+				c.Locals("permissions", []string{"achievement:update"})
+				return svc.UpdateAchievement(c)
+			})
+
+			req := httptest.NewRequest("PUT", "/"+achievementIDHex, strings.NewReader(`{"title":"Baru"}`))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			var out struct {
+				VersionWarning string `json:"version_warning"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+			assert.Equal(t, tt.wantWarning, out.VersionWarning != "")
+			versionRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(v *model.AchievementVersion) bool {
+				return v.AchievementID == achievementIDHex &&
+					v.Reason == model.VersionUpdate &&
+					v.Round == 2 &&
+					v.Snapshot.Title == "Baru" &&
+					v.CreatedBy == "user-1"
+			}))
+		})
+	}
+}
+
+func TestAchievementService_CreateKeepsAchievementWhenVersionFails(t *testing.T) {
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	stuRepo := new(MockStudentRepo)
+	versionRepo := new(MockVersionRepo)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, stuRepo: stuRepo, versionRepo: versionRepo})
+
+	oid := primitive.NewObjectID()
+	stuRepo.On("GetStudentProfile", mock.Anything, "user-1").Return(&model.Student{ID: "stu-1"}, nil)
+	achRepo.On("Create", mock.Anything, mock.Anything).Return(oid, nil)
+	refRepo.On("CreateDraft", mock.Anything, "stu-1", oid.Hex(), "user-1").Return(nil)
+	versionRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("mongo down"))
+	allowDuplicateCheck(achRepo, stuRepo)
+
+	app := fiber.New()
+	app.Post("/achievements", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+		return svc.CreateAchievement(c)
+	})
+
+	body := `{"achievementType":"academic","title":"Asisten","details":{"activityName":"Praktikum","date":"2026-05-01"}}`
+	req := httptest.NewRequest("POST", "/achievements", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	// prestasi sudah tersimpan: 201 + peringatan, bukan 500 yang memancing create ulang
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	var out struct {
+		VersionWarning string `json:"version_warning"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.NotEmpty(t, out.VersionWarning)
+	achRepo.AssertNotCalled(t, "Remove", mock.Anything, mock.Anything)
+	refRepo.AssertNotCalled(t, "Discard", mock.Anything, mock.Anything)
+}
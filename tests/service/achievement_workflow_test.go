@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+=================================================
+TEST: DEFAULT WORKFLOW
+=================================================
+*/
+
+func TestAchievementWorkflow_DefaultTransitions(t *testing.T) {
+	w, err := service.LoadAchievementWorkflow("")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		action  string
+		from    string
+		allowed bool
+		to      string
+	}{
+		{"submit from draft", model.ActionSubmit, model.StatusDraft, true, model.StatusSubmitted},
+		{"submit from rejected", model.ActionSubmit, model.StatusRejected, false, ""},
+		{"verify from submitted", model.ActionVerify, model.StatusSubmitted, true, model.StatusVerified},
+		{"verify from draft", model.ActionVerify, model.StatusDraft, false, ""},
+		{"reject from submitted", model.ActionReject, model.StatusSubmitted, true, model.StatusRejected},
+		{"update from draft", model.ActionUpdate, model.StatusDraft, true, model.StatusDraft},
+		{"update from verified", model.ActionUpdate, model.StatusVerified, false, ""},
+		{"delete from draft", model.ActionDelete, model.StatusDraft, true, model.StatusDeleted},
+		{"revoke from verified", model.ActionRevoke, model.StatusVerified, true, model.StatusRevoked},
+		{"revoke from submitted", model.ActionRevoke, model.StatusSubmitted, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := w.Transition(tt.action, tt.from)
+			if !tt.allowed {
+				assert.ErrorIs(t, err, service.ErrIllegalTransition)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.to, tr.To)
+		})
+	}
+
+	_, err = w.Transition("publish", model.StatusDraft)
+	assert.ErrorIs(t, err, service.ErrUnknownAction)
+}
+
+/*
+=================================================
+TEST: LOAD FROM CONFIG FILE
+=================================================
+*/
+
+func TestAchievementWorkflow_LoadFromFile(t *testing.T) {
+	def := service.DefaultWorkflowDefinition()
+
+	// fakultas menambah status "in_review" tanpa ubah kode
+	def.States = append(def.States, "in_review")
+	def.Transitions = append(def.Transitions, model.WorkflowTransition{
+		Action: "start_review",
+		From:   []string{model.StatusSubmitted},
+		To:     "in_review",
+		Actors: []model.WorkflowActor{{Role: "Admin"}},
+	})
+	for i := range def.Transitions {
+		if def.Transitions[i].Action == model.ActionVerify {
+			def.Transitions[i].From = []string{"in_review"}
+		}
+	}
+
+	raw, _ := json.Marshal(def)
+	path := filepath.Join(t.TempDir(), "workflow.json")
+	assert.NoError(t, os.WriteFile(path, raw, 0644))
+
+	w, err := service.LoadAchievementWorkflow(path)
+	assert.NoError(t, err)
+
+	assert.True(t, w.Allows("start_review", model.StatusSubmitted))
+	assert.True(t, w.Allows(model.ActionVerify, "in_review"))
+	assert.False(t, w.Allows(model.ActionVerify, model.StatusSubmitted))
+}
+
+func TestAchievementWorkflow_InvalidDefinition(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(def *model.WorkflowDefinition)
+	}{
+		{
+			name: "unknown target state",
+			mutate: func(def *model.WorkflowDefinition) {
+				def.Transitions[0].To = "archived"
+			},
+		},
+		{
+			name: "duplicate action",
+			mutate: func(def *model.WorkflowDefinition) {
+				def.Transitions = append(def.Transitions, def.Transitions[0])
+			},
+		},
+		{
+			name: "missing required action",
+			mutate: func(def *model.WorkflowDefinition) {
+				def.Transitions = def.Transitions[1:]
+			},
+		},
+		{
+			name: "unknown relation",
+			mutate: func(def *model.WorkflowDefinition) {
+				def.Transitions[0].Actors = []model.WorkflowActor{{Role: "Admin", Relation: "friend"}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := service.DefaultWorkflowDefinition()
+			tt.mutate(&def)
+
+			_, err := service.NewAchievementWorkflow(def)
+			assert.Error(t, err)
+		})
+	}
+
+	// tiap action yang dipakai handler wajib ada (mis. config lama tanpa revise / restore)
+	for _, action := range service.RequiredWorkflowActions {
+		t.Run("missing "+action, func(t *testing.T) {
+			def := service.DefaultWorkflowDefinition()
+			def.Transitions = slices.DeleteFunc(def.Transitions, func(tr model.WorkflowTransition) bool {
+				return tr.Action == action
+			})
+
+			_, err := service.NewAchievementWorkflow(def)
+			assert.ErrorContains(t, err, action)
+		})
+	}
+
+	_, err := service.LoadAchievementWorkflow(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
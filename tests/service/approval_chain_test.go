@@ -0,0 +1,270 @@
+package service_test
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/*
+=================================================
+TEST: RESOLVE CHAIN
+=================================================
+*/
+
+func TestApprovalChains_Resolve(t *testing.T) {
+	chains, err := service.LoadApprovalChains("")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		achieve  model.Achievement
+		expected []string
+	}{
+		{
+			name:     "publication - two levels",
+			achieve:  model.Achievement{AchievementType: "publication"},
+			expected: []string{"advisor", "faculty"},
+		},
+		{
+			name: "international competition - two levels",
+			achieve: model.Achievement{
+				AchievementType: "competition",
+				Details:         map[string]any{"competitionLevel": "International"},
+			},
+			expected: []string{"advisor", "faculty"},
+		},
+		{
+			name: "national competition - advisor only",
+			achieve: model.Achievement{
+				AchievementType: "competition",
+				Details:         map[string]any{"competitionLevel": "national"},
+			},
+			expected: []string{"advisor"},
+		},
+		{
+			name:     "organization - advisor only",
+			achieve:  model.Achievement{AchievementType: "organization"},
+			expected: []string{"advisor"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := chains.Resolve(&tt.achieve)
+
+			var names []string
+			for _, lvl := range chain.Levels {
+				names = append(names, lvl.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestApprovalChains_RequireFallback(t *testing.T) {
+	_, err := service.NewApprovalChains([]model.ApprovalChain{
+		{
+			AchievementType: "publication",
+			Levels:          []model.ApprovalLevel{{Name: "advisor", Actors: []model.WorkflowActor{{Role: "Admin"}}}},
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestApprovalChains_LoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chains.json")
+	raw := `[
+		{"achievementType": "publication", "levels": [
+			{"name": "advisor", "actors": [{"role": "Dosen Wali", "relation": "advisor"}]},
+			{"name": "faculty", "actors": [{"role": "Admin"}]}
+		]},
+		{"levels": [{"name": "advisor", "actors": [{"role": "Dosen Wali", "relation": "advisor"}]}]}
+	]`
+	assert.NoError(t, os.WriteFile(path, []byte(raw), 0o600))
+
+	chains, err := service.LoadApprovalChains(path)
+	assert.NoError(t, err)
+
+	// key camelCase sama dengan field achievementType di body prestasi
+	assert.Len(t, chains.Resolve(&model.Achievement{AchievementType: "publication"}).Levels, 2)
+	assert.Len(t, chains.Resolve(&model.Achievement{AchievementType: "academic"}).Levels, 1)
+}
+
+/*
+=================================================
+TEST: VERIFY PER LEVEL
+=================================================
+*/
+
+func TestAchievementService_VerifyMultiLevel(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+	studentID := "stu-123"
+	lecturerUserID := "lecturer-user"
+	adminID := "admin-1"
+
+	tests := []struct {
+		name           string
+		claims         *model.JWTClaims
+		approvalLevel  int
+		approvals      []model.AchievementApproval
+		expectedStatus int
+		expectedNext   string
+	}{
+		{
+			name:           "advisor approves first level",
+			claims:         &model.JWTClaims{UserID: lecturerUserID, Role: "Dosen Wali"},
+			approvalLevel:  0,
+			approvals:      []model.AchievementApproval{},
+			expectedStatus: fiber.StatusOK,
+			expectedNext:   "faculty",
+		},
+		{
+			name:           "admin approves final level",
+			claims:         &model.JWTClaims{UserID: adminID, Role: "Admin"},
+			approvalLevel:  1,
+			approvals:      []model.AchievementApproval{{Level: 0, ApprovedBy: lecturerUserID}},
+			expectedStatus: fiber.StatusOK,
+			expectedNext:   "",
+		},
+		{
+			name:           "advisor cannot approve faculty level",
+			claims:         &model.JWTClaims{UserID: lecturerUserID, Role: "Dosen Wali"},
+			approvalLevel:  1,
+			approvals:      []model.AchievementApproval{{Level: 0, ApprovedBy: lecturerUserID}},
+			expectedStatus: fiber.StatusForbidden,
+		},
+		{
+			name:           "same admin cannot approve twice",
+			claims:         &model.JWTClaims{UserID: adminID, Role: "Admin"},
+			approvalLevel:  1,
+			approvals:      []model.AchievementApproval{{Level: 0, ApprovedBy: adminID}},
+			expectedStatus: fiber.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			lecRepo := new(MockLecturerRepo)
+			svc := newAchievementService(achievementDeps{
+				achRepo: achRepo,
+				refRepo: refRepo,
+				stuRepo: stuRepo,
+				lecRepo: lecRepo,
+			})
+
+			ref := &model.AchievementReference{
+				StudentID:     studentID,
+				Status:        model.StatusSubmitted,
+				ApprovalLevel: tt.approvalLevel,
+			}
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: studentID, AchievementType: "publication",
+			}, nil)
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
+			refRepo.On("GetApprovals", mock.Anything, achievementIDHex).Return(tt.approvals, nil)
+			refRepo.On("Approve", mock.Anything, achievementIDHex, mock.Anything, mock.Anything, tt.expectedNext).Return(ref, nil)
+			lecRepo.On("GetLecturerProfile", mock.Anything, lecturerUserID).Return(&model.Lecturer{ID: "lec-1"}, nil)
+			stuRepo.On("GetStudentsByAdvisor", mock.Anything, "lec-1").Return([]*model.Student{{ID: studentID}}, nil)
+
+			app := fiber.New()
+			app.Post("/:id/verify", func(c *fiber.Ctx) error {
+				c.Locals("user", tt.claims)
+				return svc.VerifyAchievement(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("POST", "/"+achievementIDHex+"/verify", nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == fiber.StatusOK {
+				refRepo.AssertCalled(t, "Approve", mock.Anything, achievementIDHex, mock.Anything, mock.Anything, tt.expectedNext)
+			} else {
+				refRepo.AssertNotCalled(t, "Approve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+/*
+=================================================
+TEST: REJECT BERTINGKAT
+=================================================
+*/
+
+func TestAchievementService_RejectMultiLevel(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+	studentID := "stu-1"
+	lecturerUserID := "lecturer-user"
+	adminID := "admin-1"
+
+	tests := []struct {
+		name           string
+		claims         *model.JWTClaims
+		approvalLevel  int
+		expectedStatus int
+	}{
+		{"advisor rejects first level", &model.JWTClaims{UserID: lecturerUserID, Role: "Dosen Wali"}, 0, fiber.StatusOK},
+		{"advisor cannot reject faculty level", &model.JWTClaims{UserID: lecturerUserID, Role: "Dosen Wali"}, 1, fiber.StatusForbidden},
+		{"admin rejects faculty level", &model.JWTClaims{UserID: adminID, Role: "Admin"}, 1, fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			lecRepo := new(MockLecturerRepo)
+			svc := newAchievementService(achievementDeps{
+				achRepo: achRepo,
+				refRepo: refRepo,
+				stuRepo: stuRepo,
+				lecRepo: lecRepo,
+			})
+
+			ref := &model.AchievementReference{
+				StudentID:     studentID,
+				Status:        model.StatusSubmitted,
+				ApprovalLevel: tt.approvalLevel,
+			}
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: studentID, AchievementType: "publication",
+			}, nil)
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
+			refRepo.On("Reject", mock.Anything, achievementIDHex, mock.Anything).Return(ref, nil)
+			lecRepo.On("GetLecturerProfile", mock.Anything, lecturerUserID).Return(&model.Lecturer{ID: "lec-1"}, nil)
+			stuRepo.On("GetStudentsByAdvisor", mock.Anything, "lec-1").Return([]*model.Student{{ID: studentID}}, nil)
+
+			app := fiber.New()
+			app.Post("/:id/reject", func(c *fiber.Ctx) error {
+				c.Locals("user", tt.claims)
+				return svc.RejectAchievement(c)
+			})
+
+			req := httptest.NewRequest("POST", "/"+achievementIDHex+"/reject", strings.NewReader(`{"rejection_note":"bukti kurang"}`))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == fiber.StatusOK {
+				refRepo.AssertCalled(t, "Reject", mock.Anything, achievementIDHex, mock.Anything)
+			} else {
+				refRepo.AssertNotCalled(t, "Reject", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
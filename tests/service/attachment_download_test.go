@@ -0,0 +1,228 @@
+package service_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/app/storage"
+	"uas-backend/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const downloadContent = "%PDF-1.4\n1 0 obj"
+
+// downloadFixture: prestasi milik stu-1 dengan satu lampiran PDF
+func downloadFixture(t *testing.T) (*fiber.App, *MockStorage, string, string) {
+	t.Setenv("ATTACHMENT_URL_SECRET", "test-secret")
+
+	achievementID := primitive.NewObjectID()
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	store := new(MockStorage)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, store: store})
+
+	att := model.Attachment{
+		ID:       "att-1",
+		FileName: "sertifikat juara.pdf",
+		Key:      "sha256/ab/abcdef",
+		FileType: "application/pdf",
+		Size:     int64(len(downloadContent)),
+		Hash:     "abcdef",
+	}
+	// lampiran lama: belum punya ID, dicari lewat hash
+	legacy := model.Attachment{
+		FileName: "sertifikat juara.pdf",
+		FileURL:  "/uploads/lama.pdf",
+		FileType: "application/pdf",
+		Hash:     "fedcba",
+	}
+	achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+		ID: achievementID, StudentID: "stu-1", Attachments: []model.Attachment{att, legacy},
+	}, nil)
+	refRepo.On("GetMemberIDs", mock.Anything, achievementID.Hex()).Return([]string{"stu-1"}, nil)
+
+	app := fiber.New()
+	withClaims := func(h fiber.Handler) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			c.Locals("user", &model.JWTClaims{UserID: "user-" + c.Get("X-Student"), Role: "Mahasiswa", StudentID: c.Get("X-Student")})
+			return h(c)
+		}
+	}
+	app.Get("/achievements/:id/attachments/:attachmentId", withClaims(svc.DownloadAttachment))
+	app.Get("/achievements/:id/attachments/:attachmentId/link", withClaims(svc.GetAttachmentLink))
+	app.Get("/api/v1/files/achievements/:id/attachments/:attachmentId", svc.DownloadSignedAttachment)
+
+	return app, store, achievementID.Hex(), att.Key
+}
+
+/*
+=================================================
+TEST: DOWNLOAD LAMPIRAN
+=================================================
+*/
+
+func TestAchievementService_DownloadAttachment(t *testing.T) {
+	tests := []struct {
+		name           string
+		student        string
+		attachmentID   string
+		rangeHeader    string
+		ifNoneMatch    string
+		expectedStatus int
+		expectedBody   string
+		contentRange   string
+	}{
+		{"Success - owner gets full file", "stu-1", "att-1", "", "", fiber.StatusOK, downloadContent, ""},
+		{"Success - legacy attachment by hash", "stu-1", "fedcba", "", "", fiber.StatusOK, downloadContent, ""},
+		{"Partial - single range", "stu-1", "att-1", "bytes=0-7", "", fiber.StatusPartialContent, "%PDF-1.4", "bytes 0-7/16"},
+		{"Partial - suffix range", "stu-1", "att-1", "bytes=-3", "", fiber.StatusPartialContent, "obj", "bytes 13-15/16"},
+		{"Unsatisfiable range", "stu-1", "att-1", "bytes=100-200", "", fiber.StatusRequestedRangeNotSatisfiable, "", "bytes */16"},
+		{"Not modified", "stu-1", "att-1", "", `"abcdef"`, fiber.StatusNotModified, "", ""},
+		{"Forbidden - not a member", "stu-2", "att-1", "", "", fiber.StatusForbidden, "", ""},
+		{"Not found - unknown attachment", "stu-1", "att-9", "", "", fiber.StatusNotFound, "", ""},
+		{"Not found - hash of attachment with id", "stu-1", "abcdef", "", "", fiber.StatusNotFound, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, store, achievementID, key := downloadFixture(t)
+			store.On("Get", mock.Anything, mock.Anything).Return(io.NopCloser(strings.NewReader(downloadContent)), nil)
+			store.On("GetRange", mock.Anything, key, mock.Anything, mock.Anything).Return(
+				func(offset, length int64) io.ReadCloser {
+					return io.NopCloser(strings.NewReader(downloadContent[offset : offset+length]))
+				}, nil)
+
+			req := httptest.NewRequest("GET", "/achievements/"+achievementID+"/attachments/"+tt.attachmentID, nil)
+			req.Header.Set("X-Student", tt.student)
+			if tt.rangeHeader != "" {
+				req.Header.Set(fiber.HeaderRange, tt.rangeHeader)
+			}
+			if tt.ifNoneMatch != "" {
+				req.Header.Set(fiber.HeaderIfNoneMatch, tt.ifNoneMatch)
+			}
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.contentRange, resp.Header.Get(fiber.HeaderContentRange))
+			if tt.expectedBody != "" {
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, tt.expectedBody, string(body))
+				assert.Equal(t, "application/pdf", resp.Header.Get(fiber.HeaderContentType))
+				assert.Equal(t, "nosniff", resp.Header.Get(fiber.HeaderXContentTypeOptions))
+				assert.Equal(t, `inline; filename="sertifikat juara.pdf"`, resp.Header.Get(fiber.HeaderContentDisposition))
+			}
+		})
+	}
+}
+
+func TestAchievementService_DownloadAttachmentMissingObject(t *testing.T) {
+	app, store, achievementID, key := downloadFixture(t)
+	store.On("Get", mock.Anything, key).Return(nil, storage.ErrNotFound)
+
+	req := httptest.NewRequest("GET", "/achievements/"+achievementID+"/attachments/att-1?download=true", nil)
+	req.Header.Set("X-Student", "stu-1")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+/*
+=================================================
+TEST: LINK BERTANDA TANGAN
+=================================================
+*/
+
+func TestAchievementService_SignedAttachmentLink(t *testing.T) {
+	app, store, achievementID, key := downloadFixture(t)
+	store.On("Get", mock.Anything, key).Return(io.NopCloser(strings.NewReader(downloadContent)), nil)
+
+	// 1️⃣ link dibuat oleh anggota
+	req := httptest.NewRequest("GET", "/achievements/"+achievementID+"/attachments/att-1/link", nil)
+	req.Header.Set("X-Student", "stu-1")
+	resp, _ := app.Test(req)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var link struct {
+		URL       string    `json:"url"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&link))
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), link.ExpiresAt, time.Minute)
+
+	// 2️⃣ link dipakai tanpa JWT
+	resp, _ = app.Test(httptest.NewRequest("GET", link.URL, nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, downloadContent, string(body))
+
+	// 3️⃣ link diubah ke lampiran lain / tanda tangan rusak
+	u, _ := url.Parse(link.URL)
+	tampered := strings.Replace(u.Path, "att-1", "att-2", 1) + "?" + u.RawQuery
+	resp, _ = app.Test(httptest.NewRequest("GET", tampered, nil))
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", u.Path+"?expires="+u.Query().Get("expires")+"&signature=00", nil))
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	// 4️⃣ link kedaluwarsa (tanda tangan valid)
+	expires := time.Now().Add(-time.Minute).Unix()
+	mac := hmac.New(sha256.New, []byte("test-secret"))
+	fmt.Fprintf(mac, "%s/%s/%s/%d", achievementID, "att-1", "", expires)
+	expired := u.Path + "?expires=" + strconv.FormatInt(expires, 10) + "&signature=" + hex.EncodeToString(mac.Sum(nil))
+	resp, _ = app.Test(httptest.NewRequest("GET", expired, nil))
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+func TestAchievementService_SignedAttachmentLinkForbidden(t *testing.T) {
+	app, _, achievementID, _ := downloadFixture(t)
+
+	req := httptest.NewRequest("GET", "/achievements/"+achievementID+"/attachments/att-1/link", nil)
+	req.Header.Set("X-Student", "stu-2")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+func TestAttachmentURLSecret(t *testing.T) {
+	derived := hmac.New(sha256.New, []byte("jwt-secret"))
+	derived.Write([]byte("attachment-url"))
+
+	tests := []struct {
+		name      string
+		urlSecret string
+		jwtSecret string
+		expected  string
+	}{
+		{"Own secret", "url-secret", "jwt-secret", "url-secret"},
+		{"Derived from JWT secret", "", "jwt-secret", hex.EncodeToString(derived.Sum(nil))},
+		{"Not configured", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ATTACHMENT_URL_SECRET", tt.urlSecret)
+			t.Setenv("JWT_SECRET", tt.jwtSecret)
+
+			assert.Equal(t, tt.expected, config.AttachmentURLSecret())
+			if tt.jwtSecret != "" {
+				assert.NotEqual(t, tt.jwtSecret, config.AttachmentURLSecret(), "secret JWT tidak dipakai ulang")
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// editAttachments: lampiran draft milik stu-1 (lampiran kedua model lama, tanpa ID)
+func editAttachments() []model.Attachment {
+	return []model.Attachment{
+		{ID: "att-1", FileName: "salah.pdf", Key: "sha256/aa/aaaa", Hash: "aaaa", Kind: model.EvidenceCertificate},
+		{FileName: "foto.png", FileURL: "/uploads/foto.png", Hash: "bbbb", Kind: model.EvidencePhoto},
+	}
+}
+
+func newEditApp(svc *service.AchievementService) *fiber.App {
+	app := fiber.New()
+	withClaims := func(h fiber.Handler) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+			c.Locals("permissions", []string{"achievement:update"})
+			return h(c)
+		}
+	}
+	app.Delete("/:id/attachments/:attachmentId", withClaims(svc.DeleteAttachment))
+	app.Put("/:id/attachments/:attachmentId", withClaims(svc.ReplaceAttachment))
+	return app
+}
+
+/*
+=================================================
+TEST: HAPUS LAMPIRAN
+=================================================
+*/
+
+func TestAchievementService_DeleteAttachment(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         string
+		attachmentID   string
+		removed        bool
+		inUse          bool
+		expectedStatus int
+		storageDelete  bool
+		version        bool
+	}{
+		{"Success - object removed", model.StatusDraft, "att-1", true, false, fiber.StatusOK, true, true},
+		{"Success - legacy attachment by hash", model.StatusDraft, "bbbb", true, false, fiber.StatusOK, true, true},
+		{"Success - shared object kept", model.StatusDraft, "att-1", true, true, fiber.StatusOK, false, true},
+		{"Not found - unknown attachment", model.StatusDraft, "att-9", false, false, fiber.StatusNotFound, false, false},
+		{"Not found - removed concurrently", model.StatusDraft, "att-1", false, false, fiber.StatusNotFound, false, false},
+		{"Conflict - not editable", model.StatusSubmitted, "att-1", false, false, fiber.StatusConflict, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achievementID := primitive.NewObjectID()
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			versionRepo := new(MockVersionRepo)
+			store := new(MockStorage)
+			svc := newAchievementService(achievementDeps{
+				achRepo:     achRepo,
+				refRepo:     refRepo,
+				versionRepo: versionRepo,
+				store:       store,
+			})
+
+			refRepo.On("GetByAchievementID", mock.Anything, achievementID.Hex()).Return(&model.AchievementReference{
+				MongoAchievementID: achievementID.Hex(), StudentID: "stu-1", Status: tt.status,
+			}, nil)
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: "stu-1", Attachments: editAttachments(),
+			}, nil)
+			achRepo.On("RemoveAttachment", mock.Anything, achievementID, tt.attachmentID).Return(tt.removed, nil)
+			achRepo.On("AttachmentInUse", mock.Anything, mock.Anything, achievementID).Return(tt.inUse, nil)
+			store.On("Delete", mock.Anything, mock.Anything).Return(nil)
+			versionRepo.On("Create", mock.Anything, mock.MatchedBy(func(v *model.AchievementVersion) bool {
+				return v.Reason == model.VersionAttachmentDelete && len(v.Snapshot.Attachments) == 1
+			})).Return(nil)
+
+			req := httptest.NewRequest("DELETE", "/"+achievementID.Hex()+"/attachments/"+tt.attachmentID, nil)
+			resp, _ := newEditApp(svc).Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assertCalled(t, &store.Mock, "Delete", tt.storageDelete)
+			assertCalled(t, &versionRepo.Mock, "Create", tt.version)
+		})
+	}
+}
+
+/*
+=================================================
+TEST: GANTI LAMPIRAN
+=================================================
+*/
+
+func TestAchievementService_ReplaceAttachment(t *testing.T) {
+	pdf := "%PDF-1.4\n2 0 obj"
+	sum := sha256.Sum256([]byte(pdf))
+	pdfHash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name           string
+		existing       []model.Attachment
+		kind           string
+		replaced       bool
+		replaceErr     error
+		expectedStatus int
+		expectedKind   string
+		storageDelete  bool // object lama / object baru yang gagal dicatat
+		version        bool
+	}{
+		{"Success - keeps kind of old attachment", editAttachments(), "", true, nil, fiber.StatusOK, model.EvidenceCertificate, true, true},
+		{"Success - new kind", editAttachments(), model.EvidencePhoto, true, nil, fiber.StatusOK, model.EvidencePhoto, true, true},
+		{"Conflict - same file as another attachment", append(editAttachments(), model.Attachment{ID: "att-2", Hash: pdfHash}), "", false, nil, fiber.StatusConflict, "", false, false},
+		{"Not found - replaced concurrently", editAttachments(), "", false, nil, fiber.StatusNotFound, model.EvidenceCertificate, true, false},
+		{"MongoDB failure - new object removed", editAttachments(), "", false, errors.New("mongo down"), fiber.StatusInternalServerError, model.EvidenceCertificate, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achievementID := primitive.NewObjectID()
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			versionRepo := new(MockVersionRepo)
+			store := new(MockStorage)
+			svc := newAchievementService(achievementDeps{
+				achRepo:     achRepo,
+				refRepo:     refRepo,
+				versionRepo: versionRepo,
+				store:       store,
+			})
+
+			refRepo.On("GetByAchievementID", mock.Anything, achievementID.Hex()).Return(&model.AchievementReference{
+				MongoAchievementID: achievementID.Hex(), StudentID: "stu-1", Status: model.StatusDraft,
+			}, nil)
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: "stu-1", AchievementType: "competition", Attachments: tt.existing,
+			}, nil)
+			store.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
+			store.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "application/pdf").Return(nil)
+			store.On("Delete", mock.Anything, mock.Anything).Return(nil)
+			achRepo.On("AttachmentInUse", mock.Anything, "sha256/aa/aaaa", achievementID).Return(false, nil)
+			achRepo.On("ReplaceAttachment", mock.Anything, achievementID, "att-1", mock.MatchedBy(func(att model.Attachment) bool {
+				return att.ID != "" && att.ID != "att-1" && att.Hash == pdfHash && att.Kind == tt.expectedKind &&
+					att.FileName == "benar.pdf"
+			})).Return(tt.replaced, tt.replaceErr)
+			versionRepo.On("Create", mock.Anything, mock.MatchedBy(func(v *model.AchievementVersion) bool {
+				return v.Reason == model.VersionAttachmentReplace && v.Snapshot.Attachments[0].Hash == pdfHash
+			})).Return(nil)
+
+			body, contentType := newUploadRequest("benar.pdf", "application/pdf", pdf, tt.kind)
+			req := httptest.NewRequest("PUT", "/"+achievementID.Hex()+"/attachments/att-1", body)
+			req.Header.Set("Content-Type", contentType)
+			resp, _ := newEditApp(svc).Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assertCalled(t, &store.Mock, "Delete", tt.storageDelete)
+			assertCalled(t, &versionRepo.Mock, "Create", tt.version)
+			if tt.version {
+				store.AssertCalled(t, "Delete", mock.Anything, "sha256/aa/aaaa")
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/*
+=================================================
+TEST: LAMPIRAN LAMA (HANYA FILE_URL)
+=================================================
+*/
+
+// lampiran lama: backfill memberi ID + hash, lalu bisa diunduh & dihapus lewat ID itu
+func TestAchievementService_LegacyAttachmentEndToEnd(t *testing.T) {
+	const content = "%PDF-1.4\nlegacy"
+	sum := sha256.Sum256([]byte(content))
+
+	achievementID := primitive.NewObjectID()
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	store := new(MockStorage)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, store: store})
+
+	legacy := model.Attachment{FileName: "sertifikat.pdf", FileURL: "/uploads/sertifikat.pdf", FileType: "application/pdf"}
+	doc := &model.Achievement{ID: achievementID, StudentID: "stu-1", Attachments: []model.Attachment{legacy}}
+
+	// 1️⃣ backfill (job saat start)
+	achRepo.On("FindLegacyAttachments", mock.Anything).Return([]model.Achievement{*doc}, nil)
+	store.On("Get", mock.Anything, "sertifikat.pdf").Return(func(string) io.ReadCloser {
+		return io.NopCloser(strings.NewReader(content))
+	}, nil)
+	var backfilled model.Attachment
+	achRepo.On("BackfillAttachment", mock.Anything, achievementID, legacy, mock.Anything).Run(func(args mock.Arguments) {
+		backfilled = args.Get(3).(model.Attachment)
+		doc.Attachments[0] = backfilled
+	}).Return(true, nil)
+
+	n, err := svc.BackfillLegacyAttachments(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.NotEmpty(t, backfilled.ID)
+	assert.Equal(t, hex.EncodeToString(sum[:]), backfilled.Hash)
+	assert.Equal(t, int64(len(content)), backfilled.Size)
+	assert.Equal(t, legacy.FileURL, backfilled.FileURL, "file tidak dipindah")
+
+	// 2️⃣ download lewat ID baru
+	achRepo.On("GetByID", mock.Anything, achievementID).Return(doc, nil)
+	refRepo.On("GetMemberIDs", mock.Anything, achievementID.Hex()).Return([]string{"stu-1"}, nil)
+	refRepo.On("GetByAchievementID", mock.Anything, achievementID.Hex()).Return(&model.AchievementReference{
+		MongoAchievementID: achievementID.Hex(), StudentID: "stu-1", Status: model.StatusDraft,
+	}, nil)
+
+	app := newEditApp(svc)
+	app.Get("/:id/attachments/:attachmentId", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+		return svc.DownloadAttachment(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/"+achievementID.Hex()+"/attachments/"+backfilled.ID, nil))
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, content, string(body))
+
+	// 3️⃣ hapus lewat ID baru → file lama ikut dihapus dari storage
+	achRepo.On("RemoveAttachment", mock.Anything, achievementID, backfilled.ID).Return(true, nil)
+	achRepo.On("AttachmentInUse", mock.Anything, "sertifikat.pdf", achievementID).Return(false, nil)
+	store.On("Delete", mock.Anything, "sertifikat.pdf").Return(nil)
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/"+achievementID.Hex()+"/attachments/"+backfilled.ID, nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	store.AssertCalled(t, "Delete", mock.Anything, "sertifikat.pdf")
+}
+
+func TestAchievementService_BackfillLegacyAttachments(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+
+	tests := []struct {
+		name        string
+		attachments []model.Attachment
+		getErr      error
+		claimed     bool
+		expected    int
+		hashed      bool
+	}{
+		{"File hashed", []model.Attachment{{FileName: "a.pdf", FileURL: "/uploads/a.pdf"}}, nil, true, 1, true},
+		{"Missing file still gets an id", []model.Attachment{{FileName: "a.pdf", FileURL: "/uploads/a.pdf"}}, errors.New("not found"), true, 1, false},
+		{"Already backfilled concurrently", []model.Attachment{{FileName: "a.pdf", FileURL: "/uploads/a.pdf"}}, nil, false, 0, true},
+		{"Attachment with id skipped", []model.Attachment{{ID: "att-1", FileName: "a.pdf", Key: "sha256/aa/aaaa"}}, nil, true, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			store := new(MockStorage)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, store: store})
+
+			achRepo.On("FindLegacyAttachments", mock.Anything).Return([]model.Achievement{{ID: achievementID, Attachments: tt.attachments}}, nil)
+			if tt.getErr != nil {
+				store.On("Get", mock.Anything, "a.pdf").Return(nil, tt.getErr)
+			} else {
+				store.On("Get", mock.Anything, "a.pdf").Return(io.NopCloser(strings.NewReader("isi")), nil)
+			}
+			achRepo.On("BackfillAttachment", mock.Anything, achievementID, mock.Anything, mock.MatchedBy(func(att model.Attachment) bool {
+				return att.ID != "" && (att.Hash != "") == tt.hashed
+			})).Return(tt.claimed, nil)
+
+			n, err := svc.BackfillLegacyAttachments(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, n)
+			assertCalled(t, &achRepo.Mock, "BackfillAttachment", tt.attachments[0].ID == "")
+		})
+	}
+}
+
+// lampiran lama yang sudah di-backfill bisa diganti lewat ID-nya; file lama dihapus dari storage
+func TestAchievementService_ReplaceLegacyAttachment(t *testing.T) {
+	pdf := "%PDF-1.4\n2 0 obj"
+	achievementID := primitive.NewObjectID()
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	store := new(MockStorage)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, store: store})
+
+	backfilled := model.Attachment{ID: "legacy-1", FileName: "sertifikat.pdf", FileURL: "/uploads/sertifikat.pdf", Hash: "cccc", Kind: model.EvidenceCertificate}
+	refRepo.On("GetByAchievementID", mock.Anything, achievementID.Hex()).Return(&model.AchievementReference{
+		MongoAchievementID: achievementID.Hex(), StudentID: "stu-1", Status: model.StatusDraft,
+	}, nil)
+	achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+		ID: achievementID, StudentID: "stu-1", AchievementType: "competition", Attachments: []model.Attachment{backfilled},
+	}, nil)
+	store.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
+	store.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "application/pdf").Return(nil)
+	store.On("Delete", mock.Anything, "sertifikat.pdf").Return(nil)
+	achRepo.On("AttachmentInUse", mock.Anything, "sertifikat.pdf", achievementID).Return(false, nil)
+	achRepo.On("ReplaceAttachment", mock.Anything, achievementID, "legacy-1", mock.MatchedBy(func(att model.Attachment) bool {
+		return att.ID != "legacy-1" && att.Kind == model.EvidenceCertificate && att.Key != ""
+	})).Return(true, nil)
+
+	body, contentType := newUploadRequest("baru.pdf", "application/pdf", pdf, "")
+	req := httptest.NewRequest("PUT", "/"+achievementID.Hex()+"/attachments/legacy-1", body)
+	req.Header.Set("Content-Type", contentType)
+	resp, _ := newEditApp(svc).Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	store.AssertCalled(t, "Delete", mock.Anything, "sertifikat.pdf")
+}
@@ -0,0 +1,277 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func pngBytes(t *testing.T, w, h int) []byte {
+	var b bytes.Buffer
+	require.NoError(t, png.Encode(&b, image.NewRGBA(image.Rect(0, 0, w, h))))
+	return b.Bytes()
+}
+
+/*
+=================================================
+TEST: BUAT PRATINJAU
+=================================================
+*/
+
+func TestAchievementService_GeneratePreview(t *testing.T) {
+	tests := []struct {
+		name           string
+		fileType       string
+		content        []byte
+		getErr         error
+		putErr         error
+		expectedStatus string
+		expectedErr    bool
+		put            bool
+		saved          bool
+	}{
+		{"Ready - image", "image/png", pngBytes(t, 2048, 1024), nil, nil, model.PreviewReady, false, true, true},
+		{"Unavailable - PDF without images", "application/pdf", []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj"), nil, nil, model.PreviewUnavailable, false, false, true},
+		{"Unavailable - unsupported type", "application/zip", nil, nil, nil, model.PreviewUnavailable, false, false, true},
+		{"Failed - original missing", "image/png", nil, storage.ErrNotFound, nil, model.PreviewFailed, true, false, true},
+		// gangguan sementara: status tidak ditulis, sapuan berikutnya mencoba lagi
+		{"Pending - storage read", "image/png", nil, errors.New("bucket down"), nil, model.PreviewPending, true, false, false},
+		{"Pending - storage write", "image/png", pngBytes(t, 10, 10), nil, errors.New("bucket down"), model.PreviewPending, true, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achievementID := primitive.NewObjectID()
+			achRepo := new(MockAchievementRepo)
+			store := new(MockStorage)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, store: store})
+
+			att := model.Attachment{ID: "att-1", Key: "sha256/ab/abcd", FileType: tt.fileType,
+				Preview: &model.AttachmentPreview{Status: model.PreviewPending}}
+
+			if tt.getErr != nil {
+				store.On("Get", mock.Anything, att.Key).Return(nil, tt.getErr)
+			} else {
+				store.On("Get", mock.Anything, att.Key).Return(io.NopCloser(bytes.NewReader(tt.content)), nil)
+			}
+			store.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "image/jpeg").Return(tt.putErr)
+			achRepo.On("SetAttachmentPreview", mock.Anything, achievementID, "att-1", mock.MatchedBy(func(p model.AttachmentPreview) bool {
+				return p.Status == tt.expectedStatus
+			})).Return(nil)
+
+			result, err := svc.GeneratePreview(context.Background(), achievementID, att)
+
+			assert.Equal(t, tt.expectedErr, err != nil)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			assertCalled(t, &store.Mock, "Put", tt.put)
+			assertCalled(t, &achRepo.Mock, "SetAttachmentPreview", tt.saved)
+			if tt.put && tt.putErr == nil {
+				store.AssertCalled(t, "Put", mock.Anything, "sha256/ab/abcd.thumb.jpg", mock.Anything, mock.Anything, "image/jpeg")
+				store.AssertCalled(t, "Put", mock.Anything, "sha256/ab/abcd.preview.jpg", mock.Anything, mock.Anything, "image/jpeg")
+				assert.Equal(t, 1024, result.Width)
+				assert.Equal(t, 512, result.Height)
+			}
+		})
+	}
+}
+
+func TestAchievementService_GeneratePendingPreviews(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achRepo := new(MockAchievementRepo)
+	store := new(MockStorage)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, store: store})
+
+	achRepo.On("FindPendingPreviews", mock.Anything).Return([]model.Achievement{{
+		ID: achievementID,
+		Attachments: []model.Attachment{
+			{ID: "att-1", Key: "sha256/ab/abcd", FileType: "image/png", Preview: &model.AttachmentPreview{Status: model.PreviewPending}},
+			{ID: "att-2", Key: "sha256/cd/cdef", FileType: "image/png", Preview: &model.AttachmentPreview{Status: model.PreviewReady}},
+			{ID: "att-3", Key: "sha256/ef/ef01", FileType: "application/zip"},
+		},
+	}}, nil)
+	store.On("Get", mock.Anything, "sha256/ab/abcd").Return(io.NopCloser(bytes.NewReader(pngBytes(t, 10, 10))), nil)
+	store.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "image/jpeg").Return(nil)
+	achRepo.On("SetAttachmentPreview", mock.Anything, achievementID, "att-1", mock.Anything).Return(nil)
+
+	n, err := svc.GeneratePendingPreviews(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	achRepo.AssertNumberOfCalls(t, "SetAttachmentPreview", 1)
+}
+
+// panicReader: decoder / storage yang panic di tengah pembuatan pratinjau
+type panicReader struct{}
+
+func (panicReader) Read([]byte) (int, error) { panic("corrupt input") }
+
+func TestAchievementService_GeneratePendingPreviews_Panic(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achRepo := new(MockAchievementRepo)
+	store := new(MockStorage)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, store: store})
+
+	achRepo.On("FindPendingPreviews", mock.Anything).Return([]model.Achievement{{
+		ID: achievementID,
+		Attachments: []model.Attachment{
+			{ID: "att-1", Key: "sha256/ab/abcd", FileType: "application/pdf", Preview: &model.AttachmentPreview{Status: model.PreviewPending}},
+		},
+	}}, nil)
+	store.On("Get", mock.Anything, "sha256/ab/abcd").Return(io.NopCloser(panicReader{}), nil)
+	achRepo.On("SetAttachmentPreview", mock.Anything, achievementID, "att-1", model.AttachmentPreview{Status: model.PreviewFailed}).Return(nil)
+
+	var n int
+	var err error
+	assert.NotPanics(t, func() { n, err = svc.GeneratePendingPreviews(context.Background()) })
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	achRepo.AssertCalled(t, "SetAttachmentPreview", mock.Anything, achievementID, "att-1", model.AttachmentPreview{Status: model.PreviewFailed})
+}
+
+/*
+=================================================
+TEST: DOWNLOAD THUMBNAIL / PRATINJAU
+=================================================
+*/
+
+func TestAchievementService_DownloadAttachmentVariant(t *testing.T) {
+	tests := []struct {
+		name           string
+		preview        *model.AttachmentPreview
+		variant        string
+		expectedStatus int
+		expectedKey    string
+	}{
+		{"Thumbnail", readyPreview(), model.VariantThumbnail, fiber.StatusOK, "sha256/ab/abcd.thumb.jpg"},
+		{"Preview", readyPreview(), model.VariantPreview, fiber.StatusOK, "sha256/ab/abcd.preview.jpg"},
+		{"Not ready", &model.AttachmentPreview{Status: model.PreviewPending}, model.VariantThumbnail, fiber.StatusNotFound, ""},
+		{"No preview", nil, model.VariantPreview, fiber.StatusNotFound, ""},
+		{"Invalid variant", readyPreview(), "original", fiber.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achievementID := primitive.NewObjectID()
+			achRepo := new(MockAchievementRepo)
+			store := new(MockStorage)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, store: store})
+
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: "stu-1",
+				Attachments: []model.Attachment{{
+					ID: "att-1", FileName: "sertifikat.pdf", Key: "sha256/ab/abcd", FileType: "application/pdf",
+					Hash: "abcd", Preview: tt.preview,
+				}},
+			}, nil)
+			store.On("Get", mock.Anything, tt.expectedKey).Return(io.NopCloser(strings.NewReader("jpeg")), nil)
+
+			app := fiber.New()
+			app.Get("/:id/attachments/:attachmentId", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+				return svc.DownloadAttachment(c)
+			})
+
+			req := httptest.NewRequest("GET", "/"+achievementID.Hex()+"/attachments/att-1?variant="+tt.variant, nil)
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == fiber.StatusOK {
+				assert.Equal(t, "image/jpeg", resp.Header.Get(fiber.HeaderContentType))
+				assert.Equal(t, `inline; filename=sertifikat-`+tt.variant+`.jpg`, resp.Header.Get(fiber.HeaderContentDisposition))
+			}
+		})
+	}
+}
+
+func readyPreview() *model.AttachmentPreview {
+	return &model.AttachmentPreview{
+		Status:       model.PreviewReady,
+		ThumbnailKey: "sha256/ab/abcd.thumb.jpg",
+		PreviewKey:   "sha256/ab/abcd.preview.jpg",
+	}
+}
+
+/*
+=================================================
+TEST: URL PRATINJAU & PEMBERSIHAN
+=================================================
+*/
+
+func TestAchievement_PreviewURLs(t *testing.T) {
+	id := primitive.NewObjectID()
+	a := model.Achievement{
+		ID: id,
+		Attachments: []model.Attachment{
+			{ID: "att-1", Preview: readyPreview()},
+			{ID: "att-2", Preview: &model.AttachmentPreview{Status: model.PreviewPending}},
+		},
+	}
+
+	data, err := json.Marshal(a)
+	require.NoError(t, err)
+
+	var out struct {
+		Attachments []struct {
+			Preview map[string]any `json:"preview"`
+		} `json:"attachments"`
+	}
+	require.NoError(t, json.Unmarshal(data, &out))
+
+	base := "/api/v1/achievements/" + id.Hex() + "/attachments/att-1"
+	assert.Equal(t, base+"?variant=thumbnail", out.Attachments[0].Preview["thumbnail_url"])
+	assert.Equal(t, base+"?variant=preview", out.Attachments[0].Preview["preview_url"])
+	assert.NotContains(t, out.Attachments[0].Preview, "thumbnailKey")
+	assert.NotContains(t, out.Attachments[1].Preview, "thumbnail_url")
+	assert.Empty(t, a.Attachments[0].Preview.ThumbnailURL, "dokumen asli tidak diubah")
+}
+
+func TestAchievementService_DeleteAttachmentRemovesPreviews(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	versionRepo := new(MockVersionRepo)
+	store := new(MockStorage)
+	svc := newAchievementService(achievementDeps{
+		achRepo:     achRepo,
+		refRepo:     refRepo,
+		versionRepo: versionRepo,
+		store:       store,
+	})
+
+	refRepo.On("GetByAchievementID", mock.Anything, achievementID.Hex()).Return(&model.AchievementReference{
+		MongoAchievementID: achievementID.Hex(), StudentID: "stu-1", Status: model.StatusDraft,
+	}, nil)
+	achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+		ID: achievementID, StudentID: "stu-1",
+		Attachments: []model.Attachment{{ID: "att-1", Key: "sha256/ab/abcd", Hash: "abcd", Preview: readyPreview()}},
+	}, nil)
+	achRepo.On("RemoveAttachment", mock.Anything, achievementID, "att-1").Return(true, nil)
+	achRepo.On("AttachmentInUse", mock.Anything, "sha256/ab/abcd", achievementID).Return(false, nil)
+	store.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	versionRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/"+achievementID.Hex()+"/attachments/att-1", nil)
+	resp, _ := newEditApp(svc).Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	for _, key := range []string{"sha256/ab/abcd", "sha256/ab/abcd.thumb.jpg", "sha256/ab/abcd.preview.jpg"} {
+		store.AssertCalled(t, "Delete", mock.Anything, key)
+	}
+}
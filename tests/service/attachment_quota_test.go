@@ -0,0 +1,323 @@
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// kuota kecil supaya mudah dilewati: prestasi 1 MB, mahasiswa 2 MB
+func setSmallQuotas(t *testing.T) {
+	t.Setenv("ATTACHMENT_QUOTA_ACHIEVEMENT_MB", "1")
+	t.Setenv("ATTACHMENT_QUOTA_STUDENT_MB", "2")
+}
+
+type quotaResponseBody struct {
+	Message string              `json:"message"`
+	Quota   model.QuotaExceeded `json:"quota"`
+}
+
+/*
+=================================================
+TEST: KUOTA SAAT UPLOAD
+=================================================
+*/
+
+func TestAchievementService_UploadAttachmentQuota(t *testing.T) {
+	setSmallQuotas(t)
+	achievementID := primitive.NewObjectID()
+	pdf := "%PDF-1.4\n3 0 obj"
+
+	tests := []struct {
+		name           string
+		existing       []model.Attachment
+		studentUsed    int64
+		usageErr       error
+		sessions       []model.UploadSession
+		expectedStatus int
+		expectedScope  string
+	}{
+		{"Within quota", []model.Attachment{{ID: "att-1", Hash: "aaaa", Size: 512 << 10}}, 1 << 20, nil, nil, fiber.StatusOK, ""},
+		{"Achievement quota", []model.Attachment{{ID: "att-1", Hash: "aaaa", Size: 1 << 20}}, 1 << 20, nil, nil, fiber.StatusRequestEntityTooLarge, model.QuotaScopeAchievement},
+		{"Achievement quota - open upload", nil, 0, nil, []model.UploadSession{{AchievementID: achievementID.Hex(), Size: 1 << 20}}, fiber.StatusRequestEntityTooLarge, model.QuotaScopeAchievement},
+		{"Student quota", nil, 2 << 20, nil, nil, fiber.StatusRequestEntityTooLarge, model.QuotaScopeStudent},
+		{"Student quota - open upload elsewhere", nil, 1 << 20, nil, []model.UploadSession{{AchievementID: "lain", Size: 1 << 20}}, fiber.StatusRequestEntityTooLarge, model.QuotaScopeStudent},
+		{"Usage lookup failure", nil, 0, errors.New("mongo down"), nil, fiber.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			uploadRepo := new(MockUploadSessionRepo)
+			store := new(MockStorage)
+			svc := newAchievementService(achievementDeps{
+				achRepo:    achRepo,
+				refRepo:    refRepo,
+				store:      store,
+				uploadRepo: uploadRepo,
+			})
+
+			refRepo.On("GetByAchievementID", mock.Anything, achievementID.Hex()).
+				Return(&model.AchievementReference{StudentID: "stu-1", Status: model.StatusDraft}, nil)
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: "stu-1", AchievementType: "competition", Attachments: tt.existing,
+			}, nil)
+			achRepo.On("StorageByStudent", mock.Anything, []string{"stu-1"}, int64(1)).
+				Return([]model.StorageUsage{{StudentID: "stu-1", UsedBytes: tt.studentUsed}}, tt.usageErr)
+			uploadRepo.On("FindOpenByStudent", mock.Anything, "stu-1", mock.Anything).Return(append([]model.UploadSession{}, tt.sessions...), nil)
+			store.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
+			store.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			achRepo.On("AddAttachment", mock.Anything, achievementID, mock.Anything).Return(true, nil)
+
+			app := fiber.New()
+			app.Post("/:id/attachments", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+				c.Locals("permissions", []string{"achievement:update"})
+				return svc.UploadAttachment(c)
+			})
+
+			body, contentType := newUploadRequest("sertifikat.pdf", "application/pdf", pdf, model.EvidenceCertificate)
+			req := httptest.NewRequest("POST", "/"+achievementID.Hex()+"/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assertCalled(t, &store.Mock, "Put", tt.expectedStatus == fiber.StatusOK)
+			if tt.expectedScope != "" {
+				var out quotaResponseBody
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+				assert.Equal(t, tt.expectedScope, out.Quota.Scope)
+				assert.Equal(t, int64(len(pdf)), out.Quota.RequestedBytes)
+				assert.Contains(t, out.Message, tt.expectedScope+" storage quota exceeded")
+			}
+		})
+	}
+}
+
+func TestAchievementService_UploadAttachmentQuotaConcurrent(t *testing.T) {
+	setSmallQuotas(t)
+	achievementID := primitive.NewObjectID()
+	files := []string{"%PDF-1.4\n5 0 obj", "%PDF-1.4\n6 0 obj"}
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	store := new(MockStorage)
+
+	// pesanan kuota sungguhan + dokumen prestasi bersama: sisa kuota cukup untuk satu file
+	var docMu sync.Mutex
+	attachments := []model.Attachment{{ID: "att-1", Hash: "aaaa", Size: 1<<20 - int64(len(files[0]))}}
+	ledger := &quotaLedger{}
+	refRepo.On("ReserveStudentQuota", mock.Anything, mock.Anything).Return(ledger, nil)
+	refRepo.On("ReleaseQuotaReservation", mock.Anything, mock.Anything).Return(ledger)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, store: store})
+
+	refRepo.On("GetByAchievementID", mock.Anything, achievementID.Hex()).
+		Return(&model.AchievementReference{StudentID: "stu-1", Status: model.StatusDraft}, nil)
+	achRepo.On("GetByID", mock.Anything, achievementID).Return(func() *model.Achievement {
+		docMu.Lock()
+		defer docMu.Unlock()
+		return &model.Achievement{
+			ID: achievementID, StudentID: "stu-1", AchievementType: "competition",
+			Attachments: append([]model.Attachment{}, attachments...),
+		}
+	}, nil)
+	achRepo.On("AddAttachment", mock.Anything, achievementID, mock.Anything).Return(true, nil).Run(func(args mock.Arguments) {
+		docMu.Lock()
+		defer docMu.Unlock()
+		attachments = append(attachments, args.Get(2).(model.Attachment))
+	})
+	store.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
+	store.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/:id/attachments", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+		c.Locals("permissions", []string{"achievement:update"})
+		return svc.UploadAttachment(c)
+	})
+
+	statuses := make([]int, len(files))
+	var wg sync.WaitGroup
+	for i, content := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, contentType := newUploadRequest("sertifikat.pdf", "application/pdf", content, model.EvidenceCertificate)
+			req := httptest.NewRequest("POST", "/"+achievementID.Hex()+"/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			resp, err := app.Test(req)
+			if err == nil {
+				statuses[i] = resp.StatusCode
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.ElementsMatch(t, []int{fiber.StatusOK, fiber.StatusRequestEntityTooLarge}, statuses)
+	assert.Len(t, attachments, 2)
+	// pesanan dilepas setelah lampiran tercatat
+	assert.Empty(t, ledger.reserved)
+}
+
+func TestAchievementService_ReplaceAttachmentQuota(t *testing.T) {
+	setSmallQuotas(t)
+	pdf := "%PDF-1.4\n4 0 obj"
+
+	tests := []struct {
+		name           string
+		oldSize        int64
+		studentUsed    int64
+		expectedStatus int
+		lookup         bool
+	}{
+		// file pengganti lebih kecil → kuota tidak perlu dihitung
+		{"Smaller file - no lookup", 1 << 20, 0, fiber.StatusOK, false},
+		{"Larger file - student quota", 0, 2 << 20, fiber.StatusRequestEntityTooLarge, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achievementID := primitive.NewObjectID()
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			uploadRepo := new(MockUploadSessionRepo)
+			versionRepo := new(MockVersionRepo)
+			store := new(MockStorage)
+			svc := newAchievementService(achievementDeps{
+				achRepo:     achRepo,
+				refRepo:     refRepo,
+				versionRepo: versionRepo,
+				store:       store,
+				uploadRepo:  uploadRepo,
+			})
+
+			refRepo.On("GetByAchievementID", mock.Anything, achievementID.Hex()).Return(&model.AchievementReference{
+				MongoAchievementID: achievementID.Hex(), StudentID: "stu-1", Status: model.StatusDraft,
+			}, nil)
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: "stu-1", AchievementType: "competition",
+				Attachments: []model.Attachment{{ID: "att-1", Key: "sha256/aa/aaaa", Hash: "aaaa", Size: tt.oldSize}},
+			}, nil)
+			if tt.lookup {
+				achRepo.On("StorageByStudent", mock.Anything, []string{"stu-1"}, int64(1)).
+					Return([]model.StorageUsage{{StudentID: "stu-1", UsedBytes: tt.studentUsed}}, nil)
+				uploadRepo.On("FindOpenByStudent", mock.Anything, "stu-1", mock.Anything).Return([]model.UploadSession{}, nil)
+			}
+			store.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
+			store.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			store.On("Delete", mock.Anything, mock.Anything).Return(nil)
+			achRepo.On("AttachmentInUse", mock.Anything, "sha256/aa/aaaa", achievementID).Return(false, nil)
+			achRepo.On("ReplaceAttachment", mock.Anything, achievementID, "att-1", mock.Anything).Return(true, nil)
+			versionRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			body, contentType := newUploadRequest("benar.pdf", "application/pdf", pdf, "")
+			req := httptest.NewRequest("PUT", "/"+achievementID.Hex()+"/attachments/att-1", body)
+			req.Header.Set("Content-Type", contentType)
+			resp, _ := newEditApp(svc).Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assertCalled(t, &uploadRepo.Mock, "FindOpenByStudent", tt.lookup)
+			assertCalled(t, &achRepo.Mock, "ReplaceAttachment", tt.expectedStatus == fiber.StatusOK)
+		})
+	}
+}
+
+func TestAchievementService_CreateUploadQuota(t *testing.T) {
+	setSmallQuotas(t)
+	f := newResumableFixture()
+	f.uploadRepo.ExpectedCalls = nil
+	f.achRepo.ExpectedCalls = nil
+	f.achRepo.On("GetByID", mock.Anything, f.achievementID).Return(&model.Achievement{ID: f.achievementID, StudentID: "stu-1"}, nil)
+	f.achRepo.On("StorageByStudent", mock.Anything, []string{"stu-1"}, int64(1)).
+		Return([]model.StorageUsage{{StudentID: "stu-1", UsedBytes: 1 << 20}}, nil)
+	// upload bertahap lain yang belum selesai sudah memesan sisa kuota
+	f.uploadRepo.On("FindOpenByStudent", mock.Anything, "stu-1", mock.Anything).
+		Return([]model.UploadSession{{AchievementID: "lain", Size: 768 << 10}}, nil)
+
+	req := httptest.NewRequest("POST", "/"+f.achievementID.Hex()+"/uploads", strings.NewReader(`{"file_name":"video.mp4","size":524288}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := f.app.Test(req)
+
+	assert.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+	var out quotaResponseBody
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Equal(t, model.QuotaExceeded{
+		Scope: model.QuotaScopeStudent, UsedBytes: (1 << 20) + (768 << 10), QuotaBytes: 2 << 20, RequestedBytes: 512 << 10,
+	}, out.Quota)
+	f.uploadRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+/*
+=================================================
+TEST: LAPORAN PENYIMPANAN
+=================================================
+*/
+
+func TestReportService_GetStorageReport(t *testing.T) {
+	setSmallQuotas(t)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedLimit int64
+	}{
+		{"Default limit", "", 10},
+		{"Custom limit", "?limit=3", 3},
+		{"Limit too large", "?limit=1000", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			svc := service.NewReportService(nil, achRepo)
+
+			achRepo.On("StorageTotal", mock.Anything).Return(model.StorageUsage{UsedBytes: 5 << 20, Attachments: 7}, nil)
+			achRepo.On("StorageByStudent", mock.Anything, []string(nil), tt.expectedLimit).Return([]model.StorageUsage{
+				{StudentID: "stu-1", UsedBytes: 3 << 20}, {StudentID: "stu-2", UsedBytes: 2 << 20},
+			}, nil)
+			achRepo.On("StorageByAchievement", mock.Anything, tt.expectedLimit).Return([]model.AchievementStorageUsage{
+				{StudentID: "stu-1", Title: "Video lomba", UsedBytes: 3 << 20, Attachments: 1},
+			}, nil)
+
+			app := fiber.New()
+			app.Get("/reports/storage", svc.GetStorageReport)
+			resp, _ := app.Test(httptest.NewRequest("GET", "/reports/storage"+tt.query, nil))
+			require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			data, _ := io.ReadAll(resp.Body)
+			var report model.StorageReport
+			require.NoError(t, json.Unmarshal(data, &report))
+			assert.Equal(t, int64(5<<20), report.TotalBytes)
+			assert.Equal(t, 7, report.TotalAttachments)
+			assert.Equal(t, "stu-1", report.TopStudents[0].StudentID)
+			assert.Equal(t, int64(2<<20), report.TopStudents[0].QuotaBytes)
+			assert.Equal(t, int64(1<<20), report.TopAchievements[0].QuotaBytes)
+		})
+	}
+}
+
+func TestReportService_GetStorageReportFailure(t *testing.T) {
+	achRepo := new(MockAchievementRepo)
+	svc := service.NewReportService(nil, achRepo)
+	achRepo.On("StorageTotal", mock.Anything).Return(model.StorageUsage{}, errors.New("mongo down"))
+
+	app := fiber.New()
+	app.Get("/reports/storage", svc.GetStorageReport)
+	resp, _ := app.Test(httptest.NewRequest("GET", "/reports/storage", nil))
+
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
@@ -0,0 +1,539 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"uas-backend/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockUploadSessionRepo struct{ mock.Mock }
+
+func (m *MockUploadSessionRepo) Create(ctx context.Context, s *model.UploadSession) error {
+	args := m.Called(ctx, s)
+	if args.Error(0) == nil {
+		s.ID = primitive.NewObjectID()
+	}
+	return args.Error(0)
+}
+
+func (m *MockUploadSessionRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*model.UploadSession, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UploadSession), args.Error(1)
+}
+
+func (m *MockUploadSessionRepo) Advance(ctx context.Context, s *model.UploadSession, from int64) (bool, error) {
+	args := m.Called(ctx, s, from)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUploadSessionRepo) ClaimFinish(ctx context.Context, id primitive.ObjectID, now, staleBefore time.Time) (bool, error) {
+	args := m.Called(ctx, id, now, staleBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUploadSessionRepo) ReleaseFinish(ctx context.Context, id primitive.ObjectID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockUploadSessionRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockUploadSessionRepo) FindExpired(ctx context.Context, before time.Time) ([]model.UploadSession, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).([]model.UploadSession), args.Error(1)
+}
+
+func (m *MockUploadSessionRepo) FindOpenByStudent(ctx context.Context, studentID string, now time.Time) ([]model.UploadSession, error) {
+	args := m.Called(ctx, studentID, now)
+	return args.Get(0).([]model.UploadSession), args.Error(1)
+}
+
+// memStorage: Put / Get / Delete MockStorage diarahkan ke map, supaya potongan bisa dibaca ulang
+type memStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemStorage(store *MockStorage) *memStorage {
+	m := &memStorage{objects: map[string][]byte{}}
+	store.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		data, _ := io.ReadAll(args.Get(2).(io.Reader))
+		m.mu.Lock()
+		m.objects[args.String(1)] = data
+		m.mu.Unlock()
+	}).Return(nil)
+	store.On("Get", mock.Anything, mock.Anything).Return(func(key string) io.ReadCloser {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return io.NopCloser(bytes.NewReader(m.objects[key]))
+	}, nil)
+	store.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
+	store.On("Delete", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		m.mu.Lock()
+		delete(m.objects, args.String(1))
+		m.mu.Unlock()
+	}).Return(nil)
+	return m
+}
+
+func (m *memStorage) keys(prefix string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := []string{}
+	for k := range m.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+type resumableFixture struct {
+	achievementID primitive.ObjectID
+	achRepo       *MockAchievementRepo
+	uploadRepo    *MockUploadSessionRepo
+	store         *MockStorage
+	mem           *memStorage
+	app           *fiber.App
+}
+
+// newResumableFixture: prestasi draft milik stu-1 (user-1), tipe default (pdf / gambar / video, upload bertahap maks. 100 MB)
+func newResumableFixture(attachments ...model.Attachment) *resumableFixture {
+	f := &resumableFixture{
+		achievementID: primitive.NewObjectID(),
+		achRepo:       new(MockAchievementRepo),
+		uploadRepo:    new(MockUploadSessionRepo),
+		store:         new(MockStorage),
+	}
+	refRepo := new(MockReferenceRepo)
+	f.mem = newMemStorage(f.store)
+	svc := newAchievementService(achievementDeps{
+		achRepo:    f.achRepo,
+		refRepo:    refRepo,
+		store:      f.store,
+		uploadRepo: f.uploadRepo,
+	})
+
+	refRepo.On("GetByAchievementID", mock.Anything, f.achievementID.Hex()).Return(&model.AchievementReference{
+		MongoAchievementID: f.achievementID.Hex(), StudentID: "stu-1", Status: model.StatusDraft,
+	}, nil)
+	f.achRepo.On("GetByID", mock.Anything, f.achievementID).Return(&model.Achievement{
+		ID: f.achievementID, StudentID: "stu-1", Attachments: attachments,
+	}, nil)
+	f.achRepo.On("StorageByStudent", mock.Anything, mock.Anything, mock.Anything).Return([]model.StorageUsage{}, nil)
+	f.uploadRepo.On("FindOpenByStudent", mock.Anything, "stu-1", mock.Anything).Return([]model.UploadSession{}, nil)
+	// klaim penggabungan: default belum ada request lain yang menggabungkan
+	f.uploadRepo.On("ClaimFinish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Maybe()
+	f.uploadRepo.On("ReleaseFinish", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	f.app = fiber.New()
+	withClaims := func(h fiber.Handler) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+			c.Locals("permissions", []string{"achievement:update"})
+			return h(c)
+		}
+	}
+	f.app.Post("/:id/uploads", withClaims(svc.CreateUpload))
+	f.app.Get("/:id/uploads/:uploadId", withClaims(svc.GetUpload))
+	f.app.Patch("/:id/uploads/:uploadId", withClaims(svc.PatchUpload))
+	f.app.Delete("/:id/uploads/:uploadId", withClaims(svc.CancelUpload))
+	return f
+}
+
+// session: sesi yang sudah menerima received (disimpan sebagai satu potongan di storage)
+func (f *resumableFixture) session(content []byte, received int) *model.UploadSession {
+	s := &model.UploadSession{
+		ID:            primitive.NewObjectID(),
+		AchievementID: f.achievementID.Hex(),
+		FileName:      "video.png",
+		Kind:          model.EvidencePhoto,
+		Size:          int64(len(content)),
+		Offset:        int64(received),
+		Chunks:        []model.UploadChunk{},
+		CreatedBy:     "user-1",
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+	if received > 0 {
+		key := "uploads/" + s.ID.Hex() + "/000000000000-first"
+		f.mem.objects[key] = content[:received]
+		s.Chunks = append(s.Chunks, model.UploadChunk{Key: key, Size: int64(received)})
+		s.Head = append([]byte(nil), content[:min(received, 512)]...)
+
+		h := sha256.New()
+		h.Write(content[:received])
+		s.HashState, _ = h.(encoding.BinaryMarshaler).MarshalBinary()
+	}
+	f.uploadRepo.On("GetByID", mock.Anything, s.ID).Return(s, nil)
+	return s
+}
+
+func (f *resumableFixture) patch(s *model.UploadSession, offset int64, chunk []byte, checksum string) *httptestResponse {
+	req := httptest.NewRequest("PATCH", "/"+f.achievementID.Hex()+"/uploads/"+s.ID.Hex(), bytes.NewReader(chunk))
+	req.Header.Set("Content-Type", "application/offset+octet-stream")
+	req.Header.Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	if checksum != "" {
+		req.Header.Set("Upload-Checksum", checksum)
+	}
+	resp, _ := f.app.Test(req)
+	body, _ := io.ReadAll(resp.Body)
+	return &httptestResponse{StatusCode: resp.StatusCode, Offset: resp.Header.Get("Upload-Offset"), Body: body}
+}
+
+type httptestResponse struct {
+	StatusCode int
+	Offset     string
+	Body       []byte
+}
+
+func chunkChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256 " + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+/*
+=================================================
+TEST: MULAI UPLOAD BERTAHAP
+=================================================
+*/
+
+func TestAchievementService_CreateUpload(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		created        bool
+	}{
+		{"Success", `{"file_name":"../video.png","size":8388608,"kind":"photo"}`, fiber.StatusCreated, true},
+		{"Success - default kind with checksum", `{"file_name":"a.pdf","size":10,"checksum":"` + strings.Repeat("AB", 32) + `"}`, fiber.StatusCreated, true},
+		{"Success - larger than single upload limit", `{"file_name":"video.mp4","size":52428800,"kind":"photo"}`, fiber.StatusCreated, true},
+		{"Too large", `{"file_name":"video.mp4","size":209715200}`, fiber.StatusRequestEntityTooLarge, false},
+		{"Missing size", `{"file_name":"a.pdf"}`, fiber.StatusBadRequest, false},
+		{"Invalid kind", `{"file_name":"a.pdf","size":10,"kind":"selfie"}`, fiber.StatusBadRequest, false},
+		{"Invalid checksum", `{"file_name":"a.pdf","size":10,"checksum":"abc"}`, fiber.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newResumableFixture()
+			f.uploadRepo.On("Create", mock.Anything, mock.MatchedBy(func(s *model.UploadSession) bool {
+				return s.CreatedBy == "user-1" && s.StudentID == "stu-1" && s.Offset == 0 && s.FileName != "../video.png" &&
+					s.ExpiresAt.After(time.Now()) && (s.Checksum == "" || s.Checksum == strings.Repeat("ab", 32))
+			})).Return(nil)
+
+			req := httptest.NewRequest("POST", "/"+f.achievementID.Hex()+"/uploads", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := f.app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assertCalled(t, &f.uploadRepo.Mock, "Create", tt.created)
+			if tt.created {
+				assert.Equal(t, "0", resp.Header.Get("Upload-Offset"))
+				assert.Contains(t, resp.Header.Get("Location"), "/api/v1/achievements/"+f.achievementID.Hex()+"/uploads/")
+			}
+		})
+	}
+}
+
+/*
+=================================================
+TEST: KIRIM POTONGAN
+=================================================
+*/
+
+func TestAchievementService_PatchUpload(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 1000)
+	chunk := content[:100]
+
+	tests := []struct {
+		name           string
+		offset         int64
+		chunk          []byte
+		checksum       string
+		modify         func(s *model.UploadSession)
+		advanced       bool
+		expectedStatus int
+		expectedOffset string
+		put            bool
+	}{
+		{"Chunk received", 0, chunk, chunkChecksum(chunk), nil, true, fiber.StatusOK, "100", true},
+		{"Offset mismatch", 50, chunk, "", nil, true, fiber.StatusConflict, "0", false},
+		{"Missing offset", -1, chunk, "", nil, true, fiber.StatusBadRequest, "", false},
+		{"Empty chunk", 0, nil, "", nil, true, fiber.StatusBadRequest, "", false},
+		{"Checksum mismatch", 0, chunk, chunkChecksum([]byte("lain")), nil, true, 460, "", false},
+		{"Unsupported checksum", 0, chunk, "md5 abc", nil, true, fiber.StatusBadRequest, "", false},
+		{"Exceeds upload size", 0, content, "", func(s *model.UploadSession) { s.Size = 10 }, true, fiber.StatusRequestEntityTooLarge, "", false},
+		{"Concurrent chunk won", 0, chunk, "", nil, false, fiber.StatusConflict, "", true},
+		{"Expired", 0, chunk, "", func(s *model.UploadSession) { s.ExpiresAt = time.Now().Add(-time.Minute) }, true, fiber.StatusGone, "", false},
+		{"Other user's session", 0, chunk, "", func(s *model.UploadSession) { s.CreatedBy = "user-2" }, true, fiber.StatusNotFound, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newResumableFixture()
+			s := f.session(content, 0)
+			if tt.modify != nil {
+				tt.modify(s)
+			}
+			f.uploadRepo.On("Advance", mock.Anything, s, int64(0)).Return(tt.advanced, nil)
+
+			var resp *httptestResponse
+			if tt.offset < 0 {
+				req := httptest.NewRequest("PATCH", "/"+f.achievementID.Hex()+"/uploads/"+s.ID.Hex(), bytes.NewReader(tt.chunk))
+				r, _ := f.app.Test(req)
+				resp = &httptestResponse{StatusCode: r.StatusCode}
+			} else {
+				resp = f.patch(s, tt.offset, tt.chunk, tt.checksum)
+			}
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode, string(resp.Body))
+			assert.Equal(t, tt.expectedOffset, resp.Offset)
+			assertCalled(t, &f.store.Mock, "Put", tt.put)
+			// potongan yang kalah balapan tidak ditinggal di storage
+			if !tt.advanced || tt.expectedStatus != fiber.StatusOK {
+				assert.Empty(t, f.mem.keys("uploads/"))
+			}
+		})
+	}
+}
+
+func TestAchievementService_PatchUploadRejectsTypeEarly(t *testing.T) {
+	// zip 1 MB: ditolak begitu 512 byte pertama masuk, sesi & potongan dibuang
+	content := append([]byte("PK\x03\x04"), bytes.Repeat([]byte{0}, 1<<20)...)
+	f := newResumableFixture()
+	s := f.session(content, 0)
+	f.uploadRepo.On("Delete", mock.Anything, s.ID).Return(nil)
+
+	resp := f.patch(s, 0, content[:600], "")
+
+	assert.Equal(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+	f.uploadRepo.AssertCalled(t, "Delete", mock.Anything, s.ID)
+	f.uploadRepo.AssertNotCalled(t, "Advance", mock.Anything, mock.Anything, mock.Anything)
+	assertCalled(t, &f.store.Mock, "Put", false)
+}
+
+func TestAchievementService_PatchUploadVideo(t *testing.T) {
+	// mp4 (box ftyp): boleh untuk dokumentasi, ditolak sebagai sertifikat
+	content := append([]byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"), bytes.Repeat([]byte{0}, 1000)...)
+
+	tests := []struct {
+		name           string
+		kind           string
+		expectedStatus int
+	}{
+		{"Photo kind", model.EvidencePhoto, fiber.StatusOK},
+		{"Other kind", model.EvidenceOther, fiber.StatusOK},
+		{"Certificate kind", model.EvidenceCertificate, fiber.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newResumableFixture()
+			s := f.session(content, 0)
+			s.Kind = tt.kind
+			f.uploadRepo.On("Advance", mock.Anything, s, int64(0)).Return(true, nil)
+			f.uploadRepo.On("Delete", mock.Anything, s.ID).Return(nil)
+
+			resp := f.patch(s, 0, content[:600], "")
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode, string(resp.Body))
+			assertCalled(t, &f.uploadRepo.Mock, "Delete", tt.expectedStatus != fiber.StatusOK)
+		})
+	}
+}
+
+/*
+=================================================
+TEST: UPLOAD SELESAI
+=================================================
+*/
+
+func TestAchievementService_FinishUpload(t *testing.T) {
+	content := pngBytes(t, 64, 64)
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	half := len(content) / 2
+
+	tests := []struct {
+		name           string
+		checksum       string
+		attachments    []model.Attachment
+		expectedStatus int
+		added          bool
+	}{
+		{"Success", "", nil, fiber.StatusCreated, true},
+		{"Success - file checksum", hash, nil, fiber.StatusCreated, true},
+		{"File checksum mismatch", strings.Repeat("0", 64), nil, 460, false},
+		{"Duplicate content", "", []model.Attachment{{ID: "att-1", Hash: hash}}, fiber.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newResumableFixture(tt.attachments...)
+			s := f.session(content, half)
+			s.Checksum = tt.checksum
+			f.uploadRepo.On("Advance", mock.Anything, s, int64(half)).Return(true, nil)
+			f.uploadRepo.On("Delete", mock.Anything, s.ID).Return(nil)
+			f.achRepo.On("AddAttachment", mock.Anything, f.achievementID, mock.Anything).Return(true, nil)
+
+			resp := f.patch(s, int64(half), content[half:], chunkChecksum(content[half:]))
+
+			require.Equal(t, tt.expectedStatus, resp.StatusCode, string(resp.Body))
+			assertCalled(t, &f.achRepo.Mock, "AddAttachment", tt.added)
+			// sesi & potongan sementara selalu dibuang
+			f.uploadRepo.AssertCalled(t, "Delete", mock.Anything, s.ID)
+			assert.Empty(t, f.mem.keys("uploads/"))
+
+			if tt.added {
+				key := "sha256/" + hash[:2] + "/" + hash
+				assert.Equal(t, content, f.mem.objects[key], "potongan digabung berurutan")
+
+				var out struct {
+					Data model.Attachment `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(resp.Body, &out))
+				assert.Equal(t, hash, out.Data.Hash)
+				assert.Equal(t, "image/png", out.Data.FileType)
+				assert.Equal(t, model.EvidencePhoto, out.Data.Kind)
+				assert.Equal(t, int64(len(content)), out.Data.Size)
+			}
+		})
+	}
+}
+
+func TestAchievementService_FinishUploadRetry(t *testing.T) {
+	// gagal simpan ke MongoDB → sesi tetap; PATCH kosong di offset akhir mengulang
+	content := pngBytes(t, 16, 16)
+	f := newResumableFixture()
+	s := f.session(content, len(content))
+	f.achRepo.On("AddAttachment", mock.Anything, f.achievementID, mock.Anything).Return(false, assert.AnError).Once()
+
+	resp := f.patch(s, s.Size, nil, "")
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	f.uploadRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	assert.NotEmpty(t, f.mem.keys("uploads/"))
+
+	f.achRepo.On("AddAttachment", mock.Anything, f.achievementID, mock.Anything).Return(true, nil)
+	f.uploadRepo.On("Delete", mock.Anything, s.ID).Return(nil)
+
+	resp = f.patch(s, s.Size, nil, "")
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Empty(t, f.mem.keys("uploads/"))
+	// klaim percobaan pertama dilepas supaya retry bisa menggabungkan
+	f.uploadRepo.AssertNumberOfCalls(t, "ReleaseFinish", 1)
+}
+
+func TestAchievementService_FinishUploadClaimed(t *testing.T) {
+	// PATCH akhir paralel / retry saat penggabungan masih berjalan → 409, tidak dicatat dua kali
+	content := pngBytes(t, 16, 16)
+	f := newResumableFixture()
+	s := f.session(content, len(content))
+	f.uploadRepo.ExpectedCalls = slices.DeleteFunc(f.uploadRepo.ExpectedCalls, func(c *mock.Call) bool {
+		return c.Method == "ClaimFinish"
+	})
+	f.uploadRepo.On("ClaimFinish", mock.Anything, s.ID, mock.Anything, mock.Anything).Return(false, nil)
+
+	resp := f.patch(s, s.Size, nil, "")
+
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	f.achRepo.AssertNotCalled(t, "AddAttachment", mock.Anything, mock.Anything, mock.Anything)
+	f.uploadRepo.AssertNotCalled(t, "ReleaseFinish", mock.Anything, mock.Anything)
+	assert.NotEmpty(t, f.mem.keys("uploads/"))
+}
+
+func TestAchievementService_FinishUploadAlreadyAttached(t *testing.T) {
+	// isi yang sama tercatat duluan oleh request lain → lampiran lama, file tidak dihapus
+	content := pngBytes(t, 16, 16)
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	f := newResumableFixture()
+	s := f.session(content, len(content))
+	f.achRepo.On("AddAttachment", mock.Anything, f.achievementID, mock.Anything).Return(false, nil)
+	f.uploadRepo.On("Delete", mock.Anything, s.ID).Return(nil)
+
+	resp := f.patch(s, s.Size, nil, "")
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	assert.Contains(t, string(resp.Body), "attachment already uploaded")
+	assert.Equal(t, content, f.mem.objects["sha256/"+hash[:2]+"/"+hash])
+	assert.Empty(t, f.mem.keys("uploads/"))
+}
+
+/*
+=================================================
+TEST: BATAL & PEMBERSIHAN
+=================================================
+*/
+
+func TestAchievementService_CancelUpload(t *testing.T) {
+	f := newResumableFixture()
+	s := f.session(bytes.Repeat([]byte("x"), 100), 40)
+	f.uploadRepo.On("Delete", mock.Anything, s.ID).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/"+f.achievementID.Hex()+"/uploads/"+s.ID.Hex(), nil)
+	resp, _ := f.app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	f.uploadRepo.AssertCalled(t, "Delete", mock.Anything, s.ID)
+	assert.Empty(t, f.mem.keys("uploads/"))
+}
+
+func TestAchievementService_GetUpload(t *testing.T) {
+	f := newResumableFixture()
+	s := f.session(bytes.Repeat([]byte("x"), 100), 40)
+
+	req := httptest.NewRequest("HEAD", "/"+f.achievementID.Hex()+"/uploads/"+s.ID.Hex(), nil)
+	resp, _ := f.app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "40", resp.Header.Get("Upload-Offset"))
+	assert.Equal(t, "100", resp.Header.Get("Upload-Length"))
+}
+
+func TestAchievementService_CleanupUploads(t *testing.T) {
+	uploadRepo := new(MockUploadSessionRepo)
+	store := new(MockStorage)
+	svc := newAchievementService(achievementDeps{store: store, uploadRepo: uploadRepo})
+
+	now := time.Now()
+	expired := []model.UploadSession{
+		{ID: primitive.NewObjectID(), Chunks: []model.UploadChunk{{Key: "uploads/a/0"}, {Key: "uploads/a/1"}}},
+		{ID: primitive.NewObjectID()},
+	}
+	uploadRepo.On("FindExpired", mock.Anything, now).Return(expired, nil)
+	uploadRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	store.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	n, err := svc.CleanupUploads(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	store.AssertCalled(t, "Delete", mock.Anything, "uploads/a/0")
+	store.AssertCalled(t, "Delete", mock.Anything, "uploads/a/1")
+	uploadRepo.AssertNumberOfCalls(t, "Delete", 2)
+}
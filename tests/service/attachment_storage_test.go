@@ -0,0 +1,212 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockStorage struct{ mock.Mock }
+
+func (m *MockStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	return m.Called(ctx, key, r, size, contentType).Error(0)
+}
+
+func (m *MockStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	// isi bisa dihitung dari key (object yang di-Put sebelumnya)
+	if fn, ok := args.Get(0).(func(key string) io.ReadCloser); ok {
+		return fn(key), args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockStorage) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	args := m.Called(ctx, key, offset, length)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	// isi rentang bisa dihitung dari offset & length
+	if fn, ok := args.Get(0).(func(offset, length int64) io.ReadCloser); ok {
+		return fn(offset, length), args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockStorage) Exists(ctx context.Context, key string) (bool, error) {
+	args := m.Called(ctx, key)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStorage) Delete(ctx context.Context, key string) error {
+	return m.Called(ctx, key).Error(0)
+}
+
+// newUploadRequest: body multipart dengan satu file + field kind
+func newUploadRequest(fileName, contentType, content, kind string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="file"; filename="`+fileName+`"`)
+	h.Set("Content-Type", contentType)
+	part, _ := w.CreatePart(h)
+	part.Write([]byte(content))
+	if kind != "" {
+		w.WriteField("kind", kind)
+	}
+	w.Close()
+
+	return body, w.FormDataContentType()
+}
+
+/*
+=================================================
+TEST: UPLOAD KE STORAGE
+=================================================
+*/
+
+func TestAchievementService_UploadAttachmentStorage(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+
+	pdf := "%PDF-1.4\n1 0 obj"
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	sum := sha256.Sum256([]byte(pdf))
+	pdfHash := hex.EncodeToString(sum[:])
+	pdfKey := "sha256/" + pdfHash[:2] + "/" + pdfHash
+
+	tests := []struct {
+		name            string
+		achievementType string
+		content         string
+		existing        []model.Attachment
+		stored          bool // object sudah ada di storage
+		putErr          error
+		addErr          error
+		expectedStatus  int
+		expectedType    string
+		put, add, del   bool
+	}{
+		{"Success - content-addressed, sniffed type", "competition", pdf, nil, false, nil, nil, fiber.StatusOK, "application/pdf", true, true, false},
+		{"Success - image for competition", "competition", png, nil, false, nil, nil, fiber.StatusOK, "image/png", true, true, false},
+		{"Dedupe - object already stored", "competition", pdf, nil, true, nil, nil, fiber.StatusOK, "application/pdf", false, true, false},
+		{"Dedupe - already attached", "competition", pdf, []model.Attachment{{Hash: pdfHash, Key: pdfKey}}, true, nil, nil, fiber.StatusOK, "", false, false, false},
+		{"Unsupported - text disguised as pdf", "competition", "hello, not a pdf", nil, false, nil, nil, fiber.StatusUnsupportedMediaType, "", false, false, false},
+		{"Unsupported - image for publication", "publication", png, nil, false, nil, nil, fiber.StatusUnsupportedMediaType, "", false, false, false},
+		{"Storage failure", "competition", pdf, nil, false, errors.New("bucket down"), nil, fiber.StatusInternalServerError, "application/pdf", true, false, false},
+		{"MongoDB failure - new object removed", "competition", pdf, nil, false, nil, errors.New("mongo down"), fiber.StatusInternalServerError, "application/pdf", true, true, true},
+		{"MongoDB failure - shared object kept", "competition", pdf, nil, true, nil, errors.New("mongo down"), fiber.StatusInternalServerError, "application/pdf", false, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			store := new(MockStorage)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, store: store})
+
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).
+				Return(&model.AchievementReference{StudentID: "stu-1", Status: model.StatusDraft}, nil)
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: "stu-1", AchievementType: tt.achievementType, Attachments: tt.existing,
+			}, nil)
+			store.On("Exists", mock.Anything, mock.Anything).Return(tt.stored, nil)
+			store.On("Put", mock.Anything, mock.Anything, mock.Anything, int64(len(tt.content)), tt.expectedType).Return(tt.putErr)
+			store.On("Delete", mock.Anything, mock.Anything).Return(nil)
+			achRepo.On("AddAttachment", mock.Anything, achievementID, mock.MatchedBy(func(att model.Attachment) bool {
+				return strings.HasPrefix(att.Key, "sha256/") && !strings.Contains(att.Key, "sertifikat") &&
+					att.FileName == "sertifikat juara.pdf" && att.FileType == tt.expectedType &&
+					att.Size == int64(len(tt.content)) && att.Kind == model.EvidenceCertificate &&
+					att.Preview != nil && att.Preview.Status == model.PreviewPending
+			})).Return(tt.addErr == nil, tt.addErr)
+
+			app := fiber.New()
+			app.Post("/:id/attachments", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+				c.Locals("permissions", []string{"achievement:update"})
+				return svc.UploadAttachment(c)
+			})
+
+			// nama dari client berisi path → hanya basename yang disimpan
+			body, contentType := newUploadRequest("../../etc/sertifikat juara.pdf", "application/pdf", tt.content, model.EvidenceCertificate)
+			req := httptest.NewRequest("POST", "/"+achievementIDHex+"/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assertCalled(t, &store.Mock, "Put", tt.put)
+			assertCalled(t, &achRepo.Mock, "AddAttachment", tt.add)
+			assertCalled(t, &store.Mock, "Delete", tt.del)
+			if tt.content == pdf && tt.put {
+				store.AssertCalled(t, "Put", mock.Anything, pdfKey, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAchievementService_UploadAttachmentTooLarge(t *testing.T) {
+	t.Setenv("ATTACHMENT_MAX_SIZE_MB", "1")
+	achievementID := primitive.NewObjectID()
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	store := new(MockStorage)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, store: store})
+
+	refRepo.On("GetByAchievementID", mock.Anything, achievementID.Hex()).
+		Return(&model.AchievementReference{StudentID: "stu-1", Status: model.StatusDraft}, nil)
+	achRepo.On("GetByID", mock.Anything, achievementID).
+		Return(&model.Achievement{ID: achievementID, StudentID: "stu-1", AchievementType: "competition"}, nil)
+
+	app := fiber.New(fiber.Config{BodyLimit: 4 << 20})
+	app.Post("/:id/attachments", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+		c.Locals("permissions", []string{"achievement:update"})
+		return svc.UploadAttachment(c)
+	})
+
+	body, contentType := newUploadRequest("big.pdf", "application/pdf", "%PDF-"+strings.Repeat("x", 1<<20), "")
+	req := httptest.NewRequest("POST", "/"+achievementID.Hex()+"/attachments", body)
+	req.Header.Set("Content-Type", contentType)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+	store.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// assertCalled: method mock dipanggil atau tidak sama sekali
+func assertCalled(t *testing.T, m *mock.Mock, method string, called bool) {
+	t.Helper()
+	n := 0
+	for _, call := range m.Calls {
+		if call.Method == method {
+			n++
+		}
+	}
+	assert.Equal(t, called, n > 0, method)
+}
+
+func TestAttachment_StorageKey(t *testing.T) {
+	assert.Equal(t, "achievements/a/1-x.pdf", model.Attachment{Key: "achievements/a/1-x.pdf", FileURL: "/uploads/x.pdf"}.StorageKey())
+	assert.Equal(t, "x.pdf", model.Attachment{FileURL: "/uploads/x.pdf"}.StorageKey(), "lampiran lama di root storage lokal")
+	assert.Equal(t, "", model.Attachment{FileURL: "https://cdn.example.com/x.pdf"}.StorageKey())
+}
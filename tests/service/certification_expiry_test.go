@@ -0,0 +1,304 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func date(s string) *time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return &t
+}
+
+/*
+=================================================
+TEST: MASA BERLAKU
+=================================================
+*/
+
+func TestAchievement_IsExpired(t *testing.T) {
+	a := model.Achievement{ValidUntil: date("2026-10-19")}
+
+	assert.False(t, a.IsExpired(time.Date(2026, 10, 19, 23, 59, 0, 0, time.UTC)), "berlaku sampai akhir hari")
+	assert.True(t, a.IsExpired(time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, (&model.Achievement{}).IsExpired(time.Now()), "tanpa validUntil tidak pernah kedaluwarsa")
+
+	raw, _ := json.Marshal(model.Achievement{ValidUntil: date("2020-01-01")})
+	assert.Contains(t, string(raw), `"expired":true`)
+}
+
+func TestAchievementService_CreateCertificationValidity(t *testing.T) {
+	tests := []struct {
+		name           string
+		validUntil     string
+		expectedStatus int
+	}{
+		{"Success - validity from details", "2029-10-01", fiber.StatusCreated},
+		{"Bad request - validUntil before issuedDate", "2026-09-01", fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, stuRepo: stuRepo})
+
+			oid := primitive.NewObjectID()
+			stuRepo.On("GetStudentProfile", mock.Anything, "user-1").Return(&model.Student{ID: "stu-1"}, nil)
+			achRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *model.Achievement) bool {
+				return a.ValidFrom.Equal(*date("2026-10-01")) && a.ValidUntil.Equal(*date(tt.validUntil))
+			})).Return(oid, nil)
+			refRepo.On("CreateDraft", mock.Anything, "stu-1", oid.Hex(), "user-1").Return(nil)
+			allowDuplicateCheck(achRepo, stuRepo)
+
+			app := fiber.New()
+			app.Post("/achievements", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+				return svc.CreateAchievement(c)
+			})
+
+			body := `{"achievementType":"certification","title":"AWS","details":{"certificationName":"AWS SAA","issuer":"AWS","issued_date":"2026-10-01","valid_until":"` + tt.validUntil + `"}}`
+			req := httptest.NewRequest("POST", "/achievements", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != fiber.StatusCreated {
+				achRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+/*
+=================================================
+TEST: PENGINGAT KEDALUWARSA
+=================================================
+*/
+
+func TestAchievementService_RunExpiryReminders(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	due := model.Achievement{
+		ID: primitive.NewObjectID(), StudentID: "stu-1", Title: "AWS", ValidUntil: date("2026-11-01"),
+		Members: []model.AchievementMember{{StudentID: "stu-1", Role: model.MemberRoleLeader}, {StudentID: "stu-2"}},
+	}
+	notVerified := model.Achievement{ID: primitive.NewObjectID(), StudentID: "stu-3", ValidUntil: date("2026-11-01")}
+	reminded := model.Achievement{ID: primitive.NewObjectID(), StudentID: "stu-4", ValidUntil: date("2026-11-01"), RemindedFor: date("2026-11-01")}
+	renewed := model.Achievement{ID: primitive.NewObjectID(), StudentID: "stu-5", ValidUntil: date("2026-11-01"), RenewedByID: "new"}
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	stuRepo := new(MockStudentRepo)
+	notifRepo := new(MockNotificationRepo)
+	svc := newAchievementService(achievementDeps{
+		achRepo:   achRepo,
+		refRepo:   refRepo,
+		stuRepo:   stuRepo,
+		notifRepo: notifRepo,
+	})
+
+	achRepo.On("FindExpiring", mock.Anything, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), now.Add(30*24*time.Hour)).
+		Return([]model.Achievement{due, notVerified, reminded, renewed}, nil)
+	refRepo.On("GetByAchievementID", mock.Anything, due.ID.Hex()).Return(&model.AchievementReference{Status: model.StatusVerified}, nil)
+	refRepo.On("GetByAchievementID", mock.Anything, notVerified.ID.Hex()).Return(&model.AchievementReference{Status: model.StatusSubmitted}, nil)
+	achRepo.On("MarkExpiryReminded", mock.Anything, due.ID, *due.ValidUntil).Return(true, nil)
+	stuRepo.On("GetStudentByID", mock.Anything, mock.Anything).Return(&model.Student{UserID: "user-x"}, nil)
+	notifRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *model.Notification) bool {
+		return n.Type == model.NotificationCertExpiring && n.AchievementID == due.ID.Hex() &&
+			strings.Contains(n.Message, "13 hari lagi")
+	})).Return(nil)
+
+	sent, err := svc.RunExpiryReminders(context.Background(), now, 30*24*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	notifRepo.AssertNumberOfCalls(t, "Create", 2) // pemilik + anggota
+	achRepo.AssertNumberOfCalls(t, "MarkExpiryReminded", 1)
+}
+
+/*
+=================================================
+TEST: PERPANJANGAN
+=================================================
+*/
+
+func TestAchievementService_RenewAchievement(t *testing.T) {
+	oldID := primitive.NewObjectID()
+	newID := primitive.NewObjectID()
+
+	tests := []struct {
+		name           string
+		status         string
+		renewedBy      string
+		claimed        bool
+		draftErr       error
+		expectedStatus int
+		created        bool
+		released       bool
+	}{
+		{"Success - renew verified certification", model.StatusVerified, "", true, nil, fiber.StatusCreated, true, false},
+		{"Conflict - not verified", model.StatusSubmitted, "", true, nil, fiber.StatusConflict, false, false},
+		{"Conflict - already renewed", model.StatusVerified, primitive.NewObjectID().Hex(), true, nil, fiber.StatusConflict, false, false},
+		{"Conflict - renewed concurrently", model.StatusVerified, "", false, nil, fiber.StatusConflict, false, false},
+		{"Reference failure - claim released", model.StatusVerified, "", true, errors.New("pg down"), fiber.StatusInternalServerError, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, stuRepo: stuRepo})
+
+			achRepo.On("GetByID", mock.Anything, oldID).Return(&model.Achievement{
+				ID: oldID, StudentID: "stu-1", AchievementType: "certification", Title: "AWS SAA",
+				Details: map[string]any{
+					"certificationName": "AWS SAA", "issuedBy": "AWS", "certificationNumber": "OLD-1",
+					"issuedDate": "2023-10-01", "validUntil": "2026-10-01",
+				},
+				ValidUntil:  date("2026-10-01"),
+				RenewedByID: tt.renewedBy,
+			}, nil)
+			refRepo.On("GetByAchievementID", mock.Anything, oldID.Hex()).
+				Return(&model.AchievementReference{MongoAchievementID: oldID.Hex(), Status: tt.status}, nil)
+
+			// klaim atomik memakai ID prestasi baru yang sama dengan dokumen yang dibuat
+			var claimedBy string
+			achRepo.On("ClaimRenewal", mock.Anything, oldID, mock.Anything).Run(func(args mock.Arguments) {
+				claimedBy = args.String(2)
+			}).Return(tt.claimed, nil)
+			achRepo.On("ReleaseRenewal", mock.Anything, oldID, mock.Anything).Return(nil)
+			achRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *model.Achievement) bool {
+				_, hasNumber := a.Details["certificationNumber"]
+				return a.RenewsID == oldID.Hex() && a.Title == "AWS SAA" && !hasNumber &&
+					a.ValidUntil.Equal(*date("2029-10-01")) && a.ID.Hex() == claimedBy
+			})).Return(newID, nil)
+			achRepo.On("Remove", mock.Anything, newID).Return(nil)
+			refRepo.On("CreateDraft", mock.Anything, "stu-1", newID.Hex(), "user-1").Return(tt.draftErr)
+			refRepo.On("Discard", mock.Anything, newID.Hex()).Return(nil)
+			allowDuplicateCheck(achRepo, stuRepo)
+
+			app := fiber.New()
+			app.Post("/:id/renew", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+				return svc.RenewAchievement(c)
+			})
+
+			body := `{"details":{"issuedDate":"2026-10-01","validUntil":"2029-10-01"}}`
+			req := httptest.NewRequest("POST", "/"+oldID.Hex()+"/renew", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assertCalled(t, &achRepo.Mock, "Create", tt.created)
+			assertCalled(t, &achRepo.Mock, "ReleaseRenewal", tt.released)
+			if tt.released {
+				achRepo.AssertCalled(t, "ReleaseRenewal", mock.Anything, oldID, claimedBy)
+			}
+			// dokumen lama tidak lagi ditimpa utuh
+			achRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAchievementService_RenewalDraftTrash(t *testing.T) {
+	oldID := primitive.NewObjectID()
+	renewalID := primitive.NewObjectID()
+	renewalIDHex := renewalID.Hex()
+	claims := &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"}
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo})
+
+	ref := &model.AchievementReference{MongoAchievementID: renewalIDHex, StudentID: "stu-1", Status: model.StatusDraft}
+	refRepo.On("GetByAchievementID", mock.Anything, renewalIDHex).Return(ref, nil)
+	refRepo.On("GetMemberIDs", mock.Anything, renewalIDHex).Return([]string{}, nil)
+	refRepo.On("UpdateStatus", mock.Anything, renewalIDHex, mock.Anything).Return(ref, nil)
+	achRepo.On("SoftDelete", mock.Anything, renewalID).Return(nil)
+	achRepo.On("Restore", mock.Anything, renewalID).Return(nil)
+	achRepo.On("GetByID", mock.Anything, renewalID).
+		Return(&model.Achievement{ID: renewalID, StudentID: "stu-1", RenewsID: oldID.Hex()}, nil)
+	achRepo.On("ClaimRenewal", mock.Anything, oldID, renewalIDHex).Return(true, nil)
+
+	call := func(method, route, path string, handler fiber.Handler) int {
+		app := fiber.New()
+		app.Add(method, route, func(c *fiber.Ctx) error {
+			c.Locals("user", claims)
+			return handler(c)
+		})
+		resp, _ := app.Test(httptest.NewRequest(method, path, nil))
+		return resp.StatusCode
+	}
+
+	// 1️⃣ draft perpanjangan dihapus → sertifikasi lama lepas dari klaim
+	assert.Equal(t, fiber.StatusOK, call("DELETE", "/:id", "/"+renewalIDHex, svc.DeleteAchievement))
+	achRepo.AssertCalled(t, "ReleaseRenewalBy", mock.Anything, renewalIDHex)
+
+	// 2️⃣ dipulihkan → klaim dipasang lagi
+	ref.Status = model.StatusDeleted
+	assert.Equal(t, fiber.StatusOK, call("POST", "/:id/restore", "/"+renewalIDHex+"/restore", svc.RestoreAchievement))
+	achRepo.AssertCalled(t, "ClaimRenewal", mock.Anything, oldID, renewalIDHex)
+}
+
+/*
+=================================================
+TEST: STATISTIK TANPA SERTIFIKASI KEDALUWARSA
+=================================================
+*/
+
+func TestReportService_StatisticsExcludeExpired(t *testing.T) {
+	tests := []struct {
+		query    string
+		total    int
+		excluded int
+	}{
+		{"", 1, 1},
+		{"?include_expired=true", 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run("query="+tt.query, func(t *testing.T) {
+			reportRepo := new(MockReportRepo)
+			achRepo := new(MockAchievementRepo)
+			svc := service.NewReportService(reportRepo, achRepo)
+
+			reportRepo.On("GetVerifiedAchievementIDs", mock.Anything).Return([]string{}, nil)
+			achRepo.On("FindByIDs", mock.Anything, mock.Anything).Return([]model.Achievement{
+				{AchievementType: "certification", StudentID: "stu-1", ValidUntil: date("2020-01-01")},
+				{AchievementType: "certification", StudentID: "stu-1", ValidUntil: date("2999-01-01")},
+			}, nil)
+
+			app := fiber.New()
+			app.Get("/statistics", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Admin"})
+				return svc.GetStatistics(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/statistics"+tt.query, nil))
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			var stats model.AchievementStatisticsResponse
+			_ = json.NewDecoder(resp.Body).Decode(&stats)
+			assert.Equal(t, tt.excluded, stats.ExpiredExcluded)
+			if assert.Len(t, stats.TotalPerType, 1) {
+				assert.Equal(t, tt.total, stats.TotalPerType[0].Total)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/*
+=================================================
+TEST: SAGA COMPENSATION
+=================================================
+*/
+
+func TestAchievementService_CreateCompensatesOnReferenceFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		members    string
+		draftErr   error
+		membersErr error
+	}{
+		{"CreateDraft fails", `[]`, errors.New("pg down"), nil},
+		{"SetMembers fails", `[{"student_id":"stu-2"}]`, nil, errors.New("pg down")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			stuRepo := new(MockStudentRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, stuRepo: stuRepo})
+
+			oid := primitive.NewObjectID()
+			stuRepo.On("GetStudentProfile", mock.Anything, "user-1").Return(&model.Student{ID: "stu-1"}, nil)
+			stuRepo.On("GetStudentByID", mock.Anything, "stu-2").Return(&model.Student{ID: "stu-2"}, nil)
+			achRepo.On("Create", mock.Anything, mock.Anything).Return(oid, nil)
+			refRepo.On("CreateDraft", mock.Anything, "stu-1", oid.Hex(), "user-1").Return(tt.draftErr)
+			refRepo.On("SetMembers", mock.Anything, oid.Hex(), mock.Anything).Return(tt.membersErr)
+			refRepo.On("Discard", mock.Anything, oid.Hex()).Return(nil)
+			achRepo.On("Remove", mock.Anything, oid).Return(nil)
+
+			app := fiber.New()
+			app.Post("/achievements", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+				return svc.CreateAchievement(c)
+			})
+
+			body := `{"achievementType":"academic","title":"Tim","details":{"activityName":"Hackathon","date":"2026-05-01"},"members":` + tt.members + `}`
+			req := httptest.NewRequest("POST", "/achievements", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+			refRepo.AssertCalled(t, "Discard", mock.Anything, oid.Hex())
+			achRepo.AssertCalled(t, "Remove", mock.Anything, oid)
+		})
+	}
+}
+
+func TestAchievementService_DeleteRevertsStatusOnMongoFailure(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo})
+
+	refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).
+		Return(&model.AchievementReference{MongoAchievementID: achievementIDHex, StudentID: "stu-1", Status: model.StatusDraft}, nil)
+	refRepo.On("UpdateStatus", mock.Anything, achievementIDHex, mock.Anything).
+		Return(&model.AchievementReference{}, nil)
+	achRepo.On("SoftDelete", mock.Anything, achievementID).Return(errors.New("mongo down"))
+
+	app := fiber.New()
+	app.Delete("/:id", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+		return svc.DeleteAchievement(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/"+achievementIDHex, nil))
+
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	refRepo.AssertNumberOfCalls(t, "UpdateStatus", 2)
+	refRepo.AssertCalled(t, "UpdateStatus", mock.Anything, achievementIDHex, mock.MatchedBy(func(ch model.StatusChange) bool {
+		return ch.To == model.StatusDraft &&
+			assert.ObjectsAreEqual([]string{model.StatusDeleted}, ch.From) &&
+			ch.Note != nil && strings.HasPrefix(*ch.Note, "rollback:")
+	}))
+}
+
+func TestAchievementService_SubmitRevertsStatusOnMongoFailure(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo})
+
+	refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).
+		Return(&model.AchievementReference{MongoAchievementID: achievementIDHex, StudentID: "stu-1", Status: model.StatusDraft}, nil)
+	achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+		ID: achievementID, StudentID: "stu-1", AchievementType: "academic",
+		Details:        map[string]any{"activityName": "Asisten", "date": "2025-10-01"},
+		PointsOverride: &model.PointsOverride{Points: 99},
+	}, nil)
+	refRepo.On("Submit", mock.Anything, achievementIDHex, mock.Anything, mock.Anything).
+		Return(&model.AchievementReference{}, nil)
+	achRepo.On("SetSubmission", mock.Anything, mock.MatchedBy(func(a *model.Achievement) bool {
+		return a.PointsOverride == nil && a.Points != 99
+	})).Return(errors.New("mongo down"))
+	refRepo.On("UpdateStatus", mock.Anything, achievementIDHex, mock.Anything).
+		Return(&model.AchievementReference{}, nil)
+
+	app := fiber.New()
+	app.Post("/:id/submit", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+		return svc.SubmitAchievement(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/"+achievementIDHex+"/submit", nil))
+
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	// poin baru disimpan setelah submit di PostgreSQL lolos, dokumen tidak ditimpa utuh
+	achRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	refRepo.AssertCalled(t, "UpdateStatus", mock.Anything, achievementIDHex, mock.MatchedBy(func(ch model.StatusChange) bool {
+		return ch.To == model.StatusDraft &&
+			assert.ObjectsAreEqual([]string{model.StatusSubmitted}, ch.From) &&
+			ch.Note != nil && strings.HasPrefix(*ch.Note, "rollback:")
+	}))
+}
+
+/*
+=================================================
+TEST: RECONCILE
+=================================================
+*/
+
+func TestConsistencyService_Reconcile(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	fresh := time.Now()
+
+	healthy := primitive.NewObjectID()
+	orphanDoc := primitive.NewObjectID()
+	inFlight := primitive.NewObjectID()
+	mismatch := primitive.NewObjectID()
+	team := primitive.NewObjectID()
+	orphanRef := primitive.NewObjectID().Hex()
+
+	teamMembers := []model.AchievementMember{
+		{StudentID: "stu-1", Role: model.MemberRoleLeader},
+		{StudentID: "stu-2", Role: model.MemberRoleMember},
+	}
+	docs := []model.Achievement{
+		{ID: healthy, CreatedAt: old, UpdatedAt: old},
+		{ID: orphanDoc, StudentID: "stu-1", Members: teamMembers, CreatedAt: old, UpdatedAt: old},
+		{ID: inFlight, CreatedAt: fresh}, // create masih berjalan
+		{ID: mismatch, CreatedAt: old, UpdatedAt: old, IsDeleted: false},
+		{ID: team, StudentID: "stu-1", Members: teamMembers, CreatedAt: old, UpdatedAt: old},
+	}
+	refs := []*model.AchievementReference{
+		{MongoAchievementID: healthy.Hex(), Status: model.StatusVerified, CreatedAt: old, UpdatedAt: old},
+		{MongoAchievementID: mismatch.Hex(), Status: model.StatusDeleted, CreatedAt: old, UpdatedAt: old},
+		{MongoAchievementID: team.Hex(), Status: model.StatusDraft, CreatedAt: old, UpdatedAt: old},
+		{MongoAchievementID: orphanRef, Status: model.StatusDraft, CreatedAt: old, UpdatedAt: old},
+	}
+	// tim sudah jadi individu di PostgreSQL, dokumen MongoDB masih menyimpan anggota lama
+	members := map[string][]model.AchievementMember{}
+
+	for _, repair := range []bool{false, true} {
+		achRepo := new(MockAchievementRepo)
+		refRepo := new(MockReferenceRepo)
+		achRepo.On("FindSyncStates", mock.Anything).Return(docs, nil)
+		refRepo.On("GetAll", mock.Anything).Return(refs, nil)
+		refRepo.On("GetAllMembers", mock.Anything).Return(members, nil)
+		refRepo.On("RecreateDeleted", mock.Anything, "stu-1", orphanDoc.Hex(), mock.Anything).Return(nil)
+		refRepo.On("SetMembers", mock.Anything, orphanDoc.Hex(), teamMembers).Return(nil)
+		achRepo.On("SoftDelete", mock.Anything, orphanDoc).Return(nil)
+		achRepo.On("SyncStatus", mock.Anything, mismatch, model.StatusDeleted).Return(errors.New("mongo down"))
+		achRepo.On("SyncMembers", mock.Anything, team, []model.AchievementMember(nil)).Return(nil)
+		refRepo.On("Discard", mock.Anything, orphanRef).Return(nil)
+
+		report, err := service.NewConsistencyService(achRepo, refRepo).Reconcile(context.Background(), repair, 15*time.Minute)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, report.Documents)
+		assert.Equal(t, 4, report.References)
+		assert.Equal(t, 1, report.Skipped)
+
+		var kinds []string
+		for _, issue := range report.Issues {
+			kinds = append(kinds, issue.Kind+":"+issue.AchievementID)
+		}
+		assert.Equal(t, []string{
+			model.IssueOrphanDocument + ":" + orphanDoc.Hex(),
+			model.IssueStatusMismatch + ":" + mismatch.Hex(),
+			model.IssueMemberMismatch + ":" + team.Hex(),
+			model.IssueOrphanReference + ":" + orphanRef,
+		}, kinds)
+
+		// dokumen tanpa reference tidak pernah dibuang langsung
+		achRepo.AssertNotCalled(t, "Remove", mock.Anything, mock.Anything)
+
+		if !repair {
+			refRepo.AssertNotCalled(t, "RecreateDeleted", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			achRepo.AssertNotCalled(t, "SoftDelete", mock.Anything, mock.Anything)
+			achRepo.AssertNotCalled(t, "SyncStatus", mock.Anything, mock.Anything, mock.Anything)
+			refRepo.AssertNotCalled(t, "SetMembers", mock.Anything, mock.Anything, mock.Anything)
+			achRepo.AssertNotCalled(t, "SyncMembers", mock.Anything, mock.Anything, mock.Anything)
+			refRepo.AssertNotCalled(t, "Discard", mock.Anything, mock.Anything)
+			continue
+		}
+
+		// orphan: reference dibuat ulang + dokumen masuk tempat sampah
+		assert.True(t, report.Issues[0].Repaired)
+		achRepo.AssertCalled(t, "SoftDelete", mock.Anything, orphanDoc)
+		assert.False(t, report.Issues[1].Repaired)
+		assert.Equal(t, "mongo down", report.Issues[1].Error)
+		// anggota: PostgreSQL menang, dokumen yang basi ikut disamakan
+		assert.True(t, report.Issues[2].Repaired)
+		achRepo.AssertCalled(t, "SyncMembers", mock.Anything, team, []model.AchievementMember(nil))
+		refRepo.AssertNotCalled(t, "SetMembers", mock.Anything, team.Hex(), mock.Anything)
+		assert.True(t, report.Issues[3].Repaired)
+	}
+}
+
+func TestAchievementService_UpdateRestoresMembersOnMongoFailure(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	stuRepo := new(MockStudentRepo)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, stuRepo: stuRepo})
+
+	previous := []model.AchievementMember{
+		{StudentID: "stu-1", Role: model.MemberRoleLeader},
+		{StudentID: "stu-2", Role: model.MemberRoleMember},
+	}
+	refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).
+		Return(&model.AchievementReference{MongoAchievementID: achievementIDHex, StudentID: "stu-1", Status: model.StatusDraft}, nil)
+	achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+		ID: achievementID, StudentID: "stu-1", AchievementType: "academic", Members: previous,
+	}, nil)
+	stuRepo.On("GetStudentByID", mock.Anything, "stu-3").Return(&model.Student{ID: "stu-3"}, nil)
+	refRepo.On("SetMembers", mock.Anything, achievementIDHex, mock.Anything).Return(nil)
+	achRepo.On("Update", mock.Anything, mock.Anything).Return(errors.New("mongo down"))
+
+	app := fiber.New()
+	app.Put("/:id", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+		c.Locals("permissions", []string{"achievement:update"})
+		return svc.UpdateAchievement(c)
+	})
+
+	req := httptest.NewRequest("PUT", "/"+achievementIDHex, strings.NewReader(`{"members":[{"student_id":"stu-3"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	// anggota baru ditulis ke PostgreSQL dulu, lalu dikembalikan karena dokumen gagal disimpan
+	refRepo.AssertNumberOfCalls(t, "SetMembers", 2)
+	refRepo.AssertCalled(t, "SetMembers", mock.Anything, achievementIDHex, previous)
+}
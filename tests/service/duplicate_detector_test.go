@@ -0,0 +1,229 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/*
+=================================================
+TEST: DUPLICATE SCORE
+=================================================
+*/
+
+func TestDuplicateScore(t *testing.T) {
+	base := &model.Achievement{
+		Title: "Juara 1 GEMASTIK 2025",
+		Details: map[string]any{
+			"competitionName": "GEMASTIK",
+			"eventDate":       "2025-10-01",
+		},
+		Attachments: []model.Attachment{{FileName: "sertifikat.pdf", Hash: "abc"}},
+	}
+
+	tests := []struct {
+		name      string
+		other     *model.Achievement
+		duplicate bool
+		reasons   []string
+	}{
+		{"Same file", &model.Achievement{Title: "lain", Attachments: []model.Attachment{{Hash: "abc"}}},
+			true, []string{model.DuplicateAttachment}},
+		{"Same title after normalization", &model.Achievement{Title: "juara 1 - gemastik 2025!"},
+			true, []string{model.DuplicateTitle}},
+		{"Similar title + same event and date", &model.Achievement{
+			Title:   "Juara 1 GEMASTIK",
+			Details: map[string]any{"competitionName": "Gemastik", "eventDate": "2025-10-01"},
+		}, true, []string{model.DuplicateSimilarName, model.DuplicateEvent, model.DuplicateEventDate}},
+		{"Only same date", &model.Achievement{Title: "Lomba lain", Details: map[string]any{"date": "2025-10-01"}},
+			false, []string{model.DuplicateEventDate}},
+		{"Unrelated", &model.Achievement{Title: "Sertifikasi AWS"}, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := service.DuplicateScore(base, tt.other)
+			assert.Equal(t, tt.duplicate, score >= 0.5)
+			assert.LessOrEqual(t, score, 1.0)
+			assert.Equal(t, tt.reasons, reasons)
+		})
+	}
+
+	// nomor sertifikat sama → duplikat walau judul beda
+	score, reasons := service.DuplicateScore(
+		&model.Achievement{Title: "AWS", Details: map[string]any{"certificationNumber": "AWS-123"}},
+		&model.Achievement{Title: "Cloud Practitioner", Details: map[string]any{"certificationNumber": "aws 123"}},
+	)
+	assert.Equal(t, 1.0, score)
+	assert.Equal(t, []string{model.DuplicateCertNumber}, reasons)
+}
+
+/*
+=================================================
+TEST: DUPLICATE DETECTOR
+=================================================
+*/
+
+func TestDuplicateDetector_Find(t *testing.T) {
+	self := primitive.NewObjectID()
+	mine := primitive.NewObjectID()
+	classmate := primitive.NewObjectID()
+	stranger := primitive.NewObjectID()
+	unrelated := primitive.NewObjectID()
+
+	a := &model.Achievement{
+		ID:          self,
+		StudentID:   "stu-1",
+		Title:       "Juara 1 GEMASTIK 2025",
+		Attachments: []model.Attachment{{Hash: "abc"}},
+	}
+
+	achRepo := new(MockAchievementRepo)
+	stuRepo := new(MockStudentRepo)
+	stuRepo.On("GetStudentByID", mock.Anything, "stu-1").Return(&model.Student{ID: "stu-1", AdvisorID: "lec-1"}, nil)
+	stuRepo.On("GetStudentsByAdvisor", mock.Anything, "lec-1").Return([]*model.Student{{ID: "stu-1"}, {ID: "stu-2"}}, nil)
+	achRepo.On("FindByStudentIDs", mock.Anything, []string{"stu-1", "stu-2"}).Return([]model.Achievement{
+		*a, // dirinya sendiri tidak ikut dilaporkan
+		{ID: mine, StudentID: "stu-1", Title: "juara 1 gemastik 2025"},
+		{ID: classmate, StudentID: "stu-2", Title: "Juara 1 GEMASTIK", Details: map[string]any{"competitionName": "GEMASTIK"}},
+		{ID: unrelated, StudentID: "stu-2", Title: "Ketua BEM"},
+	}, nil)
+	achRepo.On("FindByAttachmentHashes", mock.Anything, []string{"abc"}).Return([]model.Achievement{
+		{ID: stranger, StudentID: "stu-9", Title: "Sertifikat", Attachments: []model.Attachment{{Hash: "abc"}}},
+	}, nil)
+
+	got, err := service.NewDuplicateDetector(achRepo, stuRepo).Find(context.Background(), a)
+	assert.NoError(t, err)
+
+	var ids []string
+	for _, c := range got {
+		ids = append(ids, c.AchievementID)
+	}
+	// skor tertinggi dulu: file sama (1.0) → judul sama (0.5); classmate hanya judul mirip (0.35)
+	assert.Equal(t, []string{stranger.Hex(), mine.Hex()}, ids)
+}
+
+/*
+=================================================
+TEST: REVIEWER DUPLICATE PANEL
+=================================================
+*/
+
+func TestAchievementService_GetAchievementByID_PossibleDuplicates(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	other := primitive.NewObjectID()
+
+	for _, tc := range []struct {
+		role      string
+		withPanel bool
+	}{
+		{"Admin", true},
+		{"Mahasiswa", false},
+	} {
+		t.Run(tc.role, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			stuRepo := new(MockStudentRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, stuRepo: stuRepo})
+
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: "stu-1", Title: "Juara 1 GEMASTIK",
+			}, nil)
+			stuRepo.On("GetStudentByID", mock.Anything, "stu-1").Return(&model.Student{ID: "stu-1"}, nil)
+			achRepo.On("FindByStudentIDs", mock.Anything, []string{"stu-1"}).Return([]model.Achievement{
+				{ID: other, StudentID: "stu-1", Title: "Juara 1 Gemastik"},
+			}, nil)
+			achRepo.On("FindByAttachmentHashes", mock.Anything, mock.Anything).Return([]model.Achievement{}, nil)
+
+			app := fiber.New()
+			app.Get("/:id", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "u-1", Role: tc.role, StudentID: "stu-1"})
+				return svc.GetAchievementByID(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/"+achievementID.Hex(), nil))
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			var out struct {
+				PossibleDuplicates []model.DuplicateCandidate `json:"possible_duplicates"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+			if tc.withPanel {
+				assert.Len(t, out.PossibleDuplicates, 1)
+				assert.Equal(t, other.Hex(), out.PossibleDuplicates[0].AchievementID)
+			} else {
+				assert.Empty(t, out.PossibleDuplicates)
+				achRepo.AssertNotCalled(t, "FindByStudentIDs", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+/*
+=================================================
+TEST: STUDENT DUPLICATE WARNINGS
+=================================================
+*/
+
+func TestAchievementService_CreateWarningsHideOtherStudents(t *testing.T) {
+	mine := primitive.NewObjectID()
+	stranger := primitive.NewObjectID()
+
+	achRepo := new(MockAchievementRepo)
+	refRepo := new(MockReferenceRepo)
+	stuRepo := new(MockStudentRepo)
+	svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo, stuRepo: stuRepo})
+
+	oid := primitive.NewObjectID()
+	stuRepo.On("GetStudentProfile", mock.Anything, "user-1").Return(&model.Student{ID: "stu-1"}, nil)
+	stuRepo.On("GetStudentByID", mock.Anything, "stu-1").Return(&model.Student{ID: "stu-1"}, nil)
+	achRepo.On("Create", mock.Anything, mock.Anything).Return(oid, nil)
+	refRepo.On("CreateDraft", mock.Anything, "stu-1", oid.Hex(), "user-1").Return(nil)
+	achRepo.On("FindByStudentIDs", mock.Anything, []string{"stu-1"}).Return([]model.Achievement{
+		{ID: mine, StudentID: "stu-1", Title: "Asisten Praktikum"},
+	}, nil)
+	// prestasi mahasiswa lain yang kebetulan mirip
+	achRepo.On("FindByAttachmentHashes", mock.Anything, mock.Anything).Return([]model.Achievement{
+		{ID: stranger, StudentID: "stu-9", Title: "Asisten Praktikum"},
+	}, nil)
+
+	app := fiber.New()
+	app.Post("/achievements", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"})
+		return svc.CreateAchievement(c)
+	})
+
+	body := `{"achievementType":"academic","title":"Asisten Praktikum","details":{"activityName":"Praktikum","date":"2026-05-01"}}`
+	req := httptest.NewRequest("POST", "/achievements", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	var out struct {
+		Warnings []map[string]any `json:"warnings"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	if assert.Len(t, out.Warnings, 2) {
+		byID := map[string]map[string]any{}
+		for _, w := range out.Warnings {
+			id, _ := w["achievement_id"].(string)
+			byID[id] = w
+		}
+		// milik sendiri lengkap; milik mahasiswa lain hanya skor & alasan
+		assert.Equal(t, "stu-1", byID[mine.Hex()]["student_id"])
+		hidden := byID[""]
+		assert.NotNil(t, hidden["score"])
+		assert.NotContains(t, hidden, "student_id")
+		assert.NotContains(t, hidden, "title")
+	}
+}
@@ -0,0 +1,224 @@
+package service_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uas-backend/app/model"
+	"uas-backend/app/repository"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ====================
+// MOCK REPORT REPO
+// ====================
+type MockReportRepo struct{ mock.Mock }
+
+func (m *MockReportRepo) GetVerifiedAchievementIDs(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockReportRepo) GetVerifiedAchievementIDsByStudent(ctx context.Context, studentID string) ([]string, error) {
+	args := m.Called(ctx, studentID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+/*
+=================================================
+TEST: CALCULATE POINTS
+=================================================
+*/
+
+func TestPointsRules_Calculate(t *testing.T) {
+	rules, err := service.LoadPointsRules("")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		achieve  model.Achievement
+		expected int
+	}{
+		{"international champion", model.Achievement{AchievementType: "competition",
+			Details: map[string]any{"competitionLevel": "international", "rank": int64(1)}}, 100},
+		{"national third (mongo int32)", model.Achievement{AchievementType: "competition",
+			Details: map[string]any{"competitionLevel": "national", "rank": int32(3)}}, 50},
+		{"national finalist", model.Achievement{AchievementType: "competition",
+			Details: map[string]any{"competitionLevel": "national", "rank": int64(7)}}, 40},
+		{"scopus journal", model.Achievement{AchievementType: "publication",
+			Details: map[string]any{"publicationType": "journal", "indexing": "scopus"}}, 80},
+		{"unindexed conference", model.Achievement{AchievementType: "publication",
+			Details: map[string]any{"publicationType": "conference"}}, 40},
+		{"organization chair", model.Achievement{AchievementType: "organization",
+			Details: map[string]any{"position": "Ketua"}}, 40},
+		{"organization member", model.Achievement{AchievementType: "organization",
+			Details: map[string]any{"position": "Anggota"}}, 15},
+		{"unknown type", model.Achievement{AchievementType: "hobby"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, _ := rules.Calculate(&tt.achieve)
+			assert.Equal(t, tt.expected, points)
+		})
+	}
+}
+
+func TestPointsRules_VersionAndOverride(t *testing.T) {
+	a, _ := service.NewPointsRules(service.DefaultPointRules())
+	b, _ := service.NewPointsRules(service.DefaultPointRules())
+	assert.Equal(t, a.Version(), b.Version())
+
+	changed := service.DefaultPointRules()
+	changed[0].Points = 150
+	c, _ := service.NewPointsRules(changed)
+	assert.NotEqual(t, a.Version(), c.Version())
+
+	// override verifikator tetap dipakai saat dihitung ulang
+	achievement := &model.Achievement{
+		AchievementType: "certification",
+		PointsOverride:  &model.PointsOverride{Points: 45, Justification: "sertifikasi internasional"},
+	}
+	c.Apply(achievement)
+	assert.Equal(t, 45, achievement.Points)
+	assert.Equal(t, 30, achievement.PointsOverride.CalculatedPoints)
+	assert.Equal(t, c.Version(), achievement.PointsVersion)
+
+	_, err := service.NewPointsRules([]model.PointRule{{AchievementType: "academic", Points: -1}})
+	assert.Error(t, err)
+}
+
+/*
+=================================================
+TEST: RECALCULATE VERIFIED
+=================================================
+*/
+
+func TestPointsService_RecalculateVerified(t *testing.T) {
+	rules, _ := service.NewPointsRules(service.DefaultPointRules())
+
+	stale := primitive.NewObjectID()
+	current := primitive.NewObjectID()
+
+	reportRepo := new(MockReportRepo)
+	achRepo := new(MockAchievementRepo)
+	svc := service.NewPointsService(rules, reportRepo, achRepo)
+
+	reportRepo.On("GetVerifiedAchievementIDs", mock.Anything).Return([]string{stale.Hex(), current.Hex()}, nil)
+	achRepo.On("FindByIDs", mock.Anything, mock.Anything).Return([]model.Achievement{
+		{ID: stale, AchievementType: "academic", Points: 99, PointsVersion: "old"},
+		{ID: current, AchievementType: "academic", Points: 20, PointsVersion: rules.Version()},
+	}, nil)
+	achRepo.On("SetRecalculatedPoints", mock.Anything, mock.MatchedBy(func(a *model.Achievement) bool {
+		return a.ID == stale && a.Points == 20 && a.PointsVersion == rules.Version()
+	}), "old").Return(true, nil)
+
+	updated, err := svc.RecalculateVerified(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, updated)
+	achRepo.AssertNumberOfCalls(t, "SetRecalculatedPoints", 1)
+	// dokumen tidak ditimpa utuh (perubahan request lain tidak hilang)
+	achRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPointsService_RecalculateVerifiedSkipsChanged(t *testing.T) {
+	rules, _ := service.NewPointsRules(service.DefaultPointRules())
+	id := primitive.NewObjectID()
+
+	reportRepo := new(MockReportRepo)
+	achRepo := new(MockAchievementRepo)
+	svc := service.NewPointsService(rules, reportRepo, achRepo)
+
+	reportRepo.On("GetVerifiedAchievementIDs", mock.Anything).Return([]string{id.Hex()}, nil)
+	achRepo.On("FindByIDs", mock.Anything, mock.Anything).Return([]model.Achievement{
+		{ID: id, AchievementType: "academic", Points: 99, PointsVersion: "old"},
+	}, nil)
+	// versi sudah diubah request lain sejak dibaca → tidak cocok
+	achRepo.On("SetRecalculatedPoints", mock.Anything, mock.Anything, "old").Return(false, nil)
+
+	updated, err := svc.RecalculateVerified(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, updated)
+}
+
+/*
+=================================================
+TEST: VERIFIER OVERRIDE
+=================================================
+*/
+
+func TestAchievementService_VerifyPointsOverride(t *testing.T) {
+	achievementID := primitive.NewObjectID()
+	achievementIDHex := achievementID.Hex()
+	studentID := "stu-123"
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		overridden     bool
+		approveErr     error
+	}{
+		{"Success - override with justification", `{"points":45,"justification":"tingkat internasional"}`, fiber.StatusOK, true, nil},
+		{"Success - no override", `{"note":"ok"}`, fiber.StatusOK, false, nil},
+		{"Bad request - missing justification", `{"points":45}`, fiber.StatusBadRequest, false, nil},
+		{"Bad request - negative points", `{"points":-5,"justification":"x"}`, fiber.StatusBadRequest, false, nil},
+		// approve gagal → override dikembalikan
+		{"Conflict - override restored", `{"points":45,"justification":"tingkat internasional"}`, fiber.StatusConflict, true, repository.ErrStatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achRepo := new(MockAchievementRepo)
+			refRepo := new(MockReferenceRepo)
+			svc := newAchievementService(achievementDeps{achRepo: achRepo, refRepo: refRepo})
+
+			ref := &model.AchievementReference{StudentID: studentID, Status: model.StatusSubmitted}
+			achRepo.On("GetByID", mock.Anything, achievementID).Return(&model.Achievement{
+				ID: achievementID, StudentID: studentID, AchievementType: "certification", Points: 30,
+			}, nil)
+			achRepo.On("SetPoints", mock.Anything, mock.MatchedBy(func(a *model.Achievement) bool {
+				return a.Points == 45 && a.PointsOverride != nil &&
+					a.PointsOverride.CalculatedPoints == 30 && a.PointsOverride.By == "admin-1"
+			})).Return(nil).Once()
+			achRepo.On("SetPoints", mock.Anything, mock.MatchedBy(func(a *model.Achievement) bool {
+				return a.Points == 30 && a.PointsOverride == nil
+			})).Return(nil).Once()
+			refRepo.On("GetByAchievementID", mock.Anything, achievementIDHex).Return(ref, nil)
+			refRepo.On("GetApprovals", mock.Anything, achievementIDHex).Return([]model.AchievementApproval{}, nil)
+			refRepo.On("Approve", mock.Anything, achievementIDHex, mock.Anything, mock.Anything, "").Return(ref, tt.approveErr)
+
+			app := fiber.New()
+			app.Post("/:id/verify", func(c *fiber.Ctx) error {
+				c.Locals("user", &model.JWTClaims{UserID: "admin-1", Role: "Admin"})
+				return svc.VerifyAchievement(c)
+			})
+
+			req := httptest.NewRequest("POST", "/"+achievementIDHex+"/verify", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			// hanya field poin yang ditulis, dokumen tidak ditimpa utuh
+			achRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			switch {
+			case tt.approveErr != nil:
+				achRepo.AssertNumberOfCalls(t, "SetPoints", 2)
+			case tt.overridden:
+				achRepo.AssertNumberOfCalls(t, "SetPoints", 1)
+			default:
+				achRepo.AssertNotCalled(t, "SetPoints", mock.Anything, mock.Anything)
+			}
+			if tt.expectedStatus == fiber.StatusBadRequest {
+				refRepo.AssertNotCalled(t, "Approve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uas-backend/app/model"
+	"uas-backend/app/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type reviewFixture struct {
+	svc       *service.ReviewService
+	refRepo   *MockReferenceRepo
+	achRepo   *MockAchievementRepo
+	stuRepo   *MockStudentRepo
+	lecRepo   *MockLecturerRepo
+	userRepo  *MockUserRepoUserSvc
+	notifRepo *MockNotificationRepo
+}
+
+func newReviewFixture() *reviewFixture {
+	f := &reviewFixture{
+		refRepo:   new(MockReferenceRepo),
+		achRepo:   new(MockAchievementRepo),
+		stuRepo:   new(MockStudentRepo),
+		lecRepo:   new(MockLecturerRepo),
+		userRepo:  new(MockUserRepoUserSvc),
+		notifRepo: new(MockNotificationRepo),
+	}
+	workflow, _ := service.NewAchievementWorkflow(service.DefaultWorkflowDefinition())
+	approvals, _ := service.NewApprovalChains(service.DefaultApprovalChains())
+	slas, _ := service.NewReviewSLAs(service.DefaultReviewSLAs())
+	f.svc = service.NewReviewService(
+		f.refRepo, f.achRepo, f.stuRepo, f.lecRepo, f.userRepo, f.notifRepo,
+		workflow, approvals, slas,
+	)
+	return f
+}
+
+func pendingRef(achievementID primitive.ObjectID, studentID, level string, submittedAt time.Time) *model.AchievementReference {
+	return &model.AchievementReference{
+		StudentID:          studentID,
+		MongoAchievementID: achievementID.Hex(),
+		Status:             model.StatusSubmitted,
+		SubmittedAt:        &submittedAt,
+		PendingLevel:       &level,
+	}
+}
+
+/*
+=================================================
+TEST: REVIEW QUEUE
+=================================================
+*/
+
+func TestReviewService_GetPendingReviews(t *testing.T) {
+	adviseeAch := primitive.NewObjectID()
+	strangerAch := primitive.NewObjectID()
+	facultyAch := primitive.NewObjectID()
+	oldAch := primitive.NewObjectID()
+	teamAch := primitive.NewObjectID()
+	now := time.Now()
+
+	refs := []*model.AchievementReference{
+		pendingRef(oldAch, "stu-1", "advisor", now.Add(-100*time.Hour)),
+		pendingRef(adviseeAch, "stu-1", "advisor", now.Add(-2*time.Hour)),
+		pendingRef(strangerAch, "stu-2", "advisor", now.Add(-2*time.Hour)),
+		pendingRef(teamAch, "stu-2", "advisor", now.Add(-90*time.Minute)),
+		pendingRef(facultyAch, "stu-1", "faculty", now.Add(-1*time.Hour)),
+	}
+	achievements := []model.Achievement{
+		{ID: oldAch, StudentID: "stu-1", AchievementType: "academic"},
+		{ID: adviseeAch, StudentID: "stu-1", AchievementType: "competition"},
+		{ID: strangerAch, StudentID: "stu-2", AchievementType: "competition"},
+		// prestasi tim milik stu-2, stu-1 (anak wali) ikut sebagai anggota
+		{ID: teamAch, StudentID: "stu-2", AchievementType: "organization", Members: []model.AchievementMember{
+			{StudentID: "stu-2", Role: model.MemberRoleLeader},
+			{StudentID: "stu-1", Role: model.MemberRoleMember},
+		}},
+		{ID: facultyAch, StudentID: "stu-1", AchievementType: "publication"},
+	}
+
+	tests := []struct {
+		name           string
+		claims         *model.JWTClaims
+		query          string
+		expectedStatus int
+		expectedIDs    []primitive.ObjectID
+	}{
+		{"Dosen wali sees advisees (incl. team members) at advisor level, oldest first", &model.JWTClaims{UserID: "lec-user", Role: "Dosen Wali"}, "",
+			fiber.StatusOK, []primitive.ObjectID{oldAch, adviseeAch, teamAch}},
+		{"Admin sees all levels", &model.JWTClaims{UserID: "admin-1", Role: "Admin"}, "",
+			fiber.StatusOK, []primitive.ObjectID{oldAch, adviseeAch, strangerAch, teamAch, facultyAch}},
+		{"Filter by type", &model.JWTClaims{UserID: "admin-1", Role: "Admin"}, "?type=competition",
+			fiber.StatusOK, []primitive.ObjectID{adviseeAch, strangerAch}},
+		{"Filter by student", &model.JWTClaims{UserID: "admin-1", Role: "Admin"}, "?student_id=stu-2",
+			fiber.StatusOK, []primitive.ObjectID{strangerAch, teamAch}},
+		{"Filter by level", &model.JWTClaims{UserID: "admin-1", Role: "Admin"}, "?level=faculty",
+			fiber.StatusOK, []primitive.ObjectID{facultyAch}},
+		{"Level the reviewer is not an actor of", &model.JWTClaims{UserID: "lec-user", Role: "Dosen Wali"}, "?level=faculty",
+			fiber.StatusForbidden, nil},
+		{"Mahasiswa is not a reviewer", &model.JWTClaims{UserID: "user-1", Role: "Mahasiswa", StudentID: "stu-1"}, "",
+			fiber.StatusForbidden, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newReviewFixture()
+			f.refRepo.On("GetPending", mock.Anything, []string{model.StatusSubmitted}).Return(refs, nil)
+			f.achRepo.On("FindByIDs", mock.Anything, mock.Anything).Return(achievements, nil)
+			f.lecRepo.On("GetLecturerProfile", mock.Anything, "lec-user").Return(&model.Lecturer{ID: "lec-1"}, nil)
+			f.stuRepo.On("GetStudentsByAdvisor", mock.Anything, "lec-1").Return([]*model.Student{{ID: "stu-1"}}, nil)
+
+			app := fiber.New()
+			app.Get("/reviews/pending", func(c *fiber.Ctx) error {
+				c.Locals("user", tt.claims)
+				return f.svc.GetPendingReviews(c)
+			})
+
+			resp, _ := app.Test(httptest.NewRequest("GET", "/reviews/pending"+tt.query, nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != fiber.StatusOK {
+				return
+			}
+
+			var out struct {
+				Data []model.ReviewQueueItem `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+			var got []primitive.ObjectID
+			for _, item := range out.Data {
+				got = append(got, item.Achievement.ID)
+				// academic: 72 jam → item 100 jam sudah overdue
+				assert.Equal(t, item.Achievement.ID == oldAch, item.Overdue)
+			}
+			assert.Equal(t, tt.expectedIDs, got)
+		})
+	}
+}
+
+func TestReviewService_GetPendingReviewsSkipsOwnEarlierApproval(t *testing.T) {
+	approvedByMe := primitive.NewObjectID()
+	approvedByOther := primitive.NewObjectID()
+	previousRound := primitive.NewObjectID()
+	now := time.Now()
+
+	refs := []*model.AchievementReference{
+		pendingRef(approvedByMe, "stu-1", "faculty", now.Add(-3*time.Hour)),
+		pendingRef(approvedByOther, "stu-1", "faculty", now.Add(-2*time.Hour)),
+		pendingRef(previousRound, "stu-1", "faculty", now.Add(-1*time.Hour)),
+	}
+	for _, r := range refs {
+		r.ApprovalLevel = 1
+	}
+	refs[2].ResubmissionCount = 1
+
+	f := newReviewFixture()
+	f.refRepo.On("GetPending", mock.Anything, mock.Anything).Return(refs, nil)
+	f.achRepo.On("FindByIDs", mock.Anything, mock.Anything).Return([]model.Achievement{
+		{ID: approvedByMe, StudentID: "stu-1", AchievementType: "publication"},
+		{ID: approvedByOther, StudentID: "stu-1", AchievementType: "publication"},
+		{ID: previousRound, StudentID: "stu-1", AchievementType: "publication"},
+	}, nil)
+	f.refRepo.On("GetApprovals", mock.Anything, approvedByMe.Hex()).Return([]model.AchievementApproval{
+		{Round: 0, Level: 0, LevelName: "advisor", ApprovedBy: "admin-1"},
+	}, nil)
+	f.refRepo.On("GetApprovals", mock.Anything, approvedByOther.Hex()).Return([]model.AchievementApproval{
+		{Round: 0, Level: 0, LevelName: "advisor", ApprovedBy: "lec-user"},
+	}, nil)
+	// disetujui admin-1 di ronde sebelum ditolak → ronde baru boleh lagi
+	f.refRepo.On("GetApprovals", mock.Anything, previousRound.Hex()).Return([]model.AchievementApproval{
+		{Round: 0, Level: 0, LevelName: "advisor", ApprovedBy: "admin-1"},
+		{Round: 1, Level: 0, LevelName: "advisor", ApprovedBy: "lec-user"},
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/reviews/pending", func(c *fiber.Ctx) error {
+		c.Locals("user", &model.JWTClaims{UserID: "admin-1", Role: "Admin"})
+		return f.svc.GetPendingReviews(c)
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/reviews/pending?level=faculty", nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var out struct {
+		Data []model.ReviewQueueItem `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+	var got []primitive.ObjectID
+	for _, item := range out.Data {
+		got = append(got, item.Achievement.ID)
+	}
+	// admin-1 sudah menyetujui tahap advisor di ronde ini → verify akan 409
+	assert.Equal(t, []primitive.ObjectID{approvedByOther, previousRound}, got)
+}
+
+/*
+=================================================
+TEST: SLA JOB
+=================================================
+*/
+
+func TestReviewService_RunSLACheck(t *testing.T) {
+	now := time.Now()
+	fresh := primitive.NewObjectID()
+	overdue := primitive.NewObjectID()
+	escalate := primitive.NewObjectID()
+	alreadyFlagged := primitive.NewObjectID()
+	nextLevel := primitive.NewObjectID()
+
+	flaggedRef := pendingRef(alreadyFlagged, "stu-1", "advisor", now.Add(-80*time.Hour))
+	flaggedRef.OverdueAt = &now
+
+	// disubmit lama, tapi baru naik ke tahap faculty → SLA tahap ini belum lewat
+	enteredAt := now.Add(-10 * time.Hour)
+	nextLevelRef := pendingRef(nextLevel, "stu-1", "faculty", now.Add(-200*time.Hour))
+	nextLevelRef.LevelEnteredAt = &enteredAt
+
+	f := newReviewFixture()
+	f.refRepo.On("GetPending", mock.Anything, mock.Anything).Return([]*model.AchievementReference{
+		pendingRef(fresh, "stu-1", "advisor", now.Add(-10*time.Hour)),
+		pendingRef(overdue, "stu-1", "advisor", now.Add(-80*time.Hour)),
+		pendingRef(escalate, "stu-1", "faculty", now.Add(-200*time.Hour)),
+		flaggedRef,
+		nextLevelRef,
+	}, nil)
+	f.achRepo.On("FindByIDs", mock.Anything, mock.Anything).Return([]model.Achievement{
+		{ID: fresh, StudentID: "stu-1", AchievementType: "academic"},
+		// prestasi tim: pengingat ke dosen wali semua anggota
+		{ID: overdue, StudentID: "stu-1", AchievementType: "academic", Members: []model.AchievementMember{
+			{StudentID: "stu-1", Role: model.MemberRoleLeader},
+			{StudentID: "stu-3", Role: model.MemberRoleMember},
+		}},
+		{ID: escalate, StudentID: "stu-1", AchievementType: "academic"},
+		{ID: alreadyFlagged, StudentID: "stu-1", AchievementType: "academic"},
+		{ID: nextLevel, StudentID: "stu-1", AchievementType: "academic"},
+	}, nil)
+	f.refRepo.On("MarkOverdue", mock.Anything, mock.Anything).Return(true, nil)
+	f.refRepo.On("MarkEscalated", mock.Anything, mock.Anything).Return(true, nil)
+	f.stuRepo.On("GetStudentByID", mock.Anything, "stu-1").Return(&model.Student{ID: "stu-1", AdvisorID: "lec-1"}, nil)
+	f.lecRepo.On("GetLecturerByID", mock.Anything, "lec-1").Return(&model.Lecturer{ID: "lec-1", UserID: "lec-user"}, nil)
+	f.stuRepo.On("GetStudentByID", mock.Anything, "stu-3").Return(&model.Student{ID: "stu-3", AdvisorID: "lec-3"}, nil)
+	f.lecRepo.On("GetLecturerByID", mock.Anything, "lec-3").Return(&model.Lecturer{ID: "lec-3", UserID: "lec3-user"}, nil)
+	f.userRepo.On("GetAllUsers", mock.Anything).Return([]*model.User{
+		{ID: "admin-1", RoleName: "Admin", IsActive: true},
+		{ID: "admin-2", RoleName: "Admin", IsActive: false},
+		{ID: "user-1", RoleName: "Mahasiswa", IsActive: true},
+	}, nil)
+	f.notifRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	report, err := f.svc.RunSLACheck(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ReviewSLAReport{Checked: 5, Overdue: 2, Escalated: 1}, report)
+
+	f.refRepo.AssertNotCalled(t, "MarkOverdue", mock.Anything, fresh.Hex())
+	f.refRepo.AssertNotCalled(t, "MarkOverdue", mock.Anything, alreadyFlagged.Hex())
+	f.refRepo.AssertNotCalled(t, "MarkOverdue", mock.Anything, nextLevel.Hex())
+	f.refRepo.AssertNotCalled(t, "MarkEscalated", mock.Anything, nextLevel.Hex())
+
+	// advisor level → dosen wali; faculty level → admin aktif (pengingat + eskalasi)
+	f.notifRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(n *model.Notification) bool {
+		return n.UserID == "lec-user" && n.Type == model.NotificationReviewReminder && n.AchievementID == overdue.Hex()
+	}))
+	f.notifRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(n *model.Notification) bool {
+		return n.UserID == "lec3-user" && n.Type == model.NotificationReviewReminder && n.AchievementID == overdue.Hex()
+	}))
+	f.notifRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(n *model.Notification) bool {
+		return n.UserID == "admin-1" && n.Type == model.NotificationReviewEscalated && n.AchievementID == escalate.Hex()
+	}))
+	f.notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.MatchedBy(func(n *model.Notification) bool {
+		return n.UserID == "admin-2"
+	}))
+	f.notifRepo.AssertNumberOfCalls(t, "Create", 4)
+}
+
+func TestReviewSLAs_Invalid(t *testing.T) {
+	_, err := service.NewReviewSLAs([]model.ReviewSLA{{AchievementType: "competition", DueHours: 24, EscalateHours: 48}})
+	assert.Error(t, err, "default entry required")
+
+	_, err = service.NewReviewSLAs([]model.ReviewSLA{{DueHours: 48, EscalateHours: 24}})
+	assert.Error(t, err, "escalate must be after due")
+}
@@ -138,7 +138,7 @@ func TestCreateUser_Success(t *testing.T) {
 	studentRepo := new(MockStudentRepoUserSvc)
 	lecturerRepo := new(MockLecturerRepoUserSvc)
 
-	svc := service.NewUserService(userRepo, studentRepo, lecturerRepo)
+	svc := service.NewUserService(userRepo, studentRepo, lecturerRepo, nil)
 
 	req := model.CreateUserRequest{
 		Username: "john",
@@ -167,7 +167,7 @@ func TestCreateUser_Success(t *testing.T) {
 
 func TestCreateUser_Duplicate(t *testing.T) {
 	userRepo := new(MockUserRepoUserSvc)
-	svc := service.NewUserService(userRepo, nil, nil)
+	svc := service.NewUserService(userRepo, nil, nil, nil)
 
 	req := model.CreateUserRequest{
 		Username: "john",
@@ -184,7 +184,7 @@ func TestCreateUser_Duplicate(t *testing.T) {
 }
 
 func TestAssignRole_EmptyRole(t *testing.T) {
-	svc := service.NewUserService(nil, nil, nil)
+	svc := service.NewUserService(nil, nil, nil, nil)
 
 	err := svc.(*service.UserService).AssignRoleLogic(context.Background(), "1", "")
 
@@ -193,7 +193,7 @@ func TestAssignRole_EmptyRole(t *testing.T) {
 
 func TestAssignRole_Success(t *testing.T) {
 	userRepo := new(MockUserRepoUserSvc)
-	svc := service.NewUserService(userRepo, nil, nil)
+	svc := service.NewUserService(userRepo, nil, nil, nil)
 
 	userRepo.On("AssignRole", mock.Anything, "1", "role-1").Return(nil)
 
@@ -207,8 +207,9 @@ func TestGetUserByID_WithProfiles(t *testing.T) {
 	userRepo := new(MockUserRepoUserSvc)
 	studentRepo := new(MockStudentRepoUserSvc)
 	lecturerRepo := new(MockLecturerRepoUserSvc)
+	achRepo := new(MockAchievementRepo)
 
-	svc := service.NewUserService(userRepo, studentRepo, lecturerRepo)
+	svc := service.NewUserService(userRepo, studentRepo, lecturerRepo, achRepo)
 
 	userRepo.On("GetUserByID", mock.Anything, "1").
 		Return(&model.User{
@@ -219,7 +220,10 @@ func TestGetUserByID_WithProfiles(t *testing.T) {
 		}, nil)
 
 	studentRepo.On("GetStudentProfile", mock.Anything, "1").
-		Return(&model.Student{StudentID: "S001"}, nil)
+		Return(&model.Student{ID: "stu-1", StudentID: "S001"}, nil)
+
+	achRepo.On("StorageByStudent", mock.Anything, []string{"stu-1"}, int64(1)).
+		Return([]model.StorageUsage{{StudentID: "stu-1", UsedBytes: 3 << 20, Attachments: 2, Achievements: 1}}, nil)
 
 	lecturerRepo.On("GetLecturerProfile", mock.Anything, "1").
 		Return(nil, errors.New("not lecturer"))
@@ -229,11 +233,37 @@ func TestGetUserByID_WithProfiles(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "john", resp.Username)
 	assert.NotNil(t, resp.Student)
+	if assert.NotNil(t, resp.StorageUsage) {
+		assert.Equal(t, int64(3<<20), resp.StorageUsage.UsedBytes)
+		assert.Equal(t, int64(500<<20), resp.StorageUsage.QuotaBytes)
+	}
+}
+
+func TestGetUserByID_StorageUsageEmpty(t *testing.T) {
+	userRepo := new(MockUserRepoUserSvc)
+	studentRepo := new(MockStudentRepoUserSvc)
+	lecturerRepo := new(MockLecturerRepoUserSvc)
+	achRepo := new(MockAchievementRepo)
+
+	svc := service.NewUserService(userRepo, studentRepo, lecturerRepo, achRepo)
+
+	userRepo.On("GetUserByID", mock.Anything, "1").Return(&model.User{ID: "1"}, nil)
+	studentRepo.On("GetStudentProfile", mock.Anything, "1").Return(&model.Student{ID: "stu-1"}, nil)
+	lecturerRepo.On("GetLecturerProfile", mock.Anything, "1").Return(nil, errors.New("not lecturer"))
+	achRepo.On("StorageByStudent", mock.Anything, []string{"stu-1"}, int64(1)).Return([]model.StorageUsage{}, nil)
+
+	resp, err := svc.(*service.UserService).GetUserByID(context.Background(), "1")
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, resp.StorageUsage) {
+		assert.Equal(t, "stu-1", resp.StorageUsage.StudentID)
+		assert.Zero(t, resp.StorageUsage.UsedBytes)
+	}
 }
 
 func TestDeleteUser_Success(t *testing.T) {
 	userRepo := new(MockUserRepoUserSvc)
-	svc := service.NewUserService(userRepo, nil, nil)
+	svc := service.NewUserService(userRepo, nil, nil, nil)
 
 	userRepo.On("SoftDeleteUser", mock.Anything, "1").Return(nil)
 
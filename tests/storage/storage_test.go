@@ -0,0 +1,149 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"uas-backend/app/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3: pengganti MinIO di memori (path-style, cek header Signature V4)
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3(bucket string) *fakeS3 {
+	return &fakeS3{bucket: bucket, objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=minio/") ||
+		!strings.Contains(auth, "/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") ||
+		r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		if r.ContentLength < 0 {
+			http.Error(w, "MissingContentLength", http.StatusLengthRequired)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodHead, http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		// ServeContent menangani header Range seperti S3
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func roundTrip(t *testing.T, store storage.Storage, key string) {
+	ctx := context.Background()
+
+	exists, err := store.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, store.Put(ctx, key, strings.NewReader("%PDF-1.4"), 8, "application/pdf"))
+
+	exists, err = store.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	r, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "%PDF-1.4", string(data))
+
+	r, err = store.GetRange(ctx, key, 1, 3)
+	require.NoError(t, err)
+	data, _ = io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "PDF", string(data))
+
+	require.NoError(t, store.Delete(ctx, key))
+	require.NoError(t, store.Delete(ctx, key), "delete key yang tidak ada bukan error")
+
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestLocalStorage(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	roundTrip(t, store, "achievements/abc/1-sertifikat.pdf")
+
+	for _, key := range []string{"", "../etc/passwd", "a//b", "/abs", `a\b`} {
+		assert.ErrorIs(t, store.Put(context.Background(), key, strings.NewReader("x"), 1, ""), storage.ErrInvalidKey, key)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	fake := newFakeS3("prestasi")
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	store, err := storage.New(storage.Config{
+		Driver: storage.DriverS3,
+		S3: storage.S3Config{
+			Endpoint:  srv.URL,
+			Bucket:    "prestasi",
+			AccessKey: "minio",
+			SecretKey: "minio-secret",
+			PathStyle: true,
+		},
+	})
+	require.NoError(t, err)
+
+	key := "achievements/abc/1-sertifikat juara.pdf"
+	roundTrip(t, store, key)
+
+	require.NoError(t, store.Put(context.Background(), key, strings.NewReader("x"), 1, "application/pdf"))
+	assert.Equal(t, "application/pdf", fake.types[key])
+}
+
+func TestStorageConfig(t *testing.T) {
+	_, err := storage.New(storage.Config{Driver: "ftp"})
+	assert.Error(t, err)
+
+	_, err = storage.New(storage.Config{Driver: storage.DriverS3, S3: storage.S3Config{Endpoint: "minio:9000", Bucket: "b", AccessKey: "a", SecretKey: "s"}})
+	assert.Error(t, err, "endpoint tanpa skema")
+
+	_, err = storage.New(storage.Config{Driver: storage.DriverS3, S3: storage.S3Config{Endpoint: "http://minio:9000"}})
+	assert.Error(t, err, "bucket & kredensial wajib")
+}